@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportAdoption prints, for every library package in args, the main
+// packages reaching it with the packages of their closure importing it
+// directly, then each of those importers with the main packages it brings
+// the library into: the code to fix, and who ships it, before a breaking
+// change of the library.
+func reportAdoption(dg *depgraph.DepGraph, args []string) {
+	for _, lib := range args {
+		adopters := dg.Adopters(lib)
+		if len(adopters) == 0 {
+			warn(exitNotFound, "%v not found", lib)
+			continue
+		}
+		mainsOf := make(map[string][]string)
+		for _, a := range adopters {
+			for _, p := range a.Importers {
+				mainsOf[p] = append(mainsOf[p], a.Main)
+			}
+		}
+		importers := make([]string, 0, len(mainsOf))
+		for p := range mainsOf {
+			importers = append(importers, p)
+		}
+		sort.Strings(importers)
+		fmt.Printf("%s%s: %d main packages, %d direct importers\n", lib, ownerNote(lib), len(adopters), len(importers))
+		fmt.Println("  by main package:")
+		for _, a := range adopters {
+			fmt.Printf("\t%s%s: %s\n", a.Main, ownerNote(a.Main), strings.Join(a.Importers, ", "))
+		}
+		fmt.Println("  by importer:")
+		for _, p := range importers {
+			fmt.Printf("\t%s%s: %s\n", p, ownerNote(p), strings.Join(mainsOf[p], ", "))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"go/types"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+var rulesFile string
+
+// Analyzer checks the package being analyzed against the ruleset given by
+// its -rules flag. Violations are reported on the import declaration that
+// starts the offending chain.
+var Analyzer = &analysis.Analyzer{
+	Name: "deprules",
+	Doc:  "check imports against the dependency rules in a JSON ruleset",
+	Run:  run,
+}
+
+func init() {
+	Analyzer.Flags.StringVar(&rulesFile, "rules", "", "JSON ruleset, see github.com/ma6174/go_dep_search/rules")
+}
+
+var (
+	loadOnce    sync.Once
+	loadedRules *rules.Ruleset
+	loadErr     error
+)
+
+// loadRules reads the ruleset once; packages are analyzed concurrently.
+func loadRules() (*rules.Ruleset, error) {
+	loadOnce.Do(func() {
+		if rulesFile == "" {
+			return
+		}
+		f, err := os.Open(rulesFile)
+		if err != nil {
+			loadErr = err
+			return
+		}
+		defer f.Close()
+		loadedRules, loadErr = rules.Load(f)
+	})
+	return loadedRules, loadErr
+}
+
+// graphOf builds the slice of the dependency graph reachable from pkg.
+func graphOf(pkg *types.Package) *depgraph.DepGraph {
+	dg := &depgraph.DepGraph{}
+	seen := map[*types.Package]bool{pkg: true}
+	queue := []*types.Package{pkg}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, imp := range p.Imports() {
+			dg.AddEdge(p.Path(), imp.Path())
+			if !seen[imp] {
+				seen[imp] = true
+				queue = append(queue, imp)
+			}
+		}
+	}
+	return dg
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	rs, err := loadRules()
+	if err != nil || rs == nil {
+		return nil, err
+	}
+	dg := graphOf(pass.Pkg)
+	for _, v := range rules.Evaluate(dg, rs) {
+		if v.Package != pass.Pkg.Path() || len(v.Chain) < 2 {
+			continue
+		}
+		for _, f := range pass.Files {
+			for _, spec := range f.Imports {
+				if path, _ := strconv.Unquote(spec.Path.Value); path == v.Chain[1] {
+					pass.Reportf(spec.Pos(), "%s: %s", v.Rule.Name, strings.Join(v.Chain, " -> "))
+				}
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const src = `package a
+
+import (
+	_ "example.com/lib"
+	_ "fmt"
+)
+`
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+func TestAnalyzer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "analyzer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "rules.json")
+	err = ioutil.WriteFile(fn, []byte(`{"rules": [
+		{"name": "no-legacy", "from": ["example.com/cmd/..."], "deny": ["example.com/legacy/..."]}
+	]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Analyzer.Flags.Set("rules", fn); err != nil {
+		t.Fatal(err)
+	}
+
+	legacy := types.NewPackage("example.com/legacy/db", "db")
+	legacy.MarkComplete()
+	lib := types.NewPackage("example.com/lib", "lib")
+	lib.SetImports([]*types.Package{legacy})
+	lib.MarkComplete()
+	fmtPkg := types.NewPackage("fmt", "fmt")
+	fmtPkg.MarkComplete()
+	deps := map[string]*types.Package{lib.Path(): lib, fmtPkg.Path(): fmtPkg}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "a.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := types.Config{Importer: importerFunc(func(path string) (*types.Package, error) { return deps[path], nil })}
+	info := &types.Info{}
+	pkg, err := conf.Check("example.com/cmd/a", fset, []*ast.File{f}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	pass := &analysis.Pass{
+		Analyzer:  Analyzer,
+		Fset:      fset,
+		Files:     []*ast.File{f},
+		Pkg:       pkg,
+		TypesInfo: info,
+		Report: func(d analysis.Diagnostic) {
+			got = append(got, fset.Position(d.Pos).String()+": "+d.Message)
+		},
+	}
+	if _, err := Analyzer.Run(pass); err != nil {
+		t.Fatal(err)
+	}
+	expect := "a.go:4:2: no-legacy: example.com/cmd/a -> example.com/lib -> example.com/legacy/db"
+	if len(got) != 1 || got[0] != expect {
+		t.Error(got)
+	}
+}
@@ -0,0 +1,7 @@
+// Package analyzer reports import rule violations (see package rules)
+// through the go/analysis framework, so `go vet -vettool` based pipelines
+// check dependency policy along with everything else.
+//
+// It is a module of its own: it depends on golang.org/x/tools, which the
+// rest of go_dep_search doesn't need.
+package analyzer
@@ -0,0 +1,10 @@
+module github.com/ma6174/go_dep_search/analyzer
+
+go 1.26.0
+
+require (
+	github.com/ma6174/go_dep_search v0.0.0-00010101000000-000000000000
+	golang.org/x/tools v0.50.0
+)
+
+replace github.com/ma6174/go_dep_search => ../
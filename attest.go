@@ -0,0 +1,63 @@
+package main
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"io/ioutil"
+	"os"
+
+	"github.com/ma6174/go_dep_search/attest"
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// writeAttestations writes one signed attestation of the dependency
+// closure per main package in args, or of all, one DSSE envelope per
+// line, signed with the PKCS#8 PEM private key in *attestKey.
+func writeAttestations(dg *depgraph.DepGraph) {
+	signer, keyID := loadSigningKey(*attestKey)
+	enc := json.NewEncoder(os.Stdout)
+	for _, m := range mainsOrAll(dg, flag.Args()) {
+		if !dg.IsMainPackage(m) {
+			fail(exitNotFound, "%v is not a main package", m)
+		}
+		e, err := attest.Sign(attest.NewStatement(dg, m), signer, keyID)
+		if err != nil {
+			fatalln("sign attestation failed", err)
+		}
+		if err := enc.Encode(e); err != nil {
+			fatalln(err)
+		}
+	}
+}
+
+// loadSigningKey reads a PKCS#8 PEM private key, as written by
+// openssl genpkey, its key id is the SHA-256 of its public key.
+func loadSigningKey(file string) (crypto.Signer, string) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		fatalln(err)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		fatalf("%v: no PEM data", file)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		fatalln(file, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		fatalf("%v: unsupported key type %T", file, key)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		fatalln(file, err)
+	}
+	sum := sha256.Sum256(pub)
+	return signer, hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,178 @@
+// Package attest writes in-toto attestations of the dependency closure of
+// main packages, signed in DSSE envelopes, so a supply-chain pipeline can
+// check a dependency report was not altered after it was generated.
+package attest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+const (
+	StatementType = "https://in-toto.io/Statement/v1"
+	PredicateType = "https://github.com/ma6174/go_dep_search/attestation/deps/v1"
+	PayloadType   = "application/vnd.in-toto+json"
+)
+
+// Statement is an in-toto statement about a main package.
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Deps      `json:"predicate"`
+}
+
+// Subject names the main package. Its digest is the SHA-256 of the
+// dependency lines of the predicate, see Deps.Digest, as the binary itself
+// is not known.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Deps is the predicate: the dependency closure of a main package.
+type Deps struct {
+	Main         string                `json:"main"`
+	Build        depgraph.BuildProfile `json:"build"`
+	Dependencies []Dependency          `json:"dependencies"` // sorted by package
+}
+
+// Dependency is a package of the closure.
+type Dependency struct {
+	Package string `json:"package"`
+	Module  string `json:"module,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Digest returns the hex SHA-256 of one "package module@version" line per
+// dependency.
+func (d *Deps) Digest() string {
+	var b strings.Builder
+	for _, dep := range d.Dependencies {
+		b.WriteString(dep.Package)
+		if dep.Module != "" {
+			b.WriteString(" " + dep.Module + "@" + dep.Version)
+		}
+		b.WriteString("\n")
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewStatement returns the statement of the dependency closure of main.
+func NewStatement(g *depgraph.DepGraph, main string) *Statement {
+	deps := Deps{Main: main, Build: g.Build(), Dependencies: []Dependency{}}
+	for _, p := range g.Deps(main) {
+		dep := Dependency{Package: p}
+		if m := g.Module(p); m != nil {
+			dep.Module, dep.Version = m.Path, m.Version
+			if m.Replace != nil {
+				dep.Module, dep.Version = m.Replace.Path, m.Replace.Version
+			}
+		}
+		deps.Dependencies = append(deps.Dependencies, dep)
+	}
+	return &Statement{
+		Type:          StatementType,
+		Subject:       []Subject{{Name: main, Digest: map[string]string{"sha256": deps.Digest()}}},
+		PredicateType: PredicateType,
+		Predicate:     deps,
+	}
+}
+
+// Envelope is a DSSE envelope.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"` // base64
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of an Envelope.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"` // base64
+}
+
+// pae is the DSSE pre-authentication encoding of the payload.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// Sign signs s with signer, an ed25519, ECDSA or RSA private key.
+func Sign(s *Statement, signer crypto.Signer, keyID string) (*Envelope, error) {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	msg := pae(PayloadType, payload)
+	var sig []byte
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		sig, err = signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(msg)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// ErrBadSignature is returned by Verify for envelopes no signature of
+// which matches the key.
+var ErrBadSignature = errors.New("attest: signature mismatch")
+
+// Verify checks e is signed by the private key of pub and returns its
+// statement.
+func Verify(e *Envelope, pub crypto.PublicKey) (*Statement, error) {
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, err
+	}
+	msg := pae(e.PayloadType, payload)
+	digest := sha256.Sum256(msg)
+	verified := false
+	for _, s := range e.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		switch pub := pub.(type) {
+		case ed25519.PublicKey:
+			verified = ed25519.Verify(pub, msg, sig)
+		case *ecdsa.PublicKey:
+			verified = ecdsa.VerifyASN1(pub, digest[:], sig)
+		case *rsa.PublicKey:
+			verified = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) == nil
+		default:
+			return nil, fmt.Errorf("attest: unsupported key type %T", pub)
+		}
+		if verified {
+			break
+		}
+	}
+	if !verified || e.PayloadType != PayloadType {
+		return nil, ErrBadSignature
+	}
+	var s Statement
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
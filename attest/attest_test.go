@@ -0,0 +1,63 @@
+package attest
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func testGraph() *depgraph.DepGraph {
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/cmd/a", Name: "main",
+		Imports: []string{"github.com/lib/pq", "fmt"}, Deps: []string{"fmt", "github.com/lib/pq"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "github.com/lib/pq",
+		Module: &depgraph.Module{Path: "github.com/lib/pq", Version: "v1.10.0"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "fmt", Standard: true})
+	return dg
+}
+
+func TestNewStatement(t *testing.T) {
+	s := NewStatement(testGraph(), "example.com/cmd/a")
+	if s.Type != StatementType || s.Subject[0].Name != "example.com/cmd/a" || len(s.Predicate.Dependencies) != 2 {
+		t.Fatal(s)
+	}
+	if dep := s.Predicate.Dependencies[1]; dep.Package != "github.com/lib/pq" || dep.Version != "v1.10.0" {
+		t.Error(dep)
+	}
+	if s.Subject[0].Digest["sha256"] != s.Predicate.Digest() || len(s.Predicate.Digest()) != 64 {
+		t.Error(s.Subject)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	s := NewStatement(testGraph(), "example.com/cmd/a")
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	for name, signer := range map[string]struct {
+		env    func() (*Envelope, error)
+		public interface{}
+	}{
+		"ed25519": {func() (*Envelope, error) { return Sign(s, edKey, "ed") }, edPub},
+		"ecdsa":   {func() (*Envelope, error) { return Sign(s, ecKey, "ec") }, &ecKey.PublicKey},
+	} {
+		e, err := signer.env()
+		if err != nil {
+			t.Fatal(name, err)
+		}
+		got, err := Verify(e, signer.public)
+		if err != nil || got.Predicate.Main != "example.com/cmd/a" || len(got.Predicate.Dependencies) != 2 {
+			t.Error(name, got, err)
+		}
+		payload, _ := base64.StdEncoding.DecodeString(e.Payload)
+		payload[len(payload)-2] ^= 1
+		e.Payload = base64.StdEncoding.EncodeToString(payload)
+		if _, err := Verify(e, signer.public); err != ErrBadSignature {
+			t.Error(name, "altered payload should not verify", err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// baseline is the graph given by -baseline, results are marked NEW or
+// EXISTING against it.
+var baseline struct {
+	dg    *depgraph.DepGraph
+	users map[string]map[string]bool // dep -> packages depending on it
+}
+
+// loadBaseline loads *baselineFile the way the input is loaded.
+func loadBaseline() {
+	f, err := os.Open(*baselineFile)
+	if err != nil {
+		fail(exitParse, "open baseline failed %v", err)
+	}
+	defer f.Close()
+	dg, err := newGraph(buildProfile())
+	if err != nil {
+		fail(exitUsage, "%v", err)
+	}
+	if err := dg.Load(f); err != nil {
+		fail(exitParse, "load baseline failed %v", err)
+	}
+	if *goModFile != "" {
+		if err := applyGoMod(dg); err != nil {
+			fail(exitParse, "load baseline failed %v", err)
+		}
+	}
+	baseline.dg = prepareGraph(dg)
+	baseline.users = make(map[string]map[string]bool)
+	log.Printf("successfully load %d baseline packages", baseline.dg.CountAll())
+}
+
+// existed reports whether p already depended on dep in the baseline, or
+// was dep itself.
+func existed(p, dep string) bool {
+	users, ok := baseline.users[dep]
+	if !ok {
+		users = make(map[string]bool)
+		for _, u := range append(baseline.dg.SearchAll(dep), baseline.dg.SearchTest(dep)...) {
+			users[u] = true
+		}
+		baseline.users[dep] = users
+	}
+	if p == dep {
+		return baseline.dg.Exists(dep)
+	}
+	return users[p]
+}
+
+// mark returns the NEW or EXISTING prefix of the result "p depends on
+// dep", or "" without -baseline.
+func mark(p, dep string) string {
+	if baseline.dg == nil {
+		return ""
+	}
+	if existed(p, dep) {
+		return "EXISTING "
+	}
+	return "NEW "
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportHeaviest ranks the direct third-party imports of the main
+// packages given as args, or of every main package, by the packages each
+// alone brings into the binary.
+func reportHeaviest(dg *depgraph.DepGraph, mains []string) {
+	for _, m := range mainsOrAll(dg, mains) {
+		if !dg.IsMainPackage(m) {
+			log.Printf("%v is not a main package", m)
+			continue
+		}
+		fmt.Println(m + ":")
+		for _, w := range dg.HeaviestDeps(m) {
+			fmt.Printf("\t%s +%d (of %d)\n", w.Import, w.Exclusive, w.Total)
+		}
+	}
+}
+
+// reportUniqueDeps prints, per main package, the packages no other main
+// package depends on.
+func reportUniqueDeps(dg *depgraph.DepGraph) {
+	unique := dg.UniqueDeps()
+	mains := make([]string, 0, len(unique))
+	for m := range unique {
+		mains = append(mains, m)
+	}
+	sort.Strings(mains)
+	for _, m := range mains {
+		var shown []string
+		for _, p := range unique[m] {
+			if showPackage(dg, p) {
+				shown = append(shown, p)
+			}
+		}
+		fmt.Printf("%s (%d):\n", m, len(shown))
+		for _, p := range shown {
+			printPackage(dg, p, nil, "\t"+p+ownerNote(p))
+		}
+	}
+}
+
+// reportOverlaps prints the dependency overlap of every pair of main
+// packages, most similar first.
+func reportOverlaps(dg *depgraph.DepGraph) {
+	for _, o := range dg.Overlaps() {
+		fmt.Printf("%.2f %s %s (%d shared of %d)\n", o.Jaccard, o.A, o.B, o.Shared, o.Union)
+	}
+}
+
+// reportTop prints the *top most depended on first-party and third-party
+// packages with their number of transitive dependents.
+func reportTop(dg *depgraph.DepGraph) {
+	firstParty, thirdParty := dg.MostDepended()
+	for _, list := range []struct {
+		name    string
+		entries []depgraph.Popularity
+	}{{"first-party", firstParty}, {"third-party", thirdParty}} {
+		fmt.Println(list.name + ":")
+		for i, p := range list.entries {
+			if i == *top {
+				break
+			}
+			fmt.Printf("\t%d %s\n", p.Dependents, p.Package)
+		}
+	}
+}
+
+// mainsOrAll returns mains, or every main package of dg if it's empty.
+func mainsOrAll(dg *depgraph.DepGraph, mains []string) []string {
+	if len(mains) == 0 {
+		for _, p := range dg.Packages() {
+			if dg.IsMainPackage(p) {
+				mains = append(mains, p)
+			}
+		}
+	}
+	return mains
+}
+
+// depthReport is the depth distribution of the deps of one main package.
+type depthReport struct {
+	Main   string
+	Depths []int // Depths[d] packages are d imports away, see DepthHistogram
+	Max    int
+	Mean   float64 // mean depth of the deps, the main package left out
+}
+
+// reportDepths prints the depth histogram of the deps of the main
+// packages given as args, or of every main package, as text or JSON
+// depending on *depths.
+func reportDepths(dg *depgraph.DepGraph, mains []string) {
+	var reports []depthReport
+	for _, m := range mainsOrAll(dg, mains) {
+		h := dg.DepthHistogram(m)
+		if h == nil {
+			warn(exitNotFound, "%v not found", m)
+			continue
+		}
+		r := depthReport{Main: m, Depths: h, Max: len(h) - 1}
+		n := 0
+		for d, count := range h[1:] {
+			n += count
+			r.Mean += float64((d + 1) * count)
+		}
+		if n > 0 {
+			r.Mean /= float64(n)
+		}
+		reports = append(reports, r)
+	}
+	switch *depths {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			fatalln("encode depths failed", err)
+		}
+	case "text":
+		for _, r := range reports {
+			fmt.Printf("%s (max depth %d, mean %.2f):\n", r.Main, r.Max, r.Mean)
+			widest := 0
+			for _, count := range r.Depths[1:] {
+				if count > widest {
+					widest = count
+				}
+			}
+			for d, count := range r.Depths[1:] {
+				fmt.Printf("\t%3d | %5d %s\n", d+1, count, strings.Repeat("#", (count*50+widest-1)/widest))
+			}
+		}
+	default:
+		fail(exitUsage, "unknown depths format %v, supported: text,json", *depths)
+	}
+}
+
+// reportExternal prints which share of the packages each main package
+// given as args, or every main package, is built from is third-party.
+func reportExternal(dg *depgraph.DepGraph, mains []string) {
+	for _, m := range mainsOrAll(dg, mains) {
+		thirdParty, total := dg.ThirdPartyShare(m)
+		if total == 0 {
+			warn(exitNotFound, "%v not found", m)
+			continue
+		}
+		fmt.Printf("%s: %d%% of packages are external (%d of %d)\n", m, thirdParty*100/total, thirdParty, total)
+	}
+}
+
+// reportNameCollisions prints, per main package given as args or every
+// main package, the package names several of its deps share.
+func reportNameCollisions(dg *depgraph.DepGraph, mains []string) {
+	for _, m := range mainsOrAll(dg, mains) {
+		for _, c := range dg.NameCollisions(m) {
+			var shown []string
+			for _, p := range c.Packages {
+				if showPackage(dg, p) {
+					shown = append(shown, p)
+				}
+			}
+			if len(shown) > 1 {
+				fmt.Printf("%s: %d packages named %s: %s\n", m, len(shown), c.Name, strings.Join(shown, " "))
+			}
+		}
+	}
+}
+
+// reportOnlyVia prints, per main package depending on *via, the packages
+// it only reaches through it.
+func reportOnlyVia(dg *depgraph.DepGraph) {
+	mains := dg.SearchMain(*via)
+	if len(mains) == 0 {
+		log.Printf("no main package depends on %v", *via)
+	}
+	sort.Strings(mains)
+	for _, m := range mains {
+		var shown []string
+		for _, p := range dg.OnlyVia(m, *via) {
+			if showPackage(dg, p) {
+				shown = append(shown, p)
+			}
+		}
+		fmt.Printf("%s: dropping %s removes %d more packages\n", m, *via, len(shown))
+		for _, p := range shown {
+			printPackage(dg, p, nil, "\t"+p+ownerNote(p))
+		}
+	}
+}
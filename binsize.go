@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/ma6174/go_dep_search/binsize"
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportBinSize attributes the size of the binary of *binSizeFile to the
+// packages of its main package, the arg or else the only main package of
+// the graph: per package, largest first, the bytes gone without it, its
+// own bytes and the chain bringing it in.
+func reportBinSize(dg *depgraph.DepGraph) {
+	sizes, err := binsize.Load(*binSizeFile)
+	if err != nil {
+		fail(exitParse, "load binary size failed %v", err)
+	}
+	mains := mainsOrAll(dg, flag.Args())
+	if len(mains) != 1 || !dg.IsMainPackage(mains[0]) {
+		fail(exitUsage, "-binsize needs the main package of the binary as arg, found %v", mains)
+	}
+	m := mains[0]
+	subtrees := dg.SizeSubtrees(m, sizes)
+	var attributed int64
+	if len(subtrees) > 0 {
+		attributed = subtrees[0].Subtree
+	}
+	log.Printf("%s: %s of symbols, %s attributed to its packages, %s to linker generated symbols",
+		m, formatBytes(sizes.Total()), formatBytes(attributed), formatBytes(sizes[""]))
+	for _, s := range subtrees {
+		if s.Subtree == 0 || !showPackage(dg, s.Package) {
+			continue
+		}
+		line := fmt.Sprintf("%9s %9s  %s", formatBytes(s.Subtree), formatBytes(s.Own), s.Package)
+		if chains := dg.SearchChainFrom([]string{m}, s.Package); len(chains) == 1 && len(chains[0]) > 1 {
+			line += ": " + formatChain(dg, chains[0])
+		}
+		fmt.Println(line)
+	}
+}
+
+// formatBytes prints n in B, KB or MB.
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1fMB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1fKB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%dB", n)
+}
@@ -0,0 +1,158 @@
+// Package binsize reads the size of a Go binary by package, from the
+// symbol table of the binary or from a size report, to attribute it to
+// the dependencies of the binary, see depgraph.SizeSubtrees.
+package binsize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Sizes are bytes by package import path. Symbols of the main package are
+// under "main", those no package can be told for, eg: go:func.*, under "".
+type Sizes map[string]int64
+
+// Total returns the bytes of all packages.
+func (s Sizes) Total() (n int64) {
+	for _, size := range s {
+		n += size
+	}
+	return
+}
+
+// SymbolPackage returns the import path of the package of a Go symbol,
+// eg: "github.com/x/y" for "github.com/x/y.(*T).M" and
+// "type:*github.com/x/y.T", or "" for linker generated symbols.
+func SymbolPackage(sym string) string {
+	sym = strings.TrimPrefix(sym, "type:")
+	sym = strings.TrimLeft(sym, "*")
+	if sym == "" || strings.HasPrefix(sym, "go:") || strings.HasPrefix(sym, "$") || strings.HasPrefix(sym, ".") {
+		return ""
+	}
+	// the package path ends at the first dot after its last slash, which
+	// comes before any type argument or receiver; the linker escapes the
+	// dots of the last element, eg: gopkg.in/yaml%2ev3
+	head := sym
+	if i := strings.IndexAny(head, "[( "); i >= 0 {
+		head = head[:i]
+	}
+	slash := strings.LastIndex(head, "/")
+	dot := strings.Index(head[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	if p, err := url.PathUnescape(sym[:slash+1+dot]); err == nil {
+		return p
+	}
+	return sym[:slash+1+dot]
+}
+
+var nmLine = regexp.MustCompile(`^\s*[0-9a-fA-F]*\s+(\d+)\s+(\S)\s+(.+)$`)
+
+// ParseNm reads the output of go tool nm -size. Uninitialized data, of
+// type B, takes no room in the file and is left out.
+func ParseNm(r io.Reader) (Sizes, error) {
+	sizes := make(Sizes)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		m := nmLine.FindStringSubmatch(sc.Text())
+		if m == nil {
+			if strings.TrimSpace(sc.Text()) != "" {
+				return nil, fmt.Errorf("binsize: not go tool nm -size output: %q", sc.Text())
+			}
+			continue
+		}
+		switch m[2] {
+		case "U", "B", "b": // undefined, from a shared library, or bss
+			continue
+		}
+		size, _ := strconv.ParseInt(m[1], 10, 64)
+		sizes[SymbolPackage(m[3])] += size
+	}
+	return sizes, sc.Err()
+}
+
+// ParseBloaty reads a bloaty -d symbols --csv report, using its filesize
+// column, or vmsize without one.
+func ParseBloaty(r io.Reader) (Sizes, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("binsize: empty bloaty report")
+	}
+	col := -1
+	for i, name := range records[0] {
+		if name == "filesize" || name == "vmsize" && col < 0 {
+			col = i
+		}
+	}
+	if col < 0 {
+		return nil, fmt.Errorf("binsize: no filesize or vmsize column in bloaty report")
+	}
+	sizes := make(Sizes)
+	for _, rec := range records[1:] {
+		size, err := strconv.ParseInt(rec[col], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("binsize: bad size %q of %v", rec[col], rec[0])
+		}
+		sizes[SymbolPackage(rec[0])] += size
+	}
+	return sizes, nil
+}
+
+// Nm returns the sizes of the symbols of binary, running go tool nm.
+func Nm(binary string) (Sizes, error) {
+	out, err := exec.Command("go", "tool", "nm", "-size", binary).Output()
+	if err != nil {
+		if e, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("go tool nm %v: %v: %s", binary, err, e.Stderr)
+		}
+		return nil, err
+	}
+	return ParseNm(bytes.NewReader(out))
+}
+
+// Load reads file: a binary, a go tool nm -size output or a bloaty CSV
+// report, told apart by their content.
+func Load(file string) (Sizes, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	head, _ := br.Peek(64)
+	switch {
+	case isBinary(head):
+		return Nm(file)
+	case bytes.HasPrefix(head, []byte("symbols,")):
+		return ParseBloaty(br)
+	default:
+		return ParseNm(br)
+	}
+}
+
+// isBinary tells ELF, Mach-O and PE files.
+func isBinary(head []byte) bool {
+	for _, magic := range [][]byte{
+		[]byte("\x7fELF"), []byte("MZ"),
+		{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf}, {0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe},
+	} {
+		if bytes.HasPrefix(head, magic) {
+			return true
+		}
+	}
+	return false
+}
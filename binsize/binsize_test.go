@@ -0,0 +1,60 @@
+package binsize
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSymbolPackage(t *testing.T) {
+	for sym, expect := range map[string]string{
+		"main.main":              "main",
+		"github.com/x/y.(*T).M":  "github.com/x/y",
+		"type:*github.com/x/y.T": "github.com/x/y",
+		"cmp.Or[go.shape.interface { Error() string }]":        "cmp",
+		"vendor/golang.org/x/net/http2/hpack.(*Decoder).Write": "vendor/golang.org/x/net/http2/hpack",
+		"gopkg.in/yaml%2ev3.Unmarshal":                         "gopkg.in/yaml.v3",
+		"go:func.*":                                            "",
+		"$f64.3ff0000000000000":                                "",
+		"runtime":                                              "",
+	} {
+		if got := SymbolPackage(sym); got != expect {
+			t.Errorf("%v: %q, want %q", sym, got, expect)
+		}
+	}
+}
+
+func TestParseNm(t *testing.T) {
+	sizes, err := ParseNm(strings.NewReader(`  f05de0      1000 B crypto/internal/fips140/drbg.memory
+  e935a0      3000 D crypto/internal/fips140/nistec.p256PrecomputedEmbed
+  cb0f38       684 r go:func.*
+  648fc0       168 T cmp.Or[go.shape.interface { Error() string }]
+  648fc0        32 T cmp.Compare[go.shape.int]
+                 0 U abort
+  401000        50 T main.main
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := Sizes{"crypto/internal/fips140/nistec": 3000, "": 684, "cmp": 200, "main": 50}
+	if !reflect.DeepEqual(sizes, expect) || sizes.Total() != 3934 {
+		t.Error(sizes)
+	}
+	if _, err := ParseNm(strings.NewReader("symbols,vmsize,filesize\n")); err == nil {
+		t.Error("expect error")
+	}
+}
+
+func TestParseBloaty(t *testing.T) {
+	sizes, err := ParseBloaty(strings.NewReader(`symbols,vmsize,filesize
+github.com/x/y.(*T).M,100,90
+github.com/x/y.F,10,5
+[section .rodata],500,400
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sizes, Sizes{"github.com/x/y": 95, "": 400}) {
+		t.Error(sizes)
+	}
+}
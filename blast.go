@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+// loadRemoved returns the packages of dg matching the patterns of the
+// *blastFile, one per line, see rules.Match, sorted. Empty lines and
+// lines starting with # are skipped.
+func loadRemoved(dg *depgraph.DepGraph) []string {
+	f, err := os.Open(*blastFile)
+	if err != nil {
+		fail(exitParse, "open blast file failed %v", err)
+	}
+	defer f.Close()
+	var patterns []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fail(exitParse, "read blast file failed %v", err)
+	}
+	var removed []string
+	for _, p := range dg.Packages() {
+		for _, pattern := range patterns {
+			if rules.Match(pattern, p) {
+				removed = append(removed, p)
+				break
+			}
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// reportBlast prints the main packages and test binaries broken by
+// removing the packages of the *blastFile, eg: the sub-packages a
+// refactoring deletes, each with the shortest chain to one of them, then
+// the owners of the packages importing them, by number of broken binaries.
+func reportBlast(dg *depgraph.DepGraph) {
+	removed := loadRemoved(dg)
+	if len(removed) == 0 {
+		fail(exitNotFound, "no package matches %v", *blastFile)
+	}
+	broken := dg.BlastRadius(removed)
+	var mains, tests int
+	teams := make(map[string]int)
+	for _, b := range broken {
+		if b.Test {
+			tests++
+		} else {
+			mains++
+		}
+		importer := b.Chain[0]
+		if len(b.Chain) > 1 {
+			importer = b.Chain[len(b.Chain)-2]
+		}
+		owners := codeOwners.Of(importer)
+		if len(owners) == 0 {
+			owners = []string{"(no owner)"}
+		}
+		for _, o := range owners {
+			teams[o]++
+		}
+	}
+	fmt.Printf("removing %d packages breaks %d main packages and %d test binaries\n", len(removed), mains, tests)
+	if mains > 0 {
+		fmt.Println("main packages:")
+		for _, b := range broken[:mains] {
+			fmt.Println("\t" + formatChain(dg, append([]string{"main"}, b.Chain...)))
+		}
+	}
+	if tests > 0 {
+		fmt.Println("test binaries:")
+		for _, b := range broken[mains:] {
+			fmt.Println("\t" + formatChain(dg, b.Chain))
+		}
+	}
+	if len(teams) > 0 {
+		names := make([]string, 0, len(teams))
+		for o := range teams {
+			names = append(names, o)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if teams[names[i]] != teams[names[j]] {
+				return teams[names[i]] > teams[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		fmt.Println("owners:")
+		for _, o := range names {
+			fmt.Printf("\t%s: %d\n", o, teams[o])
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportCgo prints the main packages including cgo and the packages
+// importing "C" they depend on.
+func reportCgo(dg *depgraph.DepGraph) {
+	mains := dg.CgoMains()
+	names := make([]string, 0, len(mains))
+	for m := range mains {
+		names = append(names, m)
+	}
+	sort.Strings(names)
+	for _, m := range names {
+		fmt.Printf("%s: %s\n", m, strings.Join(mains[m], " "))
+	}
+}
+
+// reportUnsafe prints the main packages including third-party or
+// first-party packages importing "C" or "unsafe", and the chain to each of
+// them.
+func reportUnsafe(dg *depgraph.DepGraph) {
+	mains := dg.UnsafeMains()
+	names := make([]string, 0, len(mains))
+	for m := range mains {
+		names = append(names, m)
+	}
+	sort.Strings(names)
+	for _, m := range names {
+		fmt.Println(m + ownerNote(m))
+		for _, p := range mains[m] {
+			var uses []string
+			if dg.UsesCgo(p) {
+				uses = append(uses, "cgo")
+			}
+			if dg.UsesUnsafe(p) {
+				uses = append(uses, "unsafe")
+			}
+			line := p
+			if chains := dg.SearchChainFrom([]string{m}, p); len(chains) == 1 {
+				line = formatChain(dg, chains[0])
+			}
+			fmt.Printf("\t%s (%s)\n", line, strings.Join(uses, ", "))
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/coverage"
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportCoverage prints, for the main packages in args, or every one, that
+// ran according to the *coverageFiles, the subtrees of their deps linked
+// in but never executed, largest first: the package whose import to drop,
+// with the chain bringing it in, and the packages going with it.
+func reportCoverage(dg *depgraph.DepGraph) {
+	executed, err := coverage.LoadFiles(strings.Split(*coverageFiles, ","))
+	if err != nil {
+		fail(exitParse, "load coverage failed %v", err)
+	}
+	for _, m := range mainsOrAll(dg, flag.Args()) {
+		if !executed[m] {
+			if flag.NArg() > 0 {
+				warn(exitNotFound, "%v didn't run", m)
+			}
+			continue
+		}
+		var dead []depgraph.DeadSubtree
+		var packages int
+		for _, s := range dg.DeadSubtrees(m, executed) {
+			if showPackage(dg, s.Root) {
+				dead = append(dead, s)
+				packages += len(s.Packages)
+			}
+		}
+		fmt.Printf("%s%s: %d packages never executed in %d subtrees\n", m, ownerNote(m), packages, len(dead))
+		for _, s := range dead {
+			fmt.Printf("\t%d %s\n", len(s.Packages), formatChain(dg, append([]string{"main"}, s.Chain...)))
+			for _, p := range s.Packages {
+				if p != s.Root {
+					fmt.Println("\t\t" + p)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Package coverage reads which packages of a binary ran, from coverage
+// profiles or runtime package usage data, to tell the dependencies linked
+// in but never executed, see depgraph.DeadSubtrees.
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Executed is the set of the import paths of the packages that ran.
+type Executed map[string]bool
+
+// Load reads a coverage profile, as go test -coverprofile or go tool
+// covdata textfmt write it, where a package ran if a block of one of its
+// files did, go tool covdata percent output, where it ran if its coverage
+// isn't 0.0%, or a list of import paths, one per line, eg: the packages
+// a runtime probe saw loaded. Empty lines and lines starting with # are
+// skipped.
+func Load(r io.Reader) (Executed, error) {
+	executed := make(Executed)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	profile := false
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case n == 1 && strings.HasPrefix(line, "mode:"):
+			profile = true
+		case profile:
+			// file.go:1.2,3.4 statements count
+			fields := strings.Fields(line)
+			i := strings.LastIndex(line, ".go:")
+			if len(fields) != 3 || i < 0 {
+				return nil, fmt.Errorf("line %d: bad profile block %q", n, line)
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: bad count: %v", n, err)
+			}
+			if count > 0 {
+				executed[path.Dir(line[:i+3])] = true
+			}
+		case strings.Contains(line, "coverage: "):
+			fields := strings.Fields(line)
+			if len(fields) < 3 || fields[1] != "coverage:" {
+				return nil, fmt.Errorf("line %d: bad coverage percent %q", n, line)
+			}
+			if !strings.HasPrefix(fields[2], "0.0%") {
+				executed[fields[0]] = true
+			}
+		default:
+			executed[line] = true
+		}
+	}
+	return executed, scanner.Err()
+}
+
+// LoadFiles loads every file, see Load, and returns the packages that
+// ran in any.
+func LoadFiles(files []string) (Executed, error) {
+	executed := make(Executed)
+	for _, file := range files {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		e, err := Load(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", file, err)
+		}
+		for p := range e {
+			executed[p] = true
+		}
+	}
+	return executed, nil
+}
@@ -0,0 +1,36 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	for name, tc := range map[string]struct {
+		input  string
+		expect Executed
+	}{
+		"profile": {`mode: atomic
+example.com/cmd/api/main.go:10.13,12.2 2 1
+example.com/server/server.go:5.20,7.2 1 14
+example.com/legacy/old.go:3.15,4.2 1 0
+`, Executed{"example.com/cmd/api": true, "example.com/server": true}},
+		"percent": {`	example.com/cmd/api		coverage: 61.5% of statements
+	example.com/legacy		coverage: 0.0% of statements
+`, Executed{"example.com/cmd/api": true}},
+		"list": {`# seen in production
+example.com/cmd/api
+
+example.com/server
+`, Executed{"example.com/cmd/api": true, "example.com/server": true}},
+	} {
+		got, err := Load(strings.NewReader(tc.input))
+		if err != nil || !reflect.DeepEqual(got, tc.expect) {
+			t.Error(name, got, err)
+		}
+	}
+	if _, err := Load(strings.NewReader("mode: set\nexample.com/a/a.go:1.1,2.2 1\n")); err == nil {
+		t.Error("bad block accepted")
+	}
+}
@@ -0,0 +1,45 @@
+package depgraph
+
+import "sort"
+
+// Adopter is a main package reaching a library, see Adopters.
+type Adopter struct {
+	Main string
+	// Importers are the packages of the closure of Main, Main included,
+	// importing the library directly, sorted: the call sites a breaking
+	// change of the library has to fix for Main.
+	Importers []string
+}
+
+// Adopters returns the main packages depending on packageName, sorted,
+// each with the packages of its closure importing packageName directly.
+// Test-only imports are left out, they aren't part of the binaries.
+func (g *DepGraph) Adopters(packageName string) []Adopter {
+	g.prepare()
+	adopters := make([]Adopter, 0)
+	target, ok := g.lookup(packageName)
+	if !ok {
+		return adopters
+	}
+	var importers []nodeID
+	for _, id := range g.importers[target] {
+		if g.names[id] != "main" && !g.testPackages[id] && !g.edgeTo(id, target).TestOnly {
+			importers = append(importers, id)
+		}
+	}
+	for m := range g.mainPackages {
+		if !g.dependsOn(m, target) {
+			continue
+		}
+		a := Adopter{Main: g.names[m]}
+		for _, id := range importers {
+			if id == m || g.dependsOn(m, id) {
+				a.Importers = append(a.Importers, g.names[id])
+			}
+		}
+		sort.Strings(a.Importers)
+		adopters = append(adopters, a)
+	}
+	sort.Slice(adopters, func(i, j int) bool { return adopters[i].Main < adopters[j].Main })
+	return adopters
+}
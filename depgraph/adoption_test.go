@@ -0,0 +1,33 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdopters(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/api", Name: "main", Imports: []string{"example.com/server", "example.com/lib"},
+		Deps: []string{"example.com/lib", "example.com/server"}})
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/worker", Name: "main", Imports: []string{"example.com/queue"},
+		Deps: []string{"example.com/lib", "example.com/queue"}})
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/tool", Name: "main", Imports: []string{"example.com/util"},
+		Deps: []string{"example.com/util"}})
+	dg.Add(DepInfo{ImportPath: "example.com/server", Imports: []string{"example.com/lib"}, Deps: []string{"example.com/lib"}})
+	dg.Add(DepInfo{ImportPath: "example.com/queue", Imports: []string{"example.com/lib"}, Deps: []string{"example.com/lib"}})
+	dg.Add(DepInfo{ImportPath: "example.com/util"})
+	dg.Add(DepInfo{ImportPath: "example.com/util [example.com/util.test]", Imports: []string{"example.com/lib"},
+		Deps: []string{"example.com/lib"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib"})
+
+	expect := []Adopter{
+		{Main: "example.com/cmd/api", Importers: []string{"example.com/cmd/api", "example.com/server"}},
+		{Main: "example.com/cmd/worker", Importers: []string{"example.com/queue"}},
+	}
+	if got := dg.Adopters("example.com/lib"); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	if got := dg.Adopters("example.com/nope"); got == nil || len(got) != 0 {
+		t.Error(got)
+	}
+}
@@ -0,0 +1,94 @@
+package depgraph
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Anonymize returns a copy of g safe to share outside the organization:
+// every path element of the non-standard import paths, module paths
+// included, is replaced by a keyed hash, eg: example.com/app/db becomes
+// p1f0c2a9b/p83d1e0c4/p5b27aa10. Packages under the same path keep a
+// common prefix, and the structure, package flags and sizes are kept;
+// only the packages whose record says Standard keep their names, a graph
+// loaded without the Standard fields has all of its paths hashed. Package
+// names, module versions, directories and import positions are dropped.
+//
+// The same salt gives the same names, so anonymized graphs of several
+// snapshots can be compared; without a secret salt the hash of a guessed
+// path can be checked against the output.
+func (g *DepGraph) Anonymize(salt string) *DepGraph {
+	g.prepare()
+	rename := g.anonymizer(salt)
+	a := &DepGraph{}
+	type testEdge struct{ from, to string }
+	var testOnly []testEdge
+	for id, name := range g.names {
+		id := nodeID(id)
+		if !g.loaded[id] {
+			continue
+		}
+		info := DepInfo{ImportPath: rename(name), Standard: g.standard[id]}
+		if g.mainPackages[id] || g.testPackages[id] {
+			info.Name = "main"
+		}
+		if m := g.modules[id]; m != nil {
+			info.Module = &Module{Path: hashPath(salt, m.Path), Main: m.Main}
+		}
+		for _, e := range g.imports[id] {
+			to := rename(g.names[e.to])
+			info.Imports = append(info.Imports, to)
+			if !e.inBuild() {
+				testOnly = append(testOnly, testEdge{info.ImportPath, to})
+			}
+		}
+		g.eachDep(id, func(dep nodeID) {
+			info.Deps = append(info.Deps, rename(g.names[dep]))
+		})
+		a.Add(info)
+		g.copyMainFlags(a, id, a.ids[info.ImportPath])
+		a.sizes[a.ids[info.ImportPath]] = g.sizes[id]
+	}
+	for _, e := range testOnly {
+		a.SetEdge(e.from, e.to, EdgeAttrs{TestOnly: true})
+	}
+	a.sawStandard = true
+	return a
+}
+
+// anonymizer returns the renaming of Anonymize.
+func (g *DepGraph) anonymizer(salt string) func(string) string {
+	renamed := map[string]string{"main": "main"}
+	var rename func(string) string
+	rename = func(p string) string {
+		if r, ok := renamed[p]; ok {
+			return r
+		}
+		var r string
+		if base := strings.TrimSuffix(p, ".test"); base != p {
+			r = rename(base) + ".test"
+		} else if id, ok := g.lookup(p); ok && g.standard[id] {
+			r = p
+		} else {
+			r = hashPath(salt, p)
+		}
+		renamed[p] = r
+		return r
+	}
+	return rename
+}
+
+// hashPath replaces every element of the import or module path p by the
+// keyed hash of the path up to it.
+func hashPath(salt, p string) string {
+	elements := strings.Split(p, "/")
+	hashed := make([]string, len(elements))
+	for i := range elements {
+		mac := hmac.New(sha256.New, []byte(salt))
+		mac.Write([]byte(strings.Join(elements[:i+1], "/")))
+		hashed[i] = "p" + hex.EncodeToString(mac.Sum(nil))[:8]
+	}
+	return strings.Join(hashed, "/")
+}
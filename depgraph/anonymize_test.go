@@ -0,0 +1,81 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnonymize(t *testing.T) {
+	dg := &DepGraph{}
+	app := &Module{Path: "example.com/app", Main: true}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/api", Name: "main", Module: app, GoFiles: []string{"main.go"},
+		Imports: []string{"example.com/app/db", "fmt"}, Deps: []string{"example.com/app/db", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/db", Name: "db", Module: app, Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/db [example.com/app/db.test]", Imports: []string{"testing"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Standard: true})
+	dg.Add(DepInfo{ImportPath: "testing", Standard: true})
+
+	a := dg.Anonymize("secret")
+	var api, db string
+	for _, p := range a.Packages() {
+		if strings.Contains(p, "example") || strings.Contains(p, "app") {
+			t.Error("leaked", p)
+		}
+		if a.IsMainPackage(p) {
+			api = p
+		} else if !a.IsStandard(p) {
+			db = p
+		}
+	}
+	if api == "" || db == "" || len(a.Packages()) != 4 {
+		t.Fatal(a.Packages())
+	}
+	// example.com/app is a common prefix of both
+	if parts := strings.Split(api, "/"); !strings.HasPrefix(db, parts[0]+"/"+parts[1]+"/") || len(parts) != 4 {
+		t.Error(api, db)
+	}
+	if !a.PathExists(api, "fmt") || !a.IsStandard("fmt") || a.Size(api).Files != 1 {
+		t.Error("structure lost")
+	}
+	if m := a.Module(db); m == nil || !strings.HasPrefix(db, m.Path+"/") {
+		t.Error("module should be renamed like its packages", m)
+	}
+	if e := a.Edge(db, "testing"); e == nil || !e.TestOnly {
+		t.Error("test-only edge lost", e)
+	}
+	if again := dg.Anonymize("secret"); !a.Exists(again.Packages()[0]) {
+		t.Error("same salt should give the same names")
+	}
+	if other := dg.Anonymize("other"); other.Exists(api) {
+		t.Error("another salt should give other names")
+	}
+}
+
+func TestAnonymizeDotless(t *testing.T) {
+	billing := &Module{Path: "corp/billing", Main: true}
+	for _, standard := range []bool{true, false} {
+		dg := &DepGraph{}
+		dg.Add(DepInfo{ImportPath: "corp/billing/cmd/invoice", Name: "main", Module: billing,
+			Imports: []string{"corp/billing", "fmt"}, Deps: []string{"corp/billing", "fmt"}})
+		dg.Add(DepInfo{ImportPath: "corp/billing", Name: "billing", Module: billing})
+		dg.Add(DepInfo{ImportPath: "fmt", Standard: standard})
+
+		a := dg.Anonymize("secret")
+		for _, p := range a.Packages() {
+			if strings.Contains(p, "corp") || strings.Contains(p, "billing") {
+				t.Error("leaked", p)
+			}
+			if m := a.Module(p); m != nil && strings.Contains(m.Path, "corp") {
+				t.Error("module leaked", m.Path)
+			}
+		}
+		// without the Standard fields nothing tells fmt is the standard
+		// library one, so it is hashed too
+		if a.Exists("fmt") != standard || (a.CountStandard() == 1) != standard {
+			t.Error("standard packages error", standard, a.Packages())
+		}
+		if len(a.Packages()) != 3 || a.CountMain() != 1 {
+			t.Error("structure lost", a.Packages())
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package depgraph
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchGraph builds a layered graph of n packages where every package
+// imports a few packages of the next layer.
+func benchGraph(n int) *DepGraph {
+	dg := &DepGraph{}
+	name := func(i int) string { return fmt.Sprintf("example.com/p%d", i) }
+	for i := n - 1; i >= 0; i-- {
+		d := DepInfo{ImportPath: name(i), Name: "p"}
+		for _, j := range []int{2*i + 1, 2*i + 2} {
+			if j < n {
+				d.Imports = append(d.Imports, name(j))
+			}
+		}
+		for j := 2*i + 1; j < n && len(d.Deps) < 64; j++ {
+			d.Deps = append(d.Deps, name(j))
+		}
+		dg.Add(d)
+	}
+	return dg
+}
+
+// listUnUsedScan is the former ListUnUsed: for every candidate it scans the
+// Deps of every package.
+func listUnUsedScan(g *DepGraph) (packages []string) {
+	for p := range g.deps {
+		if !g.loaded[p] {
+			continue
+		}
+		found := false
+		for m := range g.deps {
+			if g.dependsOn(nodeID(m), nodeID(p)) {
+				found = true
+			}
+		}
+		if !found {
+			packages = append(packages, g.names[p])
+		}
+	}
+	return
+}
+
+func TestListUnUsedMatchesScan(t *testing.T) {
+	dg := benchGraph(2000)
+	if len(dg.ListUnUsed()) != len(listUnUsedScan(dg)) {
+		t.Error(len(dg.ListUnUsed()), len(listUnUsedScan(dg)))
+	}
+}
+
+func BenchmarkListUnUsed(b *testing.B) {
+	for _, n := range []int{1000, 10000, 80000} {
+		dg := benchGraph(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				dg.ListUnUsed()
+			}
+		})
+	}
+}
+
+func BenchmarkListUnUsedScan(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		dg := benchGraph(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				listUnUsedScan(dg)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package depgraph
+
+import "sort"
+
+// UniqueDeps maps every main package to the sorted packages it depends on
+// that no other main package depends on: candidates for moving into the
+// binary's own tree, or for asking why only one service needs them.
+func (g *DepGraph) UniqueDeps() map[string][]string {
+	owner := make(map[nodeID]nodeID)
+	shared := make(map[nodeID]bool)
+	for m := range g.mainPackages {
+		g.eachDep(m, func(dep nodeID) {
+			if o, ok := owner[dep]; ok && o != m {
+				shared[dep] = true
+			}
+			owner[dep] = m
+		})
+	}
+	result := make(map[string][]string, len(g.mainPackages))
+	for m := range g.mainPackages {
+		result[g.names[m]] = nil
+	}
+	for dep, m := range owner {
+		if !shared[dep] {
+			result[g.names[m]] = append(result[g.names[m]], g.names[dep])
+		}
+	}
+	for _, packages := range result {
+		sort.Strings(packages)
+	}
+	return result
+}
+
+// Overlap is how much two main packages share of their dependencies.
+type Overlap struct {
+	A, B    string
+	Shared  int     // packages both depend on
+	Union   int     // packages either depends on
+	Jaccard float64 // Shared / Union, 0 if neither has dependencies
+}
+
+// Overlaps returns the dependency overlap of every pair of main packages,
+// most similar first: pairs with a high Jaccard similarity are candidates
+// for consolidation, the shared packages for a common library.
+func (g *DepGraph) Overlaps() (overlaps []Overlap) {
+	var mains []nodeID
+	for m := range g.mainPackages {
+		mains = append(mains, m)
+	}
+	sort.Slice(mains, func(i, j int) bool { return g.names[mains[i]] < g.names[mains[j]] })
+	sets := make([]bitset, len(mains))
+	for i, m := range mains {
+		g.eachDep(m, sets[i].set)
+	}
+	for i := range mains {
+		for j := i + 1; j < len(mains); j++ {
+			o := Overlap{A: g.names[mains[i]], B: g.names[mains[j]]}
+			o.Shared = sets[i].intersectionCount(sets[j])
+			o.Union = sets[i].count() + sets[j].count() - o.Shared
+			if o.Union > 0 {
+				o.Jaccard = float64(o.Shared) / float64(o.Union)
+			}
+			overlaps = append(overlaps, o)
+		}
+	}
+	sort.SliceStable(overlaps, func(i, j int) bool { return overlaps[i].Jaccard > overlaps[j].Jaccard })
+	return
+}
+
+// OnlyVia returns the sorted packages mainPackage reaches only through
+// via: those dropping via, and its own import, would remove from the
+// binary, via left out. It returns nil if mainPackage doesn't depend on
+// via.
+func (g *DepGraph) OnlyVia(mainPackage, via string) (packages []string) {
+	id, ok := g.lookup(mainPackage)
+	if !ok {
+		return
+	}
+	viaID, ok := g.lookup(via)
+	if !ok || id == viaID || !g.closure()[id].has(viaID) {
+		return
+	}
+	var without bitset
+	without.set(id)
+	queue := []nodeID{id}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, e := range g.imports[from] {
+			if e.inBuild() && e.to != viaID && !without.has(e.to) {
+				without.set(e.to)
+				queue = append(queue, e.to)
+			}
+		}
+	}
+	g.closure()[viaID].each(func(dep nodeID) {
+		if !without.has(dep) && dep != viaID {
+			packages = append(packages, g.names[dep])
+		}
+	})
+	sort.Strings(packages)
+	return
+}
@@ -0,0 +1,68 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func binariesTestGraph() *DepGraph {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/api", Name: "main", Imports: []string{"db", "log"}, Deps: []string{"db", "log", "sql"}})
+	dg.Add(DepInfo{ImportPath: "cmd/worker", Name: "main", Imports: []string{"log", "queue"}, Deps: []string{"log", "queue"}})
+	dg.Add(DepInfo{ImportPath: "cmd/tool", Name: "main", Imports: []string{"log"}, Deps: []string{"log"}})
+	dg.Add(DepInfo{ImportPath: "db", Name: "db", Imports: []string{"sql"}, Deps: []string{"sql"}})
+	dg.Add(DepInfo{ImportPath: "sql", Name: "sql"})
+	dg.Add(DepInfo{ImportPath: "log", Name: "log"})
+	dg.Add(DepInfo{ImportPath: "queue", Name: "queue"})
+	return dg
+}
+
+func TestUniqueDeps(t *testing.T) {
+	expect := map[string][]string{
+		"cmd/api":    {"db", "sql"},
+		"cmd/worker": {"queue"},
+		"cmd/tool":   nil,
+	}
+	for _, ignoreDeps := range []bool{false, true} {
+		dg := binariesTestGraph()
+		dg.IgnoreDeps(ignoreDeps)
+		if got := dg.UniqueDeps(); !reflect.DeepEqual(got, expect) {
+			t.Error(ignoreDeps, got)
+		}
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	expect := []Overlap{
+		{A: "cmd/tool", B: "cmd/worker", Shared: 1, Union: 2, Jaccard: 0.5},
+		{A: "cmd/api", B: "cmd/tool", Shared: 1, Union: 3, Jaccard: 1.0 / 3},
+		{A: "cmd/api", B: "cmd/worker", Shared: 1, Union: 4, Jaccard: 0.25},
+	}
+	if got := binariesTestGraph().Overlaps(); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+}
+
+func TestOnlyVia(t *testing.T) {
+	dg := binariesTestGraph()
+	if got := dg.OnlyVia("cmd/api", "db"); !reflect.DeepEqual(got, []string{"sql"}) {
+		t.Error(got)
+	}
+	if got := dg.OnlyVia("cmd/worker", "db"); got != nil {
+		t.Error("worker doesn't depend on db", got)
+	}
+
+	dg = &DepGraph{}
+	dg.AddEdge("main", "a")
+	dg.AddEdge("main", "b")
+	dg.AddEdge("a", "c")
+	dg.AddEdge("c", "d")
+	dg.AddEdge("b", "d")
+	dg.AddEdge("d", "e")
+	if got := dg.OnlyVia("main", "a"); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Error("d is also reached through b", got)
+	}
+	if got := dg.OnlyVia("main", "d"); !reflect.DeepEqual(got, []string{"e"}) {
+		t.Error(got)
+	}
+}
@@ -0,0 +1,120 @@
+package depgraph
+
+import "sort"
+
+// SubtreeSize is the part of the size of a binary attributed to one of
+// its packages, see SizeSubtrees.
+type SubtreeSize struct {
+	Package string
+	Own     int64 // bytes of the symbols of the package
+	Subtree int64 // bytes gone from the binary without the package: its own and those of the packages only it brings in
+}
+
+// SizeSubtrees attributes the size of the binary of mainPackage, given by
+// package in sizes, eg: from go tool nm, to its packages. The Subtree of a
+// package sums its size and the sizes of the packages every import path
+// from mainPackage to goes through it, its subtree in the dominator tree
+// of the imports. The symbols of mainPackage may be under "main" in
+// sizes. The result is sorted by Subtree, largest first, then by path.
+func (g *DepGraph) SizeSubtrees(mainPackage string, sizes map[string]int64) []SubtreeSize {
+	start, ok := g.lookup(mainPackage)
+	if !ok {
+		return nil
+	}
+	post, idom := g.dominators(start)
+	subtree := make(map[nodeID]int64, len(post))
+	result := make([]SubtreeSize, 0, len(post))
+	for _, id := range post {
+		own := sizes[g.names[id]]
+		if id == start {
+			own += sizes["main"]
+		}
+		subtree[id] += own
+		if id != start {
+			subtree[idom[id]] += subtree[id]
+		}
+		result = append(result, SubtreeSize{Package: g.names[id], Own: own, Subtree: subtree[id]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Subtree != result[j].Subtree {
+			return result[i].Subtree > result[j].Subtree
+		}
+		return result[i].Package < result[j].Package
+	})
+	return result
+}
+
+// dominators returns the packages start builds in postorder of the build
+// imports, start last, and their immediate dominators: the last package
+// every import path from start to them goes through, see Cooper, Harvey
+// and Kennedy, "A Simple, Fast Dominance Algorithm".
+func (g *DepGraph) dominators(start nodeID) ([]nodeID, map[nodeID]nodeID) {
+	// postorder of the build imports from start
+	order := make(map[nodeID]int)
+	var post []nodeID
+	type frame struct {
+		id   nodeID
+		next int
+	}
+	visited := map[nodeID]bool{start: true}
+	stack := []frame{{id: start}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if top.next == len(g.imports[top.id]) {
+			order[top.id] = len(post)
+			post = append(post, top.id)
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		e := g.imports[top.id][top.next]
+		top.next++
+		if e.inBuild() && !visited[e.to] {
+			visited[e.to] = true
+			stack = append(stack, frame{id: e.to})
+		}
+	}
+	// immediate dominators
+	preds := make(map[nodeID][]nodeID)
+	for _, id := range post {
+		for _, e := range g.imports[id] {
+			if e.inBuild() {
+				preds[e.to] = append(preds[e.to], id)
+			}
+		}
+	}
+	idom := map[nodeID]nodeID{start: start}
+	intersect := func(a, b nodeID) nodeID {
+		for a != b {
+			for order[a] < order[b] {
+				a = idom[a]
+			}
+			for order[b] < order[a] {
+				b = idom[b]
+			}
+		}
+		return a
+	}
+	for changed := true; changed; {
+		changed = false
+		for i := len(post) - 2; i >= 0; i-- {
+			id := post[i]
+			var dom nodeID
+			found := false
+			for _, p := range preds[id] {
+				if _, ok := idom[p]; !ok {
+					continue
+				}
+				if !found {
+					dom, found = p, true
+				} else {
+					dom = intersect(p, dom)
+				}
+			}
+			if old, ok := idom[id]; found && (!ok || old != dom) {
+				idom[id] = dom
+				changed = true
+			}
+		}
+	}
+	return post, idom
+}
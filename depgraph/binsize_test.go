@@ -0,0 +1,29 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSizeSubtrees(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"a", "b"}, Deps: []string{"a", "b", "c", "d"}})
+	dg.Add(DepInfo{ImportPath: "a", Name: "a", Imports: []string{"c", "d"}, Deps: []string{"c", "d"}})
+	dg.Add(DepInfo{ImportPath: "b", Name: "b", Imports: []string{"c"}, Deps: []string{"c"}})
+	dg.Add(DepInfo{ImportPath: "c", Name: "c"})
+	dg.Add(DepInfo{ImportPath: "d", Name: "d"})
+	sizes := map[string]int64{"main": 10, "a": 1, "b": 2, "c": 4, "d": 8, "unrelated": 100}
+	expect := []SubtreeSize{
+		{"cmd/a", 10, 25},
+		{"a", 1, 9},
+		{"d", 8, 8},
+		{"c", 4, 4},
+		{"b", 2, 2},
+	}
+	if got := dg.SizeSubtrees("cmd/a", sizes); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	if got := dg.SizeSubtrees("nope", sizes); got != nil {
+		t.Error(got)
+	}
+}
@@ -0,0 +1,95 @@
+package depgraph
+
+import "math/bits"
+
+// bitset is a set of node IDs. Only the words between the lowest and the
+// highest member are stored, which keeps the sets of packages that depend
+// on a narrow slice of the graph small.
+type bitset struct {
+	off   int // index of the first stored word
+	words []uint64
+}
+
+func (b *bitset) has(id nodeID) bool {
+	w := int(id)/64 - b.off
+	return w >= 0 && w < len(b.words) && b.words[w]&(1<<(uint(id)%64)) != 0
+}
+
+// grow makes room for the words in [lo, hi].
+func (b *bitset) grow(lo, hi int) {
+	if len(b.words) == 0 {
+		b.off = lo
+		b.words = make([]uint64, hi-lo+1)
+		return
+	}
+	if lo < b.off {
+		words := make([]uint64, b.off-lo+len(b.words))
+		copy(words[b.off-lo:], b.words)
+		b.off, b.words = lo, words
+	}
+	if end := b.off + len(b.words); hi >= end {
+		b.words = append(b.words, make([]uint64, hi-end+1)...)
+	}
+}
+
+func (b *bitset) set(id nodeID) {
+	w := int(id) / 64
+	b.grow(w, w)
+	b.words[w-b.off] |= 1 << (uint(id) % 64)
+}
+
+func (b *bitset) or(other bitset) {
+	if len(other.words) == 0 {
+		return
+	}
+	b.grow(other.off, other.off+len(other.words)-1)
+	for i, w := range other.words {
+		b.words[other.off-b.off+i] |= w
+	}
+}
+
+func (b *bitset) count() (n int) {
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+	return
+}
+
+func (b *bitset) equal(other bitset) bool {
+	return b.count() == other.count() && b.subsetOf(other)
+}
+
+func (b *bitset) subsetOf(other bitset) bool {
+	for i, w := range b.words {
+		if w == 0 {
+			continue
+		}
+		j := b.off + i - other.off
+		if j < 0 || j >= len(other.words) || w&^other.words[j] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// each calls fn for every member in ascending order.
+func (b *bitset) each(fn func(id nodeID)) {
+	for i, w := range b.words {
+		for w != 0 {
+			n := bits.TrailingZeros64(w)
+			fn(nodeID((b.off+i)*64 + n))
+			w &^= 1 << uint(n)
+		}
+	}
+}
+
+// intersectionCount returns the number of members b and other share.
+func (b *bitset) intersectionCount(other bitset) (n int) {
+	for i, w := range b.words {
+		j := b.off + i - other.off
+		if j >= 0 && j < len(other.words) {
+			n += bits.OnesCount64(w & other.words[j])
+		}
+	}
+	return
+}
@@ -0,0 +1,87 @@
+package depgraph
+
+import "sort"
+
+// Breakage is a binary broken by the removal of packages, see
+// BlastRadius.
+type Breakage struct {
+	Binary string // main package or test binary
+	Test   bool
+	// Chain is the shortest import chain from Binary to a removed
+	// package, both included; the package before the last is the one to
+	// fix, Binary itself if it imports a removed package directly.
+	Chain []string
+}
+
+// BlastRadius returns the main packages, then the test binaries of go
+// list -test, each sorted, depending on one of the removed packages, eg:
+// the sub-packages a refactoring deletes. Binaries being removed
+// themselves are broken too, with a chain of one package.
+func (g *DepGraph) BlastRadius(removed []string) []Breakage {
+	g.prepare()
+	isRemoved := make(map[nodeID]bool, len(removed))
+	for _, p := range removed {
+		if id, ok := g.lookup(p); ok {
+			isRemoved[id] = true
+		}
+	}
+	broken := make([]Breakage, 0)
+	for _, set := range []struct {
+		binaries map[nodeID]bool
+		test     bool
+	}{{g.mainPackages, false}, {g.testPackages, true}} {
+		var found []Breakage
+		for bin := range set.binaries {
+			var parent map[nodeID]nodeID
+			if set.test {
+				parent = g.testWalk(bin)
+			} else {
+				parent = g.buildWalk(bin)
+			}
+			if chain := nearest(g, parent, isRemoved); chain != nil {
+				found = append(found, Breakage{Binary: g.names[bin], Test: set.test, Chain: chain})
+			}
+		}
+		sort.Slice(found, func(i, j int) bool { return found[i].Binary < found[j].Binary })
+		broken = append(broken, found...)
+	}
+	return broken
+}
+
+// buildWalk returns the shortest import chain parents of the packages
+// start builds, following the imports that are not test-only.
+func (g *DepGraph) buildWalk(start nodeID) map[nodeID]nodeID {
+	parent := map[nodeID]nodeID{start: -1}
+	queue := []nodeID{start}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, e := range g.imports[from] {
+			if _, ok := parent[e.to]; !ok && e.inBuild() {
+				parent[e.to] = from
+				queue = append(queue, e.to)
+			}
+		}
+	}
+	return parent
+}
+
+// nearest returns the shortest chain of parent, a walk from a binary,
+// to one of targets, the first by import path among those as close, or
+// nil if the walk reaches none.
+func nearest(g *DepGraph, parent map[nodeID]nodeID, targets map[nodeID]bool) (best []string) {
+	for id := range targets {
+		if _, ok := parent[id]; !ok {
+			continue
+		}
+		var chain []string
+		for p := id; p >= 0; p = parent[p] {
+			chain = append(chain, g.names[p])
+		}
+		reverseSlice(chain)
+		if best == nil || len(chain) < len(best) || len(chain) == len(best) && chain[len(chain)-1] < best[len(best)-1] {
+			best = chain
+		}
+	}
+	return
+}
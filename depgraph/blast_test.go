@@ -0,0 +1,32 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBlastRadius(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "app/cmd/api", Name: "main", Imports: []string{"app/server"},
+		Deps: []string{"app/server", "lib/old", "lib/old/codec"}})
+	dg.Add(DepInfo{ImportPath: "app/cmd/tool", Name: "main", Imports: []string{"lib/new"}, Deps: []string{"lib/new"}})
+	dg.Add(DepInfo{ImportPath: "app/server", Imports: []string{"lib/old", "lib/old/codec"}, Deps: []string{"lib/old", "lib/old/codec"}})
+	dg.Add(DepInfo{ImportPath: "lib/old", Imports: []string{"lib/old/codec"}, Deps: []string{"lib/old/codec"}})
+	dg.Add(DepInfo{ImportPath: "lib/old/codec"})
+	dg.Add(DepInfo{ImportPath: "lib/new"})
+	dg.Add(DepInfo{ImportPath: "lib/new [lib/new.test]", Imports: []string{"lib/old/codec"}, Deps: []string{"lib/old/codec"}})
+	dg.Add(DepInfo{ImportPath: "lib/new.test", Name: "main", Imports: []string{"lib/new [lib/new.test]", "testing"},
+		Deps: []string{"lib/new [lib/new.test]", "lib/old/codec", "testing"}})
+
+	expect := []Breakage{
+		{Binary: "app/cmd/api", Chain: []string{"app/cmd/api", "app/server", "lib/old"}},
+		{Binary: "lib/new.test", Test: true, Chain: []string{"lib/new.test", "lib/new", "lib/old/codec"}},
+	}
+	if got := dg.BlastRadius([]string{"lib/old/codec", "lib/old", "lib/gone"}); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	expect = []Breakage{{Binary: "app/cmd/tool", Chain: []string{"app/cmd/tool"}}}
+	if got := dg.BlastRadius([]string{"app/cmd/tool"}); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+}
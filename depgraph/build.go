@@ -0,0 +1,43 @@
+package depgraph
+
+import "strings"
+
+// BuildProfile is the build configuration go list ran with. Build tags,
+// GOOS and GOARCH change which files, and so which imports, a package
+// has: query results only hold for the profile the graph was loaded with.
+type BuildProfile struct {
+	GOOS    string   `json:",omitempty"`
+	GOARCH  string   `json:",omitempty"`
+	Tags    []string `json:",omitempty"`
+	GOFLAGS string   `json:",omitempty"` // the other go flags, eg: -mod=vendor
+}
+
+// String returns p as go command settings, eg: "GOOS=linux -tags=netgo",
+// or "" for the default profile.
+func (p BuildProfile) String() string {
+	var s []string
+	if p.GOOS != "" {
+		s = append(s, "GOOS="+p.GOOS)
+	}
+	if p.GOARCH != "" {
+		s = append(s, "GOARCH="+p.GOARCH)
+	}
+	if len(p.Tags) > 0 {
+		s = append(s, "-tags="+strings.Join(p.Tags, ","))
+	}
+	if p.GOFLAGS != "" {
+		s = append(s, "GOFLAGS="+p.GOFLAGS)
+	}
+	return strings.Join(s, " ")
+}
+
+// SetBuild records the build profile g was loaded with.
+func (g *DepGraph) SetBuild(p BuildProfile) {
+	g.mustNotBeFrozen()
+	g.build = p
+}
+
+// Build returns the build profile g was loaded with, see SetBuild.
+func (g *DepGraph) Build() BuildProfile {
+	return g.build
+}
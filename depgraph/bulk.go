@@ -0,0 +1,71 @@
+package depgraph
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// SearchChainBulk is SearchChain for many targets at once, eg: every
+// package an advisory lists. The main packages are spread over workers
+// goroutines (GOMAXPROCS if workers <= 0); each worker keeps the dead ends
+// of every target across the mains it handles, so the subgraphs shared by
+// several mains are explored once per worker. The chains of each target
+// are sorted by main package; targets depended on by no main package are
+// left out.
+func (g *DepGraph) SearchChainBulk(pkgs []string, workers int) map[string][][]string {
+	g.prepare()
+	targets := make(map[nodeID]string)
+	for _, p := range pkgs {
+		if id, ok := g.lookup(p); ok {
+			targets[id] = p
+		}
+	}
+	mains := make([]nodeID, 0, len(g.mainPackages))
+	for id := range g.mainPackages {
+		mains = append(mains, id)
+	}
+	sortIDs(mains)
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(mains) {
+		workers = len(mains)
+	}
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		result = make(map[string][][]string)
+		jobs   = make(chan nodeID)
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deadEnds := make(map[nodeID]map[nodeID]bool)
+			for m := range jobs {
+				for target, name := range targets {
+					if m != target && !g.dependsOn(m, target) {
+						continue
+					}
+					if deadEnds[target] == nil {
+						deadEnds[target] = make(map[nodeID]bool)
+					}
+					chain := g.chainFrom(g.names[m], name, deadEnds[target])
+					mu.Lock()
+					result[name] = append(result[name], chain)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, m := range mains {
+		jobs <- m
+	}
+	close(jobs)
+	wg.Wait()
+	for _, chains := range result {
+		sort.Slice(chains, func(i, j int) bool { return chains[i][1] < chains[j][1] })
+	}
+	return result
+}
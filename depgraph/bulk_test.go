@@ -0,0 +1,28 @@
+package depgraph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSearchChainBulk(t *testing.T) {
+	dg := loadTestGraph(t)
+	targets := []string{"net/url", "crypto/tls", "cmd/vet", "fmt", "no/such/package"}
+	for _, workers := range []int{0, 1, 3} {
+		bulk := dg.SearchChainBulk(targets, workers)
+		for _, p := range targets {
+			expect := dg.SearchChain(p)
+			sort.Slice(expect, func(i, j int) bool { return expect[i][1] < expect[j][1] })
+			if len(expect) == 0 {
+				expect = nil
+			}
+			if !reflect.DeepEqual(bulk[p], expect) {
+				t.Error(workers, p, len(bulk[p]), len(expect))
+			}
+		}
+		if _, ok := bulk["no/such/package"]; ok {
+			t.Error("unknown package should be left out")
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package depgraph
+
+import (
+	"container/list"
+	"sync"
+)
+
+// queryCache is an LRU cache of query results keyed by the query name and
+// its arguments. It is dropped whenever the graph changes.
+type queryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+}
+
+type cacheEntry struct {
+	key   string
+	value interface{}
+}
+
+func newQueryCache(size int) *queryCache {
+	return &queryCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *queryCache) get(key string) (interface{}, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).value, true
+}
+
+func (c *queryCache) put(key string, value interface{}) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*cacheEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value})
+	for c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *queryCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// EnableCache keeps the results of the last size SearchChain and
+// SearchGraph queries, so repeated lookups from a long running UI or REPL
+// are answered immediately. Any change to the graph empties the cache.
+// size <= 0 disables caching.
+func (g *DepGraph) EnableCache(size int) {
+	if size <= 0 {
+		g.cache = nil
+		return
+	}
+	g.cache = newQueryCache(size)
+}
+
+// changed must be called by everything that modifies the graph.
+func (g *DepGraph) changed() {
+	g.cache.clear()
+}
+
+func copyChains(chains [][]string) [][]string {
+	if chains == nil {
+		return nil
+	}
+	copied := make([][]string, len(chains))
+	for i, chain := range chains {
+		copied[i] = append([]string(nil), chain...)
+	}
+	return copied
+}
+
+func copyGraph(result map[string][]string) map[string][]string {
+	if result == nil {
+		return nil
+	}
+	copied := make(map[string][]string, len(result))
+	for k, v := range result {
+		copied[k] = append([]string(nil), v...)
+	}
+	return copied
+}
@@ -0,0 +1,32 @@
+package depgraph
+
+import "testing"
+
+func TestQueryCache(t *testing.T) {
+	c := newQueryCache(2)
+	c.put("a", 1)
+	c.put("b", 2)
+	c.get("a")
+	c.put("c", 3)
+	if _, ok := c.get("b"); ok {
+		t.Error("b should be evicted")
+	}
+	if v, ok := c.get("a"); !ok || v != 1 {
+		t.Error("a should be kept", v)
+	}
+
+	dg := loadTestGraph(t)
+	dg.EnableCache(16)
+	chains := dg.SearchChain("net/url")
+	chains[0][0] = "changed"
+	if cached := dg.SearchChain("net/url"); len(cached) != len(chains) || cached[0][0] != "main" {
+		t.Error("cached result should not be shared with callers")
+	}
+	if len(dg.SearchGraph("x", "net/http")) != 0 {
+		t.Error("x does not exist yet")
+	}
+	dg.AddEdge("x", "net/http")
+	if len(dg.SearchGraph("x", "net/http")) != 1 {
+		t.Error("cache should be dropped after AddEdge")
+	}
+}
@@ -0,0 +1,78 @@
+package depgraph
+
+import "sort"
+
+// UsesCgo reports whether packageName imports "C".
+func (g *DepGraph) UsesCgo(packageName string) bool {
+	id, ok := g.lookup(packageName)
+	return ok && g.cgoPackages[id]
+}
+
+// CgoMains returns the main packages including cgo, each mapped to the
+// sorted packages importing "C" it depends on, itself included. Such
+// binaries can't be built with CGO_ENABLED=0 without losing those
+// packages' cgo code, which matters for static and cross builds.
+func (g *DepGraph) CgoMains() map[string][]string {
+	g.prepare()
+	result := make(map[string][]string)
+	for m := range g.mainPackages {
+		for c := range g.cgoPackages {
+			if c == m || g.dependsOn(m, c) {
+				result[g.names[m]] = append(result[g.names[m]], g.names[c])
+			}
+		}
+	}
+	for _, packages := range result {
+		sort.Strings(packages)
+	}
+	return result
+}
+
+// UsesUnsafe reports whether packageName imports "unsafe".
+func (g *DepGraph) UsesUnsafe(packageName string) bool {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return false
+	}
+	unsafe, ok := g.lookup("unsafe")
+	return ok && g.importsDirectly(id, unsafe)
+}
+
+func (g *DepGraph) importsDirectly(from, to nodeID) bool {
+	for _, e := range g.imports[from] {
+		if e.to == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UnsafeMains is CgoMains for the packages outside the standard library
+// importing "C" or "unsafe", the code a memory-safety review has to read:
+// the standard library is left out, most of it imports "unsafe".
+func (g *DepGraph) UnsafeMains() map[string][]string {
+	g.prepare()
+	unsafe, hasUnsafe := g.lookup("unsafe")
+	var unsafePackages []nodeID
+	for id, loaded := range g.loaded {
+		id := nodeID(id)
+		if !loaded || g.isStandard(id) {
+			continue
+		}
+		if g.cgoPackages[id] || hasUnsafe && g.importsDirectly(id, unsafe) {
+			unsafePackages = append(unsafePackages, id)
+		}
+	}
+	result := make(map[string][]string)
+	for m := range g.mainPackages {
+		for _, u := range unsafePackages {
+			if u == m || g.dependsOn(m, u) {
+				result[g.names[m]] = append(result[g.names[m]], g.names[u])
+			}
+		}
+	}
+	for _, packages := range result {
+		sort.Strings(packages)
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCgo(t *testing.T) {
+	dg := loadTestGraph(t)
+	if !dg.UsesCgo("net") || !dg.UsesCgo("runtime/cgo") || dg.UsesCgo("net/http") {
+		t.Error("UsesCgo error")
+	}
+	if dg.Exists("C") || len(dg.Importers("C")) != 0 {
+		t.Error(`"C" should not be a package`)
+	}
+	mains := dg.CgoMains()
+	if got := strings.Join(mains["cmd/go"], " "); got != "net runtime/cgo" {
+		t.Error("cmd/go", got)
+	}
+	if _, ok := mains["cmd/gofmt"]; ok {
+		t.Error("cmd/gofmt doesn't use cgo", mains["cmd/gofmt"])
+	}
+	if f := dg.Freeze(); !f.UsesCgo("net") || len(f.CgoMains()) != len(mains) {
+		t.Error("frozen graph lost cgo packages")
+	}
+}
+
+func TestUnsafe(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Imports: []string{"example.com/app/mmap"}, Deps: []string{"example.com/app/mmap", "unsafe"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/b", Name: "main", Imports: []string{"example.com/app/sqlite", "sync"}, Deps: []string{"example.com/app/sqlite", "sync", "unsafe"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/c", Name: "main", Imports: []string{"sync"}, Deps: []string{"sync", "unsafe"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/mmap", Imports: []string{"unsafe"}, Deps: []string{"unsafe"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/sqlite", Imports: []string{"C"}})
+	dg.Add(DepInfo{ImportPath: "sync", Imports: []string{"unsafe"}, Deps: []string{"unsafe"}})
+	dg.Add(DepInfo{ImportPath: "unsafe"})
+
+	if !dg.UsesUnsafe("example.com/app/mmap") || !dg.UsesUnsafe("sync") || dg.UsesUnsafe("example.com/app/sqlite") {
+		t.Error("UsesUnsafe error")
+	}
+	for _, f := range []*DepGraph{dg, dg.Freeze()} {
+		mains := f.UnsafeMains()
+		if len(mains) != 2 || strings.Join(mains["example.com/app/cmd/a"], " ") != "example.com/app/mmap" || strings.Join(mains["example.com/app/cmd/b"], " ") != "example.com/app/sqlite" {
+			t.Error(mains)
+		}
+	}
+}
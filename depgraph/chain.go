@@ -0,0 +1,110 @@
+package depgraph
+
+import "sort"
+
+// Chain is an import chain from a main package to a target, see
+// SearchChains. It is the structured form of the chains of SearchChain,
+// without the "main" pseudo-package and the "..." marker.
+type Chain struct {
+	Main     string   // main package, or root of SearchChainsFrom, the chain starts at
+	Target   string   // package searched for
+	Packages []string // Main -> ... -> Target, [Target] if Main is Target
+	Hops     []Hop    // the imports between Packages, nil if !Complete
+	// Complete is false when Main depends on Target but no import path
+	// between them is in the graph, eg: packages are missing from the
+	// input. Packages is then [Main, Target] and Gaps tells which
+	// packages to regenerate the input of.
+	Complete bool
+	Gaps     []Gap
+}
+
+// Hop is one import of a Chain.
+type Hop struct {
+	From  string
+	To    string
+	Attrs EdgeAttrs
+}
+
+// Len returns the number of imports of c, 0 when Main is Target and -1
+// if the chain is not complete.
+func (c Chain) Len() int {
+	if !c.Complete {
+		return -1
+	}
+	return len(c.Packages) - 1
+}
+
+// Strings returns c in the form of SearchChain: "main", the packages and
+// "..." where the chain is not complete.
+func (c Chain) Strings() []string {
+	chain := append([]string{"main"}, c.Packages...)
+	if !c.Complete {
+		chain = []string{"main", c.Main, "...", c.Target}
+	}
+	return chain
+}
+
+// SearchChains is SearchChain returning Chains, sorted by main package.
+func (g *DepGraph) SearchChains(packageName string) []Chain {
+	chains := make([]Chain, 0)
+	for _, chain := range g.SearchChain(packageName) {
+		chains = append(chains, g.structuredChain(chain[1:]))
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i].Main < chains[j].Main })
+	return chains
+}
+
+// SearchChainFrom is SearchChain from roots instead of the main packages,
+// eg: the packages of a library in a repository without main packages. It
+// returns one chain root -> ... -> packageName per root depending on it,
+// in the order of roots, without the leading "main".
+func (g *DepGraph) SearchChainFrom(roots []string, packageName string) (chains [][]string) {
+	target, ok := g.lookup(packageName)
+	if !ok {
+		return
+	}
+	deadEnds := make(map[nodeID]bool)
+	for _, root := range roots {
+		id, ok := g.lookup(root)
+		if !ok || id != target && !g.dependsOn(id, target) {
+			continue
+		}
+		chains = append(chains, g.chainFrom(root, packageName, deadEnds)[1:])
+	}
+	return
+}
+
+// SearchChainsFrom is SearchChainFrom returning Chains, whose Main is the
+// root, in the order of roots.
+func (g *DepGraph) SearchChainsFrom(roots []string, packageName string) []Chain {
+	chains := make([]Chain, 0)
+	for _, chain := range g.SearchChainFrom(roots, packageName) {
+		chains = append(chains, g.structuredChain(chain))
+	}
+	return chains
+}
+
+// structuredChain turns a chain of SearchChainFrom, or of SearchChain
+// without "main", into a Chain.
+func (g *DepGraph) structuredChain(packages []string) Chain {
+	c := Chain{Main: packages[0], Target: packages[len(packages)-1], Complete: true}
+	for i, p := range packages {
+		if p == "..." {
+			c.Complete = false
+			continue
+		}
+		c.Packages = append(c.Packages, p)
+		if i > 0 && c.Complete {
+			hop := Hop{From: packages[i-1], To: p}
+			if e := g.Edge(hop.From, hop.To); e != nil {
+				hop.Attrs = *e
+			}
+			c.Hops = append(c.Hops, hop)
+		}
+	}
+	if !c.Complete {
+		c.Hops = nil
+		c.Gaps = g.ChainGaps(c.Main, c.Target)
+	}
+	return c
+}
@@ -0,0 +1,69 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchChains(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"lib"}, Deps: []string{"lib", "z"}})
+	dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"z"}, Deps: []string{"z"}})
+	// cmd/b claims z in Deps without importing a path to it
+	dg.Add(DepInfo{ImportPath: "cmd/b", Name: "main", Deps: []string{"z"}})
+	dg.Add(DepInfo{ImportPath: "z", Name: "main"})
+	dg.SetEdge("lib", "z", EdgeAttrs{Pos: []string{"lib.go:3"}})
+
+	chains := dg.SearchChains("z")
+	if len(chains) != 3 {
+		t.Fatal(chains)
+	}
+	a, b, z := chains[0], chains[1], chains[2]
+	if a.Main != "cmd/a" || a.Target != "z" || !a.Complete || a.Len() != 2 ||
+		!reflect.DeepEqual(a.Packages, []string{"cmd/a", "lib", "z"}) {
+		t.Error(a)
+	}
+	if len(a.Hops) != 2 || a.Hops[0].From != "cmd/a" || a.Hops[1].To != "z" ||
+		!reflect.DeepEqual(a.Hops[1].Attrs.Pos, []string{"lib.go:3"}) {
+		t.Error(a.Hops)
+	}
+	if !reflect.DeepEqual(a.Strings(), []string{"main", "cmd/a", "lib", "z"}) {
+		t.Error(a.Strings())
+	}
+	if b.Complete || b.Len() != -1 || b.Hops != nil || !reflect.DeepEqual(b.Packages, []string{"cmd/b", "z"}) {
+		t.Error(b)
+	}
+	if !reflect.DeepEqual(b.Strings(), []string{"main", "cmd/b", "...", "z"}) {
+		t.Error(b.Strings())
+	}
+	if !z.Complete || z.Len() != 0 || len(z.Packages) != 1 || z.Main != "z" {
+		t.Error(z)
+	}
+	if chains := dg.SearchChains("missing"); chains == nil || len(chains) != 0 {
+		t.Error(chains)
+	}
+}
+
+func TestSearchChainFrom(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "lib/a", Name: "a", Imports: []string{"lib/b", "fmt"}, Deps: []string{"fmt", "lib/b", "x"}})
+	dg.Add(DepInfo{ImportPath: "lib/b", Name: "b", Imports: []string{"x"}, Deps: []string{"x"}})
+	dg.Add(DepInfo{ImportPath: "lib/c", Name: "c", Deps: []string{"x"}})
+	dg.Add(DepInfo{ImportPath: "x", Name: "x"})
+	dg.Add(DepInfo{ImportPath: "fmt", Name: "fmt"})
+	if chains := dg.SearchChain("x"); len(chains) != 0 {
+		t.Error(chains)
+	}
+	chains := dg.SearchChainFrom([]string{"lib/c", "fmt", "lib/a", "x", "nope"}, "x")
+	if !reflect.DeepEqual(chains, [][]string{{"lib/c", "...", "x"}, {"lib/a", "lib/b", "x"}, {"x"}}) {
+		t.Error(chains)
+	}
+	if chains := dg.SearchChainFrom([]string{"lib/a"}, "nope"); chains != nil {
+		t.Error(chains)
+	}
+	structured := dg.SearchChainsFrom([]string{"lib/a", "lib/c"}, "x")
+	if len(structured) != 2 || structured[0].Main != "lib/a" || structured[0].Len() != 2 ||
+		structured[1].Complete || len(structured[1].Gaps) != 1 {
+		t.Error(structured)
+	}
+}
@@ -0,0 +1,137 @@
+package depgraph
+
+// closure returns, for every node, the bitset of packages it transitively
+// imports. It is computed once and cached until the graph changes, so
+// PathExists and similar checks cost a single bit test per pair.
+func (g *DepGraph) closure() []bitset {
+	if g.frozen {
+		g.closureOnce.Do(func() {
+			g.reach = packBitsets(g.computeClosure())
+		})
+		return g.reach
+	}
+	if g.reach == nil {
+		g.reach = g.computeClosure()
+	}
+	return g.reach
+}
+
+func (g *DepGraph) computeClosure() []bitset {
+	order, cyclic := g.postorder(nil)
+	reach := make([]bitset, len(g.names))
+	for changed := true; changed; {
+		changed = false
+		for _, id := range order {
+			var b bitset
+			for _, e := range g.imports[id] {
+				if !e.inBuild() {
+					continue
+				}
+				b.set(e.to)
+				b.or(reach[e.to])
+			}
+			if !b.equal(reach[id]) {
+				reach[id] = b
+				changed = true
+			}
+		}
+		// in postorder every import is final before its importer, so one
+		// pass is enough unless the graph has a cycle
+		changed = changed && cyclic
+	}
+	return reach
+}
+
+// postorder returns every node after all the nodes it imports, and
+// reports whether an import cycle prevented that for some of them. If
+// include is not nil only the nodes it accepts are visited.
+func (g *DepGraph) postorder(include func(id nodeID) bool) (order []nodeID, cyclic bool) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]uint8, len(g.names))
+	type frame struct {
+		id   nodeID
+		next int
+	}
+	if include != nil {
+		for id := range state {
+			if !include(nodeID(id)) {
+				state[id] = visited
+			}
+		}
+	}
+	for root := range g.names {
+		if state[root] != unvisited {
+			continue
+		}
+		stack := []frame{{id: nodeID(root)}}
+		state[root] = visiting
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+			if top.next < len(g.imports[top.id]) {
+				e := g.imports[top.id][top.next]
+				to := e.to
+				top.next++
+				if !e.inBuild() {
+					continue
+				}
+				switch state[to] {
+				case unvisited:
+					state[to] = visiting
+					stack = append(stack, frame{id: to})
+				case visiting:
+					cyclic = true
+				}
+				continue
+			}
+			state[top.id] = visited
+			order = append(order, top.id)
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return
+}
+
+// updateClosure brings a cached closure up to date after the imports of
+// changed were replaced. Only changed and the packages importing it,
+// directly or not, can reach something different, so just their bitsets
+// are recomputed; everything below them is still valid.
+func (g *DepGraph) updateClosure(changed nodeID) {
+	if g.reach == nil {
+		return
+	}
+	for len(g.reach) < len(g.names) {
+		g.reach = append(g.reach, bitset{})
+	}
+	affected := map[nodeID]bool{changed: true}
+	queue := []nodeID{changed}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, importer := range g.importers[id] {
+			if !affected[importer] {
+				affected[importer] = true
+				queue = append(queue, importer)
+			}
+		}
+	}
+	order, cyclic := g.postorder(func(id nodeID) bool { return affected[id] })
+	if cyclic {
+		g.reach = nil
+		return
+	}
+	for _, id := range order {
+		var b bitset
+		for _, e := range g.imports[id] {
+			if !e.inBuild() {
+				continue
+			}
+			b.set(e.to)
+			b.or(g.reach[e.to])
+		}
+		g.reach[id] = b
+	}
+}
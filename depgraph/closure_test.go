@@ -0,0 +1,82 @@
+package depgraph
+
+import "testing"
+
+func TestClosure(t *testing.T) {
+	dg := loadTestGraph(t)
+	reach := dg.closure()
+	for _, p := range []string{"net/http", "cmd/vet", "fmt", "runtime"} {
+		id, _ := dg.lookup(p)
+		expect := dg.Descendants(p)
+		if reach[id].count() != len(expect) {
+			t.Error(p, reach[id].count(), len(expect))
+		}
+		reach[id].each(func(dep nodeID) {
+			if !expect[dg.names[dep]] {
+				t.Error(p, "should not reach", dg.names[dep])
+			}
+		})
+	}
+
+	cyclic := &DepGraph{}
+	cyclic.AddEdge("a", "b")
+	cyclic.AddEdge("b", "c")
+	cyclic.AddEdge("c", "a")
+	cyclic.AddEdge("c", "d")
+	if !cyclic.PathExists("a", "d") || !cyclic.PathExists("b", "a") || cyclic.PathExists("d", "a") {
+		t.Error("closure error on cyclic graph")
+	}
+}
+
+func TestBitset(t *testing.T) {
+	var b bitset
+	for _, id := range []nodeID{700, 3, 130} {
+		b.set(id)
+	}
+	var got []nodeID
+	b.each(func(id nodeID) { got = append(got, id) })
+	if len(got) != 3 || got[0] != 3 || got[1] != 130 || got[2] != 700 {
+		t.Error(got)
+	}
+	if !b.has(130) || b.has(131) || b.has(100000) {
+		t.Error("has error")
+	}
+	var c bitset
+	c.set(5000)
+	c.or(b)
+	if c.count() != 4 || !b.subsetOf(c) || c.subsetOf(b) || b.equal(c) {
+		t.Error("or error")
+	}
+}
+
+func TestIncrementalClosure(t *testing.T) {
+	dg := loadTestGraph(t)
+	dg.closure()
+	dg.Add(DepInfo{ImportPath: "x", Name: "x", Deps: []string{"net/http"}, Imports: []string{"net/http"}})
+	dg.Add(DepInfo{ImportPath: "errors", Name: "errors", Imports: []string{"z"}})
+	dg.AddEdge("net/url", "y")
+	dg.Remove("net/http")
+	if dg.Exists("net/http") || dg.IsMainPackage("net/http") {
+		t.Error("net/http should be removed")
+	}
+	if sliceContains(dg.Importers("net/url"), "net/http") {
+		t.Error("importers of net/url should be updated")
+	}
+	if dg.reach == nil {
+		t.Fatal("closure should be kept")
+	}
+	full := dg.computeClosure()
+	for id := range dg.names {
+		if !dg.reach[id].equal(full[id]) {
+			t.Error("incremental closure differs for", dg.names[id])
+		}
+	}
+	if !dg.PathExists("cmd/vet", "z") || !dg.PathExists("x", "net/http") || dg.PathExists("x", "y") {
+		t.Error("PathExists error")
+	}
+	// an import cycle falls back to recomputing the closure
+	dg.AddEdge("z", "cmd/vet")
+	if !dg.PathExists("z", "z") || !dg.PathExists("z", "runtime") || !dg.PathExists("cmd/vet", "cmd/vet") {
+		t.Error("PathExists error after cycle")
+	}
+}
@@ -0,0 +1,75 @@
+package depgraph
+
+import "sort"
+
+// Conflict records a package added more than once with different imports
+// or deps, eg: when dumps from different build configurations are merged.
+// The last record wins; Conflict keeps what it changed so the merge isn't
+// silently lossy.
+type Conflict struct {
+	Package        string
+	ImportsAdded   []string // imports of the last record the previous one didn't have
+	ImportsRemoved []string // imports of the previous record the last one dropped
+	DepsAdded      []string
+	DepsRemoved    []string
+}
+
+// Conflicts returns the packages added with conflicting records, sorted
+// by package.
+func (g *DepGraph) Conflicts() (conflicts []Conflict) {
+	for _, c := range g.conflicts {
+		conflicts = append(conflicts, *c)
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Package < conflicts[j].Package })
+	return
+}
+
+// recordConflict compares the record of the already loaded id with the
+// imports and sorted deps replacing it.
+func (g *DepGraph) recordConflict(id nodeID, imports []edge, deps []nodeID) {
+	var before, after []nodeID
+	for _, e := range g.imports[id] {
+		if e.inBuild() {
+			before = append(before, e.to)
+		}
+	}
+	for _, e := range imports {
+		after = append(after, e.to)
+	}
+	sortIDs(before)
+	sortIDs(after)
+	c := &Conflict{
+		Package:        g.names[id],
+		ImportsAdded:   g.sortedPaths(subtractIDs(after, before)),
+		ImportsRemoved: g.sortedPaths(subtractIDs(before, after)),
+		DepsAdded:      g.sortedPaths(subtractIDs(deps, g.deps[id])),
+		DepsRemoved:    g.sortedPaths(subtractIDs(g.deps[id], deps)),
+	}
+	if len(c.ImportsAdded)+len(c.ImportsRemoved)+len(c.DepsAdded)+len(c.DepsRemoved) == 0 {
+		return
+	}
+	if g.conflicts == nil {
+		g.conflicts = make(map[nodeID]*Conflict)
+	}
+	g.conflicts[id] = c
+}
+
+func (g *DepGraph) sortedPaths(ids []nodeID) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	paths := g.pathsOf(ids)
+	sort.Strings(paths)
+	return paths
+}
+
+// subtractIDs returns the elements of the sorted set a not in b.
+func subtractIDs(a, b []nodeID) (result []nodeID) {
+	for _, id := range a {
+		i := sort.Search(len(b), func(i int) bool { return b[i] >= id })
+		if i == len(b) || b[i] != id {
+			result = append(result, id)
+		}
+	}
+	return
+}
@@ -0,0 +1,36 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConflicts(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"fmt", "syscall"}, Deps: []string{"fmt", "syscall"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Name: "fmt"})
+	// same record again, eg: from overlapping dumps
+	dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"syscall", "fmt"}, Deps: []string{"fmt", "syscall"}})
+	if c := dg.Conflicts(); len(c) != 0 {
+		t.Fatal("identical records should not conflict", c)
+	}
+	dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"fmt", "golang.org/x/sys/windows"},
+		Deps: []string{"fmt", "golang.org/x/sys/windows"}})
+	expect := []Conflict{{
+		Package:        "lib",
+		ImportsAdded:   []string{"golang.org/x/sys/windows"},
+		ImportsRemoved: []string{"syscall"},
+		DepsAdded:      []string{"golang.org/x/sys/windows"},
+		DepsRemoved:    []string{"syscall"},
+	}}
+	if c := dg.Freeze().Conflicts(); !reflect.DeepEqual(c, expect) {
+		t.Error(c)
+	}
+	if imports := dg.Imports("lib"); !reflect.DeepEqual(imports, []string{"fmt", "golang.org/x/sys/windows"}) {
+		t.Error("last record should win", imports)
+	}
+	dg.Remove("lib")
+	if c := dg.Conflicts(); len(c) != 0 {
+		t.Error(c)
+	}
+}
@@ -0,0 +1,77 @@
+package depgraph
+
+import "sort"
+
+// DeadSubtree is a part of a binary none of whose packages ran, see
+// DeadSubtrees.
+type DeadSubtree struct {
+	Root     string   // the package whose import to drop
+	Packages []string // Root and the packages only it brings in, standard library left out, sorted
+	Chain    []string // the shortest import chain from the main package to Root
+}
+
+// DeadSubtrees returns the subtrees of the dominator tree of the build
+// imports of mainPackage, see SizeSubtrees, none of whose packages are in
+// executed, eg: the packages a coverage profile of the binary in
+// production has statements run of: linked in but never executed, dropping
+// the import of Root drops them all. Standard library packages, seldom
+// instrumented, neither make a subtree nor keep one alive. The result is
+// sorted by size, largest first, then by Root, and is nil if mainPackage
+// didn't run.
+func (g *DepGraph) DeadSubtrees(mainPackage string, executed map[string]bool) []DeadSubtree {
+	start, ok := g.lookup(mainPackage)
+	if !ok || !executed[mainPackage] {
+		return nil
+	}
+	post, idom := g.dominators(start)
+	// dead tells the packages whose dominator subtree has no executed
+	// package, children come before their dominator in postorder
+	dead := make(map[nodeID]bool, len(post))
+	for _, id := range post {
+		dead[id] = true
+	}
+	for _, id := range post {
+		if !g.isStandard(id) && executed[g.names[id]] {
+			dead[id] = false
+		}
+		if !dead[id] && id != start {
+			dead[idom[id]] = false
+		}
+	}
+	isRoot := func(id nodeID) bool {
+		return dead[id] && !g.isStandard(id) && !(dead[idom[id]] && !g.isStandard(idom[id]))
+	}
+	subtrees := make(map[nodeID]*DeadSubtree)
+	for _, id := range post {
+		if !dead[id] || g.isStandard(id) {
+			continue
+		}
+		root := id
+		for !isRoot(root) {
+			root = idom[root]
+		}
+		s := subtrees[root]
+		if s == nil {
+			s = &DeadSubtree{Root: g.names[root]}
+			subtrees[root] = s
+		}
+		s.Packages = append(s.Packages, g.names[id])
+	}
+	parent := g.buildWalk(start)
+	result := make([]DeadSubtree, 0, len(subtrees))
+	for root, s := range subtrees {
+		sort.Strings(s.Packages)
+		for p := root; p >= 0; p = parent[p] {
+			s.Chain = append(s.Chain, g.names[p])
+		}
+		reverseSlice(s.Chain)
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Packages) != len(result[j].Packages) {
+			return len(result[i].Packages) > len(result[j].Packages)
+		}
+		return result[i].Root < result[j].Root
+	})
+	return result
+}
@@ -0,0 +1,41 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeadSubtrees(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/api", Name: "main",
+		Imports: []string{"example.com/server", "example.com/export"}})
+	dg.Add(DepInfo{ImportPath: "example.com/server", Imports: []string{"example.com/log", "example.com/legacy"}})
+	dg.Add(DepInfo{ImportPath: "example.com/export", Imports: []string{"example.com/pdf", "example.com/log"}})
+	dg.Add(DepInfo{ImportPath: "example.com/pdf", Imports: []string{"example.com/fonts", "compress/zlib"}})
+	dg.Add(DepInfo{ImportPath: "example.com/fonts"})
+	dg.Add(DepInfo{ImportPath: "example.com/legacy", Imports: []string{"example.com/log"}})
+	dg.Add(DepInfo{ImportPath: "example.com/log"})
+	dg.Add(DepInfo{ImportPath: "compress/zlib", Standard: true})
+
+	executed := map[string]bool{"example.com/cmd/api": true, "example.com/server": true}
+	expect := []DeadSubtree{
+		{Root: "example.com/export", Chain: []string{"example.com/cmd/api", "example.com/export"},
+			Packages: []string{"example.com/export", "example.com/fonts", "example.com/pdf"}},
+		{Root: "example.com/legacy", Chain: []string{"example.com/cmd/api", "example.com/server", "example.com/legacy"},
+			Packages: []string{"example.com/legacy"}},
+		{Root: "example.com/log", Chain: []string{"example.com/cmd/api", "example.com/server", "example.com/log"},
+			Packages: []string{"example.com/log"}},
+	}
+	if got := dg.DeadSubtrees("example.com/cmd/api", executed); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	// a package running keeps the subtrees of its dominators alive
+	executed["example.com/fonts"] = true
+	if got := dg.DeadSubtrees("example.com/cmd/api", executed); len(got) != 2 || got[0].Root != "example.com/legacy" {
+		t.Error(got)
+	}
+	delete(executed, "example.com/cmd/api")
+	if got := dg.DeadSubtrees("example.com/cmd/api", executed); got != nil {
+		t.Error("not run", got)
+	}
+}
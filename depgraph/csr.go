@@ -0,0 +1,149 @@
+package depgraph
+
+// Freeze returns an immutable copy of g laid out in compressed sparse row
+// form: the adjacency lists of all packages are packed back to back into
+// one array per relation, so traversals walk contiguous memory instead of
+// chasing a separately allocated slice per package. Every query works on
+// the frozen graph unchanged and, since nothing can change it any more,
+// it is safe for concurrent use. Add, AddEdge and SetEdge panic.
+func (g *DepGraph) Freeze() *DepGraph {
+	g.lazyInit()
+	f := &DepGraph{
+		ids:          make(map[string]nodeID, len(g.ids)),
+		names:        append([]string(nil), g.names...),
+		loaded:       append([]bool(nil), g.loaded...),
+		imports:      packEdges(g.imports),
+		deps:         packIDs(g.deps),
+		importers:    packIDs(g.importers),
+		dependents:   packIDs(g.dependents),
+		mainPackages: make(map[nodeID]bool, len(g.mainPackages)),
+		testPackages: make(map[nodeID]bool, len(g.testPackages)),
+		cgoPackages:  make(map[nodeID]bool, len(g.cgoPackages)),
+		standard:     make(map[nodeID]bool, len(g.standard)),
+		sizes:        make(map[nodeID]Size, len(g.sizes)),
+		dirs:         make(map[nodeID]string, len(g.dirs)),
+		embeds:       make(map[nodeID][]string, len(g.embeds)),
+		pkgNames:     make(map[nodeID]string, len(g.pkgNames)),
+		sawStandard:  g.sawStandard,
+		conflicts:    make(map[nodeID]*Conflict, len(g.conflicts)),
+		modules:      make(map[nodeID]*Module, len(g.modules)),
+		weights:      make(map[string]float64, len(g.weights)),
+		tools:        make(map[string]bool, len(g.tools)),
+		replaces:     make(map[string]string, len(g.replaces)),
+		concurrency:  g.concurrency,
+		ignoreDeps:   g.ignoreDeps,
+		firstParty:   g.firstParty,
+		build:        g.build,
+		frozen:       true,
+	}
+	for k, v := range g.ids {
+		f.ids[k] = v
+	}
+	for k, v := range g.mainPackages {
+		f.mainPackages[k] = v
+	}
+	for k, v := range g.testPackages {
+		f.testPackages[k] = v
+	}
+	for k, v := range g.cgoPackages {
+		f.cgoPackages[k] = v
+	}
+	for k, v := range g.standard {
+		f.standard[k] = v
+	}
+	for k, v := range g.sizes {
+		f.sizes[k] = v
+	}
+	for k, v := range g.dirs {
+		f.dirs[k] = v
+	}
+	for k, v := range g.embeds {
+		f.embeds[k] = v
+	}
+	for k, v := range g.pkgNames {
+		f.pkgNames[k] = v
+	}
+	for k, v := range g.conflicts {
+		f.conflicts[k] = v
+	}
+	for k, v := range g.modules {
+		m := *v
+		f.modules[k] = &m
+	}
+	for k, v := range g.replaces {
+		f.replaces[k] = v
+	}
+	for k, v := range g.weights {
+		f.weights[k] = v
+	}
+	for k, v := range g.tools {
+		f.tools[k] = v
+	}
+	if g.cache != nil {
+		f.cache = newQueryCache(g.cache.size)
+	}
+	return f
+}
+
+// Frozen reports whether g was returned by Freeze.
+func (g *DepGraph) Frozen() bool {
+	return g.frozen
+}
+
+func (g *DepGraph) mustNotBeFrozen() {
+	if g.frozen {
+		panic("depgraph: modifying a frozen graph")
+	}
+}
+
+func packIDs(lists [][]nodeID) [][]nodeID {
+	total := 0
+	for _, l := range lists {
+		total += len(l)
+	}
+	all := make([]nodeID, 0, total)
+	packed := make([][]nodeID, len(lists))
+	for i, l := range lists {
+		start := len(all)
+		all = append(all, l...)
+		packed[i] = all[start:len(all):len(all)]
+	}
+	return packed
+}
+
+func packEdges(lists [][]edge) [][]edge {
+	total := 0
+	for _, l := range lists {
+		total += len(l)
+	}
+	all := make([]edge, 0, total)
+	attrs := make([]EdgeAttrs, 0, total)
+	packed := make([][]edge, len(lists))
+	for i, l := range lists {
+		if l == nil {
+			continue
+		}
+		start := len(all)
+		for _, e := range l {
+			attrs = append(attrs, *e.attrs)
+			all = append(all, edge{to: e.to, attrs: &attrs[len(attrs)-1]})
+		}
+		packed[i] = all[start:len(all):len(all)]
+	}
+	return packed
+}
+
+func packBitsets(sets []bitset) []bitset {
+	total := 0
+	for _, b := range sets {
+		total += len(b.words)
+	}
+	all := make([]uint64, 0, total)
+	packed := make([]bitset, len(sets))
+	for i, b := range sets {
+		start := len(all)
+		all = append(all, b.words...)
+		packed[i] = bitset{off: b.off, words: all[start:len(all):len(all)]}
+	}
+	return packed
+}
@@ -0,0 +1,57 @@
+package depgraph
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	dg := loadTestGraph(t)
+	f := dg.Freeze()
+	if !f.Frozen() || dg.Frozen() {
+		t.Error("Frozen error")
+	}
+	if f.CountAll() != dg.CountAll() || f.CountMain() != dg.CountMain() || f.CountTest() != dg.CountTest() {
+		t.Error("count error")
+	}
+	sorted := func(s []string) string {
+		sort.Strings(s)
+		return strings.Join(s, " ")
+	}
+	var wg sync.WaitGroup
+	for _, p := range []string{"fmt", "net/url", "net", "cmd/vet"} {
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+			if sorted(f.SearchAll(p)) != sorted(dg.SearchAll(p)) {
+				t.Error("SearchAll differs", p)
+			}
+			if len(f.SearchChain(p)) != len(dg.SearchChain(p)) {
+				t.Error("SearchChain differs", p)
+			}
+			if strings.Join(f.Importers(p), " ") != strings.Join(dg.Importers(p), " ") {
+				t.Error("Importers differs", p)
+			}
+			if !f.PathExists("cmd/vet", "runtime") {
+				t.Error("PathExists error")
+			}
+		}(p)
+	}
+	wg.Wait()
+	if e := f.Edge("cmd/pprof", "cmd/vendor/github.com/google/pprof/driver"); e == nil || !e.Vendored {
+		t.Error("edge attrs should be kept", e)
+	}
+
+	dg.AddEdge("fmt", "x")
+	if f.Exists("x") || f.PathExists("fmt", "x") {
+		t.Error("frozen graph should not see later changes")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("AddEdge on a frozen graph should panic")
+		}
+	}()
+	f.AddEdge("fmt", "x")
+}
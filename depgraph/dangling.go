@@ -0,0 +1,36 @@
+package depgraph
+
+import "sort"
+
+// Dangling returns the packages some loaded package imports or depends on
+// but that were never added themselves, each mapped to the sorted loaded
+// packages importing it, or depending on it when none imports it
+// directly. Queries through such packages give "..." chains, so an
+// incomplete dump is better diagnosed upfront.
+func (g *DepGraph) Dangling() map[string][]string {
+	result := make(map[string][]string)
+	for _, id := range g.filterNodes(func(id nodeID) bool {
+		return !g.loaded[id] && g.names[id] != "main" && g.referenced(id)
+	}) {
+		referrers := g.loadedOf(g.importers[id])
+		if len(referrers) == 0 {
+			referrers = g.loadedOf(g.dependents[id])
+		}
+		sort.Strings(referrers)
+		result[g.names[id]] = referrers
+	}
+	return result
+}
+
+func (g *DepGraph) referenced(id nodeID) bool {
+	return len(g.loadedOf(g.importers[id])) > 0 || len(g.loadedOf(g.dependents[id])) > 0
+}
+
+func (g *DepGraph) loadedOf(ids []nodeID) (packages []string) {
+	for _, id := range ids {
+		if g.loaded[id] {
+			packages = append(packages, g.names[id])
+		}
+	}
+	return
+}
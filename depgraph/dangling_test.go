@@ -0,0 +1,25 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDangling(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"lib", "missing/direct"},
+		Deps: []string{"lib", "missing/direct", "missing/deep", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"fmt", "missing/direct"},
+		Deps: []string{"fmt", "missing/direct"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Name: "fmt"})
+	expect := map[string][]string{
+		"missing/direct": {"cmd/a", "lib"},
+		"missing/deep":   {"cmd/a"},
+	}
+	if got := dg.Dangling(); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	if got := loadTestGraph(t).Dangling(); len(got) != 0 {
+		t.Error("complete dump has dangling references", got)
+	}
+}
@@ -4,15 +4,34 @@ import (
 	"container/list"
 	"encoding/json"
 	"io"
+	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 )
 
 type DepInfo struct {
 	ImportPath string   `json:"ImportPath"` // import path of package in dir
 	Name       string   `json:"Name"`       // package name
+	Standard   bool     `json:"Standard"`   // is this package part of the standard Go library?
 	Deps       []string `json:"Deps"`       // all (recursively) imported dependencies
 	Imports    []string `json:"Imports"`    // import paths used by this package
+
+	ImportMap map[string]string `json:"ImportMap"` // map from source import to ImportPath (identity entries omitted)
+	Module    *Module           `json:"Module"`    // info about package's containing module, if any
+
+	ForTest string `json:"ForTest,omitempty"` // package is only for use in named test, see TestByForTest
+
+	Dir     string   `json:"Dir"`             // directory containing package sources
+	GoFiles []string `json:"GoFiles"`         // .go source files (excluding CgoFiles, TestGoFiles, XTestGoFiles)
+	Lines   int      `json:"Lines,omitempty"` // lines of GoFiles, not set by go list, see CountLines
+
+	EmbedPatterns []string `json:"EmbedPatterns,omitempty"` // //go:embed patterns
+	EmbedFiles    []string `json:"EmbedFiles,omitempty"`    // files matched by EmbedPatterns, relative to Dir
+
+	// "file:line" of the imports by import path as written in the source,
+	// not set by go list, see RecordImportPos
+	ImportPos map[string][]string `json:"ImportPos,omitempty"`
 }
 
 func (d *DepInfo) ImportsMap() map[string]bool {
@@ -32,44 +51,209 @@ func (d *DepInfo) DepsMap() map[string]bool {
 }
 
 type DepGraph struct {
-	imports      map[string]map[string]bool
-	allDeps      map[string]map[string]bool
-	mainPackages map[string]bool
-	testPackages map[string]bool
+	ids     map[string]nodeID
+	names   []string   // nodeID -> import path
+	loaded  []bool     // a record for the package has been added
+	imports [][]edge   // direct imports, nil for packages never added
+	deps    [][]nodeID // all (recursively) imported dependencies, sorted
+
+	importers  [][]nodeID // reverse of imports, see setImports
+	dependents [][]nodeID // reverse of deps, see setDeps
+
+	mainPackages map[nodeID]bool
+	testPackages map[nodeID]bool
+	cgoPackages  map[nodeID]bool // import "C"
+	standard     map[nodeID]bool // Standard field of go list, see IsStandard
+	sizes        map[nodeID]Size
+	dirs         map[nodeID]string   // Dir field of go list
+	pkgNames     map[nodeID]string   // Name field of go list
+	embeds       map[nodeID][]string // EmbedFiles field of go list, see EmbedFiles
+	sawStandard  bool                // some record had the Standard field set
+	conflicts    map[nodeID]*Conflict
+	modules      map[nodeID]*Module
+	replaces     map[string]string // replaced module path -> replacement
+
+	reach   []bitset // cached transitive closure, reset on change
+	weights map[string]float64
+	tools   map[string]bool // see AddTool
+
+	concurrency     int
+	ignoreDeps      bool
+	normalizeVendor bool
+	countLines      bool
+	recordImportPos bool
+	testClassifier  TestClassifier // see SetTestClassifier, nil for TestBySuffix
+	firstParty      Grouper        // see FirstParty, nil for the main module
+	build           BuildProfile   // see SetBuild
+	limits          Limits         // see SetLimits
+	frozen          bool
+	closureOnce     sync.Once // guards reach on frozen graphs
+	cache           *queryCache
 }
 
-func (g *DepGraph) Add(d DepInfo) {
-	if g.imports == nil {
-		g.imports = make(map[string]map[string]bool)
-		g.imports["main"] = make(map[string]bool)
+func (g *DepGraph) lazyInit() {
+	if g.ids == nil {
+		id := g.intern("main")
+		g.imports[id] = []edge{}
 	}
 	if g.mainPackages == nil {
-		g.mainPackages = make(map[string]bool)
+		g.mainPackages = make(map[nodeID]bool)
 	}
 	if g.testPackages == nil {
-		g.testPackages = make(map[string]bool)
+		g.testPackages = make(map[nodeID]bool)
+	}
+	if g.modules == nil {
+		g.modules = make(map[nodeID]*Module)
 	}
-	if g.allDeps == nil {
-		g.allDeps = make(map[string]map[string]bool)
+	if g.cgoPackages == nil {
+		g.cgoPackages = make(map[nodeID]bool)
 	}
-	if strings.HasSuffix(d.ImportPath, "]") { // skip test package
+	if g.standard == nil {
+		g.standard = make(map[nodeID]bool)
+	}
+	if g.sizes == nil {
+		g.sizes = make(map[nodeID]Size)
+	}
+	if g.dirs == nil {
+		g.dirs = make(map[nodeID]string)
+	}
+	if g.pkgNames == nil {
+		g.pkgNames = make(map[nodeID]string)
+	}
+	if g.embeds == nil {
+		g.embeds = make(map[nodeID][]string)
+	}
+}
+
+func (g *DepGraph) Add(d DepInfo) {
+	g.mustNotBeFrozen()
+	g.lazyInit()
+	var origins map[string]string
+	if g.normalizeVendor {
+		origins = d.unvendor()
+	}
+	if _, ok := testVariantBase(d.ImportPath); ok {
+		d.foldTestVariants()
+		g.addTestImports(d)
+		g.changed()
 		return
 	}
-	isTestPackage := strings.HasSuffix(d.ImportPath, ".test")
+	id := g.intern(d.ImportPath)
+	wasLoaded := g.loaded[id]
+	// a new record may turn a main package into a library, so the flags
+	// of the previous one are dropped
+	delete(g.mainPackages, id)
+	delete(g.testPackages, id)
 	if d.Name == "main" {
-		if isTestPackage {
-			g.testPackages[d.ImportPath] = true
+		if g.isTestBinary(&d) {
+			g.testPackages[id] = true
 		} else {
-			g.mainPackages[d.ImportPath] = true
+			g.mainPackages[id] = true
 		}
 	}
-	g.imports[d.ImportPath] = d.ImportsMap()
-	g.allDeps[d.ImportPath] = d.DepsMap()
+	// the classifier may look at the variants d imports, so they are only
+	// folded now
+	d.foldTestVariants()
+	g.loaded[id] = true
+	if g.countLines && d.Lines == 0 {
+		d.Lines = countLines(d.Dir, d.GoFiles)
+	}
+	g.sizes[id] = Size{Files: len(d.GoFiles), Lines: d.Lines}
+	if d.Dir != "" {
+		g.dirs[id] = filepath.Clean(d.Dir)
+	} else {
+		delete(g.dirs, id)
+	}
+	if d.Name != "" {
+		g.pkgNames[id] = d.Name
+	} else {
+		delete(g.pkgNames, id)
+	}
+	if len(d.EmbedFiles) > 0 {
+		g.embeds[id] = d.EmbedFiles
+	} else {
+		delete(g.embeds, id)
+	}
+	if d.Standard {
+		g.standard[id] = true
+		g.sawStandard = true
+	} else {
+		delete(g.standard, id)
+	}
+	if d.Module != nil {
+		g.modules[id] = d.Module
+		if d.Module.Replace != nil {
+			g.AddReplace(d.Module.Path, d.Module.Replace.Path)
+		}
+	} else {
+		delete(g.modules, id)
+	}
+	delete(g.cgoPackages, id)
+	edges := d.edges()
+	for p, origin := range origins {
+		edges[p].Vendored = true
+		edges[p].VendorPath = origin
+	}
+	if g.recordImportPos && d.ImportPos == nil {
+		d.ImportPos = importPos(d.Dir, d.GoFiles)
+	}
+	if d.ImportPos != nil {
+		d.setImportPos(edges, origins)
+	}
+	imports := make([]edge, 0, len(d.Imports))
+	for _, p := range d.Imports {
+		if p == "C" { // cgo pseudo-import, not a package
+			g.cgoPackages[id] = true
+			continue
+		}
+		imports = append(imports, edge{to: g.intern(p), attrs: edges[p]})
+	}
+	deps := g.internAll(d.Deps)
+	if wasLoaded {
+		g.recordConflict(id, imports, deps)
+	}
+	g.setImports(id, g.keepTestImports(id, imports))
+	g.setDeps(id, deps)
+	g.updateClosure(id)
+	g.changed()
+}
+
+// Remove drops the record of packageName: its imports, deps and main/test
+// flags. Packages importing it keep their edges to it, as they would if it
+// had never been added. Indexes and cached closures are updated for the
+// affected packages only.
+func (g *DepGraph) Remove(packageName string) {
+	g.mustNotBeFrozen()
+	id, ok := g.lookup(packageName)
+	if !ok || !g.loaded[id] {
+		return
+	}
+	g.loaded[id] = false
+	delete(g.mainPackages, id)
+	delete(g.testPackages, id)
+	delete(g.cgoPackages, id)
+	delete(g.standard, id)
+	delete(g.sizes, id)
+	delete(g.dirs, id)
+	delete(g.pkgNames, id)
+	delete(g.embeds, id)
+	delete(g.conflicts, id)
+	delete(g.modules, id)
+	g.setImports(id, nil)
+	g.setDeps(id, nil)
+	g.updateClosure(id)
+	g.changed()
 }
 
-func (g *DepGraph) CountAll() int {
-	return len(g.imports)
+func (g *DepGraph) CountAll() (n int) {
+	for _, imports := range g.imports {
+		if imports != nil {
+			n++
+		}
+	}
+	return
 }
+
 func (g *DepGraph) CountMain() int {
 	return len(g.mainPackages)
 }
@@ -88,146 +272,472 @@ func reverseSlice(a []string) {
 }
 
 func (g *DepGraph) SearchMain(packageName string) (packages []string) {
+	target, ok := g.lookup(packageName)
+	if !ok {
+		return
+	}
 	for v := range g.mainPackages {
-		if g.allDeps[v][packageName] || v == packageName {
-			packages = append(packages, v)
+		if g.dependsOn(v, target) || v == target {
+			packages = append(packages, g.names[v])
 		}
 	}
 	return
 }
 
+// SearchTest returns the test binaries depending on packageName, like
+// SearchTestStream; TestChains shows how they reach it.
 func (g *DepGraph) SearchTest(packageName string) (packages []string) {
+	target, ok := g.lookup(packageName)
+	if !ok {
+		return
+	}
 	for v := range g.testPackages {
-		if g.allDeps[v][packageName] {
-			packages = append(packages, v)
+		if g.dependsOn(v, target) {
+			packages = append(packages, g.names[v])
 		}
 	}
 	return
 }
 
 func (g *DepGraph) Exists(packageName string) bool {
-	_, exists := g.allDeps[packageName]
-	return exists
+	id, ok := g.lookup(packageName)
+	return ok && g.loaded[id]
 }
 
-func (g *DepGraph) SearchAll(packageName string) (packages []string) {
-	for k, v := range g.allDeps {
-		if v[packageName] {
-			packages = append(packages, k)
+// Packages returns every loaded package, sorted.
+func (g *DepGraph) Packages() (packages []string) {
+	for id, loaded := range g.loaded {
+		if loaded {
+			packages = append(packages, g.names[id])
 		}
 	}
+	sort.Strings(packages)
 	return
 }
 
-func (g *DepGraph) ListUnUsed() (packages []string) {
-	defer func() {
-		sort.Strings(packages)
-	}()
-	for p := range g.allDeps {
-		if g.mainPackages[p] || g.testPackages[p] {
-			continue
-		}
-		found := false
-		for m := range g.allDeps {
-			if g.allDeps[m][p] {
-				found = true
-			}
-		}
-		if !found {
-			packages = append(packages, p)
-		}
+// Deps returns every package packageName depends on, sorted: its Deps
+// field, or its import closure if the graph ignores Deps.
+func (g *DepGraph) Deps(packageName string) (packages []string) {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return
 	}
+	if g.ignoreDeps {
+		g.closure()[id].each(func(dep nodeID) {
+			packages = append(packages, g.names[dep])
+		})
+	} else {
+		packages = g.pathsOf(g.deps[id])
+	}
+	sort.Strings(packages)
+	return
+}
+
+func (g *DepGraph) SearchAll(packageName string) (packages []string) {
+	target, ok := g.lookup(packageName)
+	if !ok {
+		return
+	}
+	return g.pathsOf(g.dependentsOf(target))
+}
+
+func (g *DepGraph) ListUnUsed() (packages []string) {
+	packages = g.pathsOf(g.filterNodes(func(p nodeID) bool {
+		return g.loaded[p] && !g.mainPackages[p] && !g.testPackages[p] && !g.used(p)
+	}))
+	sort.Strings(packages)
 	return
 }
 
 func (g *DepGraph) IsMainPackage(packageName string) bool {
-	return g.mainPackages[packageName]
+	id, ok := g.lookup(packageName)
+	return ok && g.mainPackages[id]
 }
 
 func (g *DepGraph) IsTestPackage(packageName string) bool {
-	return g.testPackages[packageName]
+	id, ok := g.lookup(packageName)
+	return ok && g.testPackages[id]
 }
 
 func (g *DepGraph) SearchChain(packageName string) (chains [][]string) {
+	key := "chain\x00" + packageName
+	if cached, ok := g.cache.get(key); ok {
+		return copyChains(cached.([][]string))
+	}
+	chains = g.searchChain(packageName)
+	g.cache.put(key, copyChains(chains))
+	return
+}
+
+func (g *DepGraph) searchChain(packageName string) (chains [][]string) {
+	deadEnds := make(map[nodeID]bool)
 	for _, p := range g.SearchMain(packageName) {
-		if p == packageName {
-			chains = append(chains, []string{"main", p})
-			continue
-		}
-		chain := []string{}
-		chain, found := g.search(p, packageName, chain)
-		if !found {
-			// dep存在，但是找不到依赖链，说明依赖关系导入不全，比如缺少标准库
-			chain = []string{packageName, "..."}
-		}
-		chain = append(chain, p)
-		chain = append(chain, "main")
-		reverseSlice(chain)
-		chains = append(chains, chain)
+		chains = append(chains, g.chainFrom(p, packageName, deadEnds))
 	}
 	return
 }
 
-func (g *DepGraph) search(start, packageName string, current []string) (after []string, found bool) {
-	if !g.allDeps[start][packageName] {
+// chainFrom builds the chain main -> p -> ... -> packageName. deadEnds is
+// shared by every call for the same packageName, see searchID.
+func (g *DepGraph) chainFrom(p, packageName string, deadEnds map[nodeID]bool) []string {
+	if p == packageName {
+		return []string{"main", p}
+	}
+	chain := []string{}
+	chain, found := g.search(p, packageName, chain, deadEnds)
+	if !found {
+		// dep存在，但是找不到依赖链，说明依赖关系导入不全，比如缺少标准库
+		chain = []string{packageName, "..."}
+	}
+	chain = append(chain, p)
+	chain = append(chain, "main")
+	reverseSlice(chain)
+	return chain
+}
+
+func (g *DepGraph) search(start, packageName string, current []string,
+	deadEnds map[nodeID]bool) (after []string, found bool) {
+	startID, ok := g.lookup(start)
+	if !ok {
 		return
 	}
-	if g.imports[start][packageName] {
-		found = true
-		after = append(current, packageName)
+	target, ok := g.lookup(packageName)
+	if !ok {
 		return
 	}
-	for p := range g.imports[start] {
-		if after, ok := g.search(p, packageName, current); ok {
-			after = append(after, p)
+	return g.searchID(startID, target, current, deadEnds)
+}
+
+// searchID walks imports depth-first from start with an explicit stack,
+// so deep graphs can't exhaust the goroutine stack, and never enters the
+// same package twice. On success after is current followed by the chain
+// from target back to (but excluding) start.
+//
+// Packages fully explored without reaching target are recorded in
+// deadEnds, so searches for the same target from other starts skip them
+// instead of exploring identical subgraphs again.
+func (g *DepGraph) searchID(start, target nodeID, current []string,
+	deadEnds map[nodeID]bool) (after []string, found bool) {
+	if !g.dependsOn(start, target) || deadEnds[start] {
+		return
+	}
+	type frame struct {
+		id      nodeID
+		next    int
+		blocked bool // skipped a package still on the stack
+	}
+	onStack := map[nodeID]bool{start: true}
+	stack := []frame{{id: start}}
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if e := g.edgeTo(top.id, target); top.next == 0 && e != nil && !e.TestOnly {
+			after = append(current, g.names[target])
+			for i := len(stack) - 1; i > 0; i-- {
+				after = append(after, g.names[stack[i].id])
+			}
 			return after, true
 		}
+		if top.next == len(g.imports[top.id]) {
+			// only an import cycle can hide a path from a finished
+			// package; everything else it could reach has been tried
+			if !top.blocked {
+				deadEnds[top.id] = true
+			}
+			blocked := top.blocked
+			delete(onStack, top.id)
+			stack = stack[:len(stack)-1]
+			if blocked && len(stack) > 0 {
+				stack[len(stack)-1].blocked = true
+			}
+			continue
+		}
+		e := g.imports[top.id][top.next]
+		p := e.to
+		top.next++
+		switch {
+		case !e.inBuild():
+		case onStack[p]:
+			top.blocked = true
+		case !deadEnds[p] && g.dependsOn(p, target):
+			onStack[p] = true
+			stack = append(stack, frame{id: p})
+		}
 	}
 	return
 }
 
 func (g *DepGraph) SearchGraph(start, toSearch string) (result map[string][]string) {
-	if !g.allDeps[start][toSearch] {
+	key := "graph\x00" + start + "\x00" + toSearch
+	if cached, ok := g.cache.get(key); ok {
+		return copyGraph(cached.(map[string][]string))
+	}
+	result = g.searchGraph(start, toSearch)
+	g.cache.put(key, copyGraph(result))
+	return
+}
+
+func (g *DepGraph) searchGraph(start, toSearch string) (result map[string][]string) {
+	if !g.dependsOnName(start, toSearch) {
 		return
 	}
+	startID, _ := g.lookup(start)
+	target, _ := g.lookup(toSearch)
 	result = make(map[string][]string)
-	checked := make(map[string]bool)
+	checked := make(map[nodeID]bool)
 	l := list.New()
-	l.PushBack(start)
-	checked[start] = true
+	l.PushBack(startID)
+	checked[startID] = true
 	for e := l.Front(); e != nil; e = e.Next() {
-		fromPackage := e.Value.(string)
-		for p := range g.imports[fromPackage] {
-			if p == toSearch {
-				result[fromPackage] = append(result[fromPackage], p)
+		fromID := e.Value.(nodeID)
+		fromPackage := g.names[fromID]
+		for _, imp := range g.imports[fromID] {
+			if !imp.inBuild() {
 				continue
 			}
-			if g.allDeps[p][toSearch] {
+			p := imp.to
+			if p == target {
+				result[fromPackage] = append(result[fromPackage], g.names[p])
+				continue
+			}
+			if g.dependsOn(p, target) {
 				if !checked[p] {
 					checked[p] = true
 					l.PushBack(p)
 				}
-				result[fromPackage] = append(result[fromPackage], p)
+				result[fromPackage] = append(result[fromPackage], g.names[p])
+			}
+		}
+	}
+	return
+}
+
+// SearchGraphFrom merges the SearchGraph of every start with toSearch into
+// one graph, eg: from all the main packages at once. Edges shared by
+// several starts appear once. reachedFrom annotates every package of the
+// result with the starts whose graph it is on, in the order of starts;
+// starts not importing toSearch are left out of both.
+func (g *DepGraph) SearchGraphFrom(starts []string, toSearch string) (result, reachedFrom map[string][]string) {
+	result = make(map[string][]string)
+	reachedFrom = make(map[string][]string)
+	seen := make(map[[2]string]bool)
+	done := make(map[string]bool)
+	for _, start := range starts {
+		if done[start] {
+			continue
+		}
+		done[start] = true
+		edges := g.SearchGraph(start, toSearch)
+		on := make(map[string]bool)
+		for from, tos := range edges {
+			on[from] = true
+			for _, to := range tos {
+				on[to] = true
+				if e := [2]string{from, to}; !seen[e] {
+					seen[e] = true
+					result[from] = append(result[from], to)
+				}
 			}
 		}
+		for p := range on {
+			reachedFrom[p] = append(reachedFrom[p], start)
+		}
 	}
 	return
 }
 
 func LoadDeps(r io.Reader) (dg *DepGraph, err error) {
-	dec := json.NewDecoder(r)
 	dg = &DepGraph{}
+	err = dg.Load(r)
+	return
+}
+
+// Load adds every package of the go list -json output in r to g. Input
+// going over the limits set by SetLimits fails with a *LimitError.
+func (g *DepGraph) Load(r io.Reader) error {
+	var rr *recordReader
+	if g.limits.MaxRecordSize > 0 {
+		rr = &recordReader{r: r}
+		r = rr
+	}
+	dec := json.NewDecoder(r)
+	packages := g.loadedCount()
 	for {
+		if rr != nil {
+			rr.limit = dec.InputOffset() + g.limits.MaxRecordSize
+		}
 		var di DepInfo
-		err = dec.Decode(&di)
+		err := dec.Decode(&di)
 		if err != nil {
 			if err == io.EOF {
-				err = nil
-				break
+				return nil
 			}
-			return
+			if err == errRecordTooLarge {
+				return &LimitError{Limit: "MaxRecordSize", Max: g.limits.MaxRecordSize}
+			}
+			return err
+		}
+		if err := g.checkLimits(&di, packages); err != nil {
+			return err
+		}
+		if !g.Exists(di.ImportPath) {
+			packages++
 		}
-		dg.Add(di)
+		g.Add(di)
 	}
+}
+
+// ReverseChains walks importers upward from packageName and returns one
+// shortest chain from every root (a package nobody imports, library or
+// main) down to packageName, eg: [root, a, b, packageName].
+func (g *DepGraph) ReverseChains(packageName string) (chains [][]string) {
+	if !g.Exists(packageName) {
+		return
+	}
+	next := map[string]string{packageName: ""}
+	l := list.New()
+	l.PushBack(packageName)
+	for e := l.Front(); e != nil; e = e.Next() {
+		p := e.Value.(string)
+		importers := g.Importers(p)
+		if len(importers) == 0 && p != packageName {
+			chain := []string{}
+			for ; p != ""; p = next[p] {
+				chain = append(chain, p)
+			}
+			chains = append(chains, chain)
+			continue
+		}
+		for _, importer := range importers {
+			if _, ok := next[importer]; !ok {
+				next[importer] = p
+				l.PushBack(importer)
+			}
+		}
+	}
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i][0] < chains[j][0]
+	})
+	return
+}
+
+// ReverseGraph is the reverse of SearchGraph: it walks importers upward
+// from packageName and returns every import between the packages
+// importing it, directly or not, and packageName, as a from -> []to map
+// like SearchGraph. Its roots are the roots of ReverseChains; like there,
+// test-only imports are followed, so the result shows every package to
+// migrate before packageName can go away.
+func (g *DepGraph) ReverseGraph(packageName string) (result map[string][]string) {
+	if !g.Exists(packageName) {
+		return
+	}
+	result = make(map[string][]string)
+	checked := map[string]bool{packageName: true}
+	l := list.New()
+	l.PushBack(packageName)
+	for e := l.Front(); e != nil; e = e.Next() {
+		p := e.Value.(string)
+		for _, importer := range g.Importers(p) {
+			result[importer] = append(result[importer], p)
+			if !checked[importer] {
+				checked[importer] = true
+				l.PushBack(importer)
+			}
+		}
+	}
+	for _, imports := range result {
+		sort.Strings(imports)
+	}
+	return
+}
+
+// GraphRoots returns the packages of result, a SearchGraph like graph,
+// nothing in result imports, sorted.
+func GraphRoots(result map[string][]string) (roots []string) {
+	imported := make(map[string]bool)
+	for _, tos := range result {
+		for _, to := range tos {
+			imported[to] = true
+		}
+	}
+	for from := range result {
+		if !imported[from] {
+			roots = append(roots, from)
+		}
+	}
+	sort.Strings(roots)
+	return
+}
+
+// isStdlib guesses whether importPath belongs to the standard library:
+// like the go command, it treats paths without a dot in the first element
+// as standard.
+func isStdlib(importPath string) bool {
+	first := importPath
+	if i := strings.Index(importPath, "/"); i >= 0 {
+		first = importPath[:i]
+	}
+	return !strings.Contains(first, ".")
+}
+
+// IsStandard reports whether packageName is part of the standard library,
+// as the Standard field of go list says. For dumps without the field and
+// packages missing from the dump it falls back to guessing from the path.
+func (g *DepGraph) IsStandard(packageName string) bool {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return isStdlib(packageName)
+	}
+	return g.isStandard(id)
+}
+
+func (g *DepGraph) isStandard(id nodeID) bool {
+	if g.sawStandard && g.loaded[id] {
+		return g.standard[id]
+	}
+	return isStdlib(g.names[id])
+}
+
+// CountStandard returns the number of loaded standard library packages.
+func (g *DepGraph) CountStandard() (n int) {
+	for id, loaded := range g.loaded {
+		if loaded && g.isStandard(nodeID(id)) {
+			n++
+		}
+	}
+	return
+}
+
+// Roots returns the packages no other package imports.
+func (g *DepGraph) Roots() (packages []string) {
+	packages = g.pathsOf(g.filterNodes(func(id nodeID) bool {
+		if !g.loaded[id] {
+			return false
+		}
+		for _, importer := range g.importers[id] {
+			if g.names[importer] != "main" && g.buildImports(importer, id) {
+				return false
+			}
+		}
+		return true
+	}))
+	sort.Strings(packages)
+	return
+}
+
+// Leaves returns the packages importing nothing outside the standard
+// library.
+func (g *DepGraph) Leaves() (packages []string) {
+	packages = g.pathsOf(g.filterNodes(func(id nodeID) bool {
+		if !g.loaded[id] {
+			return false
+		}
+		for _, e := range g.imports[id] {
+			if e.inBuild() && !g.isStandard(e.to) {
+				return false
+			}
+		}
+		return true
+	}))
+	sort.Strings(packages)
 	return
 }
@@ -1,7 +1,9 @@
 package depgraph
 
 import (
+	"fmt"
 	"os"
+	"reflect"
 	"testing"
 )
 
@@ -106,3 +108,240 @@ func sliceContains(s []string, t string) bool {
 	}
 	return false
 }
+
+func loadTestGraph(t *testing.T) *DepGraph {
+	f, err := os.Open("testdata/go1.12.5_deps.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dg, err := LoadDeps(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dg
+}
+
+func TestStream(t *testing.T) {
+	dg := loadTestGraph(t)
+	var streamed []string
+	for p := range dg.SearchAllStream("net/url", nil) {
+		streamed = append(streamed, p)
+	}
+	if len(streamed) != len(dg.SearchAll("net/url")) {
+		t.Error(len(streamed), len(dg.SearchAll("net/url")))
+	}
+	n := 0
+	for chain := range dg.SearchChainStream("fmt", nil) {
+		if chain[0] != "main" || chain[len(chain)-1] != "fmt" {
+			t.Error("result error", chain)
+		}
+		n++
+	}
+	if n != len(dg.SearchChain("fmt")) {
+		t.Error(n, len(dg.SearchChain("fmt")))
+	}
+	done := make(chan struct{})
+	ch := dg.SearchAllStream("fmt", done)
+	<-ch
+	close(done)
+	for range ch {
+	}
+}
+
+func TestReverseChains(t *testing.T) {
+	dg := loadTestGraph(t)
+	dg.Add(DepInfo{
+		ImportPath: "x",
+		Name:       "x",
+		Deps:       []string{"fmt"},
+		Imports:    []string{"fmt"},
+	})
+	chains := dg.ReverseChains("fmt")
+	roots := make(map[string]bool)
+	for _, chain := range chains {
+		if chain[len(chain)-1] != "fmt" {
+			t.Error("result error", chain)
+		}
+		roots[chain[0]] = true
+	}
+	if !roots["x"] || !roots["cmd/vet"] {
+		t.Error("x and cmd/vet should be roots", roots)
+	}
+	if dg.ReverseChains("fmtxxxxxxx") != nil {
+		t.Error("should be empty")
+	}
+}
+
+func TestRootsAndLeaves(t *testing.T) {
+	dg := loadTestGraph(t)
+	dg.Add(DepInfo{
+		ImportPath: "example.com/a",
+		Name:       "a",
+		Deps:       []string{"example.com/b", "fmt"},
+		Imports:    []string{"example.com/b"},
+	})
+	dg.Add(DepInfo{
+		ImportPath: "example.com/b",
+		Name:       "b",
+		Deps:       []string{"fmt"},
+		Imports:    []string{"fmt"},
+	})
+	roots := dg.Roots()
+	if !sliceContains(roots, "example.com/a") || !sliceContains(roots, "cmd/vet") {
+		t.Error("roots error", roots)
+	}
+	if sliceContains(roots, "example.com/b") || sliceContains(roots, "fmt") {
+		t.Error("roots error", roots)
+	}
+	leaves := dg.Leaves()
+	if sliceContains(leaves, "example.com/a") || !sliceContains(leaves, "example.com/b") {
+		t.Error("leaves error", leaves)
+	}
+}
+
+func TestSearchChainDeep(t *testing.T) {
+	dg := &DepGraph{}
+	const depth = 2000
+	names := make([]string, depth)
+	for i := range names {
+		names[i] = fmt.Sprintf("p%d", i)
+	}
+	for i := depth - 1; i >= 0; i-- {
+		d := DepInfo{ImportPath: names[i], Name: "p", Deps: names[i+1:]}
+		if i+1 < depth {
+			d.Imports = []string{names[i+1]}
+		}
+		if i == 0 {
+			d.Name = "main"
+		}
+		dg.Add(d)
+	}
+	chains := dg.SearchChain(names[depth-1])
+	if len(chains) != 1 || len(chains[0]) != depth+1 {
+		t.Fatal("result error", len(chains))
+	}
+	if chains[0][1] != "p0" || chains[0][depth] != names[depth-1] {
+		t.Error("result error", chains[0][:2], chains[0][depth])
+	}
+}
+
+func TestSearchChainDeadEnds(t *testing.T) {
+	dg := &DepGraph{}
+	// Deps claim every package reaches z, but the import of z is missing
+	dg.Add(DepInfo{ImportPath: "shared", Name: "shared", Deps: []string{"z"}})
+	dg.Add(DepInfo{ImportPath: "z", Name: "z"})
+	for _, m := range []string{"cmd/a", "cmd/b"} {
+		dg.Add(DepInfo{ImportPath: m, Name: "main", Deps: []string{"shared", "z"}, Imports: []string{"shared"}})
+	}
+	deadEnds := make(map[nodeID]bool)
+	for _, m := range []string{"cmd/a", "cmd/b"} {
+		chain := dg.chainFrom(m, "z", deadEnds)
+		if chain[len(chain)-2] != "..." {
+			t.Error("result error", chain)
+		}
+	}
+	if id, _ := dg.lookup("shared"); !deadEnds[id] {
+		t.Error("shared should be a dead end")
+	}
+}
+
+func TestIsStandard(t *testing.T) {
+	dg := loadTestGraph(t)
+	if n := dg.CountStandard(); n != 370 {
+		t.Error("expect 370 standard packages, real:", n)
+	}
+	// a GOPATH package without a dot looks standard, the field knows better
+	dg.Add(DepInfo{ImportPath: "mycorp/lib", Name: "lib", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	if dg.IsStandard("mycorp/lib") || !dg.IsStandard("net/http") || !dg.IsStandard("missing/pkg") {
+		t.Error("IsStandard error")
+	}
+	if leaves := dg.Freeze().Leaves(); !sliceContains(leaves, "mycorp/lib") {
+		t.Error("leaves error", leaves)
+	}
+	old := &DepGraph{}
+	old.Add(DepInfo{ImportPath: "mycorp/lib", Name: "lib"})
+	old.Add(DepInfo{ImportPath: "example.com/x", Name: "x"})
+	if !old.IsStandard("mycorp/lib") || old.IsStandard("example.com/x") {
+		t.Error("dumps without the Standard field should use the heuristic")
+	}
+}
+
+func TestReAddMainFlags(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "cmd/a.test", Name: "main", Imports: []string{"cmd/a"}, Deps: []string{"cmd/a", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Name: "fmt"})
+	if dg.CountMain() != 1 || dg.CountTest() != 1 {
+		t.Fatal(dg.CountMain(), dg.CountTest())
+	}
+	// cmd/a became a library, the test binary is gone
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "a", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Remove("cmd/a.test")
+	if dg.CountMain() != 0 || dg.CountTest() != 0 || dg.IsMainPackage("cmd/a") || len(dg.SearchMain("fmt")) != 0 {
+		t.Error(dg.CountMain(), dg.CountTest(), dg.SearchMain("fmt"))
+	}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	if !dg.IsMainPackage("cmd/a") || dg.CountMain() != 1 {
+		t.Error("main package not restored")
+	}
+}
+
+func TestSearchGraphFrom(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"lib", "x"}, Deps: []string{"lib", "x", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "cmd/b", Name: "main", Imports: []string{"lib"}, Deps: []string{"lib", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "cmd/c", Name: "main", Imports: []string{"os"}, Deps: []string{"os"}})
+	dg.Add(DepInfo{ImportPath: "lib", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "x", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "fmt"})
+	dg.Add(DepInfo{ImportPath: "os"})
+	result, reachedFrom := dg.SearchGraphFrom([]string{"cmd/a", "cmd/b", "cmd/c", "cmd/a"}, "fmt")
+	expect := map[string][]string{
+		"cmd/a": {"lib", "x"},
+		"cmd/b": {"lib"},
+		"lib":   {"fmt"},
+		"x":     {"fmt"},
+	}
+	if !reflect.DeepEqual(result, expect) {
+		t.Error(result)
+	}
+	expect = map[string][]string{
+		"cmd/a": {"cmd/a"},
+		"cmd/b": {"cmd/b"},
+		"lib":   {"cmd/a", "cmd/b"},
+		"x":     {"cmd/a"},
+		"fmt":   {"cmd/a", "cmd/b"},
+	}
+	if !reflect.DeepEqual(reachedFrom, expect) {
+		t.Error(reachedFrom)
+	}
+	if got := PruneGraphFrom(result, []string{"cmd/a", "cmd/b", "cmd/c"}, "fmt", PruneOptions{MaxDepth: 1}); !reflect.DeepEqual(got, result) {
+		t.Error("every package is one import away from a start", got)
+	}
+}
+
+func TestReverseGraph(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"lib", "x"}, Deps: []string{"lib", "x", "old"}})
+	dg.Add(DepInfo{ImportPath: "tool", Imports: []string{"old"}, Deps: []string{"old"}})
+	dg.Add(DepInfo{ImportPath: "lib", Imports: []string{"old"}, Deps: []string{"old"}})
+	dg.Add(DepInfo{ImportPath: "x", Imports: []string{"lib"}, Deps: []string{"lib", "old"}})
+	dg.Add(DepInfo{ImportPath: "old"})
+	expect := map[string][]string{
+		"cmd/a": {"lib", "x"},
+		"x":     {"lib"},
+		"lib":   {"old"},
+		"tool":  {"old"},
+	}
+	result := dg.ReverseGraph("old")
+	if !reflect.DeepEqual(result, expect) {
+		t.Error(result)
+	}
+	if roots := GraphRoots(result); !reflect.DeepEqual(roots, []string{"cmd/a", "tool"}) {
+		t.Error(roots)
+	}
+	if dg.ReverseGraph("nope") != nil {
+		t.Error("missing package has importers")
+	}
+}
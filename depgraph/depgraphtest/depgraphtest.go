@@ -0,0 +1,234 @@
+// Package depgraphtest generates random but realistic dependency graphs to
+// benchmark and fuzz code using depgraph.
+//
+// A generated graph has the shape of go list -json -deps output: main
+// packages of a main module import packages of the main module and of
+// third-party modules, laid out in layers so the graph has no cycles, down
+// to standard library packages which only import each other.
+package depgraphtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// MainModule is the module path of the main packages.
+const MainModule = "example.com/main"
+
+// Config is the shape of a generated graph. Zero fields take the value of
+// Default.
+type Config struct {
+	Packages int     // non-standard packages, main packages included
+	Mains    int     // main packages
+	Std      int     // standard library packages
+	Modules  int     // third-party modules
+	FanOut   int     // max imports of a package
+	Depth    int     // layers of non-standard packages below the main packages
+	Local    float64 // share of the non-main packages in the main module
+	Seed     int64   // the same Config always gives the same graph
+}
+
+// Default is the shape of a mid-sized service repository.
+var Default = Config{
+	Packages: 1000,
+	Mains:    5,
+	Std:      150,
+	Modules:  40,
+	FanOut:   6,
+	Depth:    8,
+	Local:    0.2,
+	Seed:     1,
+}
+
+func (c Config) withDefaults() Config {
+	if c.Packages <= 0 {
+		c.Packages = Default.Packages
+	}
+	if c.Mains <= 0 {
+		c.Mains = Default.Mains
+	}
+	if c.Mains > c.Packages {
+		c.Mains = c.Packages
+	}
+	if c.Std <= 0 {
+		c.Std = Default.Std
+	}
+	if c.Modules <= 0 {
+		c.Modules = Default.Modules
+	}
+	if c.FanOut <= 0 {
+		c.FanOut = Default.FanOut
+	}
+	if c.Depth <= 0 {
+		c.Depth = Default.Depth
+	}
+	if n := c.Packages - c.Mains; c.Depth > n {
+		c.Depth = n
+	}
+	if c.Local <= 0 || c.Local > 1 {
+		c.Local = Default.Local
+	}
+	if c.Seed == 0 {
+		c.Seed = Default.Seed
+	}
+	return c
+}
+
+// Generate returns the packages of a graph of shape c, deps first like
+// go list -deps lists them.
+func Generate(c Config) []depgraph.DepInfo {
+	c = c.withDefaults()
+	rnd := rand.New(rand.NewSource(c.Seed))
+	// layers[0] are the main packages, layers[c.Depth+1] the standard
+	// library, a package only imports packages of deeper layers.
+	layers := make([][]depgraph.DepInfo, c.Depth+2)
+	for i := 0; i < c.Mains; i++ {
+		layers[0] = append(layers[0], depgraph.DepInfo{
+			ImportPath: fmt.Sprintf("%s/cmd/c%d", MainModule, i),
+			Name:       "main",
+			Module:     &depgraph.Module{Path: MainModule, Main: true},
+		})
+	}
+	modules := make([]*depgraph.Module, c.Modules)
+	for i := range modules {
+		modules[i] = &depgraph.Module{
+			Path:    fmt.Sprintf("example.org/m%d", i),
+			Version: fmt.Sprintf("v1.%d.%d", rnd.Intn(20), rnd.Intn(10)),
+		}
+	}
+	for i := 0; i < c.Packages-c.Mains; i++ {
+		// every layer gets a package before the rest are spread at random
+		layer := 1 + i
+		if i >= c.Depth {
+			layer = 1 + rnd.Intn(c.Depth)
+		}
+		var path string
+		var m *depgraph.Module
+		// local packages are rather near the mains, third-party ones deeper
+		if rnd.Float64() < c.Local*float64(2*(c.Depth-layer+1))/float64(c.Depth+1) {
+			m = &depgraph.Module{Path: MainModule, Main: true}
+			path = fmt.Sprintf("%s/internal/p%d", MainModule, i)
+		} else {
+			m = modules[rnd.Intn(len(modules))]
+			path = fmt.Sprintf("%s/p%d", m.Path, i)
+		}
+		layers[layer] = append(layers[layer], depgraph.DepInfo{
+			ImportPath: path,
+			Name:       fmt.Sprintf("p%d", i),
+			Module:     m,
+		})
+	}
+	for _, layer := range layers[1:] {
+		sort.SliceStable(layer, func(i, j int) bool { return layer[i].Module.Path < layer[j].Module.Path })
+	}
+	for i := 0; i < c.Std; i++ {
+		layers[c.Depth+1] = append(layers[c.Depth+1], depgraph.DepInfo{
+			ImportPath: fmt.Sprintf("std%d/p", i),
+			Name:       "p",
+			Standard:   true,
+		})
+	}
+
+	deps := make(map[string][]string)
+	var pkgs []depgraph.DepInfo
+	for l := len(layers) - 1; l >= 0; l-- {
+		for i := range layers[l] {
+			d := &layers[l][i]
+			d.Imports = pickImports(rnd, layers, l, i, c.FanOut, c.Modules+1)
+			closure := make(map[string]bool)
+			for _, p := range d.Imports {
+				closure[p] = true
+				for _, dep := range deps[p] {
+					closure[dep] = true
+				}
+			}
+			for p := range closure {
+				d.Deps = append(d.Deps, p)
+			}
+			sort.Strings(d.Deps)
+			deps[d.ImportPath] = d.Deps
+			pkgs = append(pkgs, *d)
+		}
+	}
+	return pkgs
+}
+
+// pickImports picks up to fanOut imports of the package i of layer l in
+// the next layers, near its position in its layer: the layers are sorted
+// by module, so packages mostly import packages of their own module and
+// deps don't grow to the whole graph. Standard packages only import
+// standard packages listed before them.
+func pickImports(rnd *rand.Rand, layers [][]depgraph.DepInfo, l, i, fanOut, modules int) []string {
+	std := len(layers) - 1
+	var candidates []depgraph.DepInfo
+	if l == std {
+		candidates = layers[std][:i]
+	} else {
+		pos := float64(i) / float64(len(layers[l]))
+		for d := l + 1; d < std && d <= l+3; d++ {
+			width := fanOut
+			if w := len(layers[d]) / modules; w > width {
+				width = w
+			}
+			mid := int(pos * float64(len(layers[d])))
+			lo, hi := mid-width, mid+width
+			if lo < 0 {
+				lo = 0
+			}
+			if hi > len(layers[d]) {
+				hi = len(layers[d])
+			}
+			candidates = append(candidates, layers[d][lo:hi]...)
+		}
+	}
+	seen := make(map[string]bool)
+	var imports []string
+	if len(candidates) > 0 {
+		n := 1 + rnd.Intn(fanOut)
+		if l == std {
+			n = rnd.Intn(fanOut/2 + 1)
+		}
+		for j := 0; j < n; j++ {
+			p := candidates[rnd.Intn(len(candidates))].ImportPath
+			if !seen[p] {
+				seen[p] = true
+				imports = append(imports, p)
+			}
+		}
+	}
+	// most packages use the standard library
+	if l < std && len(layers[std]) > 0 && rnd.Intn(4) > 0 {
+		if p := layers[std][rnd.Intn(len(layers[std]))].ImportPath; !seen[p] {
+			imports = append(imports, p)
+		}
+	}
+	sort.Strings(imports)
+	return imports
+}
+
+// Graph returns a graph of shape c.
+func Graph(c Config) *depgraph.DepGraph {
+	dg := &depgraph.DepGraph{}
+	for _, d := range Generate(c) {
+		dg.Add(d)
+	}
+	return dg
+}
+
+// WriteJSON writes a graph of shape c as go list -json -deps would, to
+// test code reading its output.
+func WriteJSON(w io.Writer, c Config) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	for _, d := range Generate(c) {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
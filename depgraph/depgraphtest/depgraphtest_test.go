@@ -0,0 +1,85 @@
+package depgraphtest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func TestGenerate(t *testing.T) {
+	c := Config{Packages: 300, Mains: 3, Std: 40, Modules: 10, Depth: 5, Seed: 7}
+	pkgs := Generate(c)
+	if len(pkgs) != 340 {
+		t.Fatal(len(pkgs))
+	}
+	if !reflect.DeepEqual(pkgs, Generate(c)) {
+		t.Error("same config, different graphs")
+	}
+	if reflect.DeepEqual(pkgs, Generate(Config{Packages: 300, Mains: 3, Std: 40, Modules: 10, Depth: 5, Seed: 8})) {
+		t.Error("different seeds, same graph")
+	}
+	// deps first, and Deps is the closure of Imports
+	listed := make(map[string]depgraph.DepInfo)
+	for _, d := range pkgs {
+		want := make(map[string]bool)
+		for _, p := range d.Imports {
+			dep, ok := listed[p]
+			if !ok {
+				t.Fatalf("%v imports %v listed after it", d.ImportPath, p)
+			}
+			if d.Standard && !dep.Standard {
+				t.Errorf("standard %v imports %v", d.ImportPath, p)
+			}
+			want[p] = true
+			for _, p := range dep.Deps {
+				want[p] = true
+			}
+		}
+		if len(want) != len(d.Deps) {
+			t.Errorf("%v: %d deps, want %d", d.ImportPath, len(d.Deps), len(want))
+		}
+		listed[d.ImportPath] = d
+	}
+
+	dg := Graph(c)
+	if dg.CountMain() != 3 || dg.CountStandard() != 40 || len(dg.Packages()) != 340 {
+		t.Error(dg.CountMain(), dg.CountStandard(), len(dg.Packages()))
+	}
+	if n := len(dg.Modules()); n < 2 || n > 11 {
+		t.Error(n, "modules")
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, Config{Packages: 200}); err != nil {
+		t.Fatal(err)
+	}
+	dg, err := depgraph.LoadDeps(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Graph(Config{Packages: 200})
+	if !reflect.DeepEqual(dg.Packages(), want.Packages()) {
+		t.Error(len(dg.Packages()), len(want.Packages()))
+	}
+	m := MainModule + "/cmd/c0"
+	if !reflect.DeepEqual(dg.Deps(m), want.Deps(m)) || len(dg.Deps(m)) == 0 {
+		t.Error(dg.Deps(m))
+	}
+}
+
+func BenchmarkSearchChain(b *testing.B) {
+	for _, n := range []int{1000, 10000} {
+		dg := Graph(Config{Packages: n})
+		pkgs := dg.Packages()
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				dg.SearchChain(pkgs[i%len(pkgs)])
+			}
+		})
+	}
+}
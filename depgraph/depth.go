@@ -0,0 +1,29 @@
+package depgraph
+
+// DepthHistogram returns the distribution of the depths of the deps of
+// pkg: h[d] is the number of packages whose shortest import chain from pkg
+// has d imports, h[0] being pkg itself, so len(h)-1 is the longest chain
+// pkg needs to reach a dep. Tracked over releases, it tells whether the
+// graph gets deeper, a measure of coupling. Test-only imports are ignored.
+func (g *DepGraph) DepthHistogram(pkg string) (h []int) {
+	start, ok := g.lookup(pkg)
+	if !ok || !g.loaded[start] {
+		return nil
+	}
+	seen := map[nodeID]bool{start: true}
+	level := []nodeID{start}
+	for len(level) > 0 {
+		h = append(h, len(level))
+		var next []nodeID
+		for _, id := range level {
+			for _, e := range g.imports[id] {
+				if e.inBuild() && !seen[e.to] {
+					seen[e.to] = true
+					next = append(next, e.to)
+				}
+			}
+		}
+		level = next
+	}
+	return
+}
@@ -0,0 +1,35 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDepthHistogram(t *testing.T) {
+	dg := &DepGraph{}
+	dg.AddEdge("main", "a")
+	dg.AddEdge("main", "b")
+	dg.AddEdge("a", "c")
+	dg.AddEdge("b", "c")
+	dg.AddEdge("c", "d")
+	dg.AddEdge("main", "d")
+	if h := dg.DepthHistogram("main"); !reflect.DeepEqual(h, []int{1, 3, 1}) {
+		t.Error(h)
+	}
+	if h := dg.DepthHistogram("d"); !reflect.DeepEqual(h, []int{1}) {
+		t.Error(h)
+	}
+	if h := dg.DepthHistogram("x"); h != nil {
+		t.Error(h)
+	}
+
+	std := loadTestGraph(t)
+	h := std.DepthHistogram("cmd/vet")
+	total := 0
+	for _, n := range h {
+		total += n
+	}
+	if total != len(std.Descendants("cmd/vet"))+1 {
+		t.Error("every dep should be counted once", total)
+	}
+}
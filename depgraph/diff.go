@@ -0,0 +1,17 @@
+package depgraph
+
+// DiffPackages returns the sorted packages loaded in new but not in old,
+// and those loaded in old but not in new.
+func DiffPackages(old, new *DepGraph) (added, removed []string) {
+	for _, p := range new.Packages() {
+		if !old.Exists(p) {
+			added = append(added, p)
+		}
+	}
+	for _, p := range old.Packages() {
+		if !new.Exists(p) {
+			removed = append(removed, p)
+		}
+	}
+	return
+}
@@ -0,0 +1,19 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffPackages(t *testing.T) {
+	old, new := &DepGraph{}, &DepGraph{}
+	old.AddEdge("cmd/a", "log")
+	old.AddEdge("cmd/a", "legacy")
+	new.AddEdge("cmd/a", "log")
+	new.AddEdge("log", "zap")
+	new.AddEdge("cmd/a", "jwt")
+	added, removed := DiffPackages(old, new)
+	if !reflect.DeepEqual(added, []string{"jwt", "zap"}) || !reflect.DeepEqual(removed, []string{"legacy"}) {
+		t.Error(added, removed)
+	}
+}
@@ -0,0 +1,189 @@
+package depgraph
+
+import "strings"
+
+// EdgeAttrs annotates a single import edge. The zero value describes a
+// plain import.
+type EdgeAttrs struct {
+	TestOnly   bool     // only imported by the package's tests
+	Vendored   bool     // resolved to a vendored copy of the import
+	VendorPath string   // vendored path the import resolved to, see NormalizeVendor
+	Pos        []string // "file:line" of the import declarations, if known, see RecordImportPos
+}
+
+func isVendored(importPath string) bool {
+	return strings.HasPrefix(importPath, "vendor/") || strings.Contains(importPath, "/vendor/")
+}
+
+func (d *DepInfo) edges() map[string]*EdgeAttrs {
+	vendored := make(map[string]bool, len(d.ImportMap))
+	for src, dst := range d.ImportMap {
+		if src != dst {
+			vendored[dst] = true
+		}
+	}
+	m := make(map[string]*EdgeAttrs, len(d.Imports))
+	for _, v := range d.Imports {
+		m[v] = &EdgeAttrs{Vendored: vendored[v] || isVendored(v)}
+	}
+	return m
+}
+
+// inBuild reports whether the import is part of the package's build, as
+// opposed to an import only its tests add.
+func (e edge) inBuild() bool {
+	return !e.attrs.TestOnly
+}
+
+// buildImports reports whether from imports to in its build, not only in
+// its tests.
+func (g *DepGraph) buildImports(from, to nodeID) bool {
+	attrs := g.edgeTo(from, to)
+	return attrs != nil && !attrs.TestOnly
+}
+
+// testVariantBase returns the package a test variant from go list -test
+// belongs to, eg: "p" for "p [p.test]" and "p_test [p.test]", and false
+// for other import paths.
+func testVariantBase(importPath string) (string, bool) {
+	i := strings.Index(importPath, " [")
+	if i < 0 || !strings.HasSuffix(importPath, "]") {
+		return importPath, false
+	}
+	return strings.TrimSuffix(importPath[:i], "_test"), true
+}
+
+// foldTestVariants maps the test variants d refers to, as the test mains
+// of go list -test do, eg: "p [p.test]", to their base package, so the
+// packages d imports and depends on are the ones the graph has records
+// for.
+func (d *DepInfo) foldTestVariants() {
+	d.Imports = foldVariants(d.Imports)
+	d.Deps = foldVariants(d.Deps)
+	if len(d.ImportMap) == 0 {
+		return
+	}
+	// imports of test variants are no vendoring, their entries are dropped
+	m := make(map[string]string, len(d.ImportMap))
+	for src, dst := range d.ImportMap {
+		if _, ok := testVariantBase(dst); !ok {
+			m[src] = dst
+		}
+	}
+	d.ImportMap = m
+}
+
+func foldVariants(paths []string) []string {
+	seen := make(map[string]bool, len(paths))
+	folded := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if base, _ := testVariantBase(p); !seen[base] {
+			seen[base] = true
+			folded = append(folded, base)
+		}
+	}
+	return folded
+}
+
+// addTestImports folds the imports of the test variant d into its base
+// package as test-only edges. Imports the base already has are left
+// alone, so its build edges keep their attributes.
+func (g *DepGraph) addTestImports(d DepInfo) {
+	base, _ := testVariantBase(d.ImportPath)
+	id := g.intern(base)
+	imports := g.imports[id][:len(g.imports[id]):len(g.imports[id])]
+	seen := map[nodeID]bool{id: true}
+	for _, e := range imports {
+		seen[e.to] = true
+	}
+	attrs := d.edges()
+	for _, p := range d.Imports {
+		if p == "C" {
+			continue
+		}
+		to := g.intern(p)
+		if seen[to] {
+			continue
+		}
+		seen[to] = true
+		e := edge{to: to, attrs: attrs[p]}
+		e.attrs.TestOnly = true
+		imports = append(imports, e)
+	}
+	g.setImports(id, imports)
+	g.updateClosure(id)
+}
+
+// keepTestImports appends to imports the test-only edges of id whose
+// target imports doesn't already have, so adding a package after its test
+// variant keeps the edges folded from it.
+func (g *DepGraph) keepTestImports(id nodeID, imports []edge) []edge {
+	for _, old := range g.imports[id] {
+		if old.inBuild() {
+			continue
+		}
+		found := false
+		for _, e := range imports {
+			if e.to == old.to {
+				found = true
+				break
+			}
+		}
+		if !found {
+			imports = append(imports, old)
+		}
+	}
+	return imports
+}
+
+// Edge returns the attributes of the import edge from -> to, or nil if
+// from does not import to.
+func (g *DepGraph) Edge(from, to string) *EdgeAttrs {
+	fromID, ok := g.lookup(from)
+	if !ok {
+		return nil
+	}
+	toID, ok := g.lookup(to)
+	if !ok {
+		return nil
+	}
+	return g.edgeTo(fromID, toID)
+}
+
+// SetEdge replaces the attributes of the import edge from -> to. It
+// reports false if the edge does not exist.
+func (g *DepGraph) SetEdge(from, to string, attrs EdgeAttrs) bool {
+	g.mustNotBeFrozen()
+	fromID, ok := g.lookup(from)
+	if !ok {
+		return false
+	}
+	toID, ok := g.lookup(to)
+	if !ok {
+		return false
+	}
+	e := g.edgeTo(fromID, toID)
+	if e == nil {
+		return false
+	}
+	*e = attrs
+	// TestOnly takes the edge in or out of the build
+	g.updateClosure(fromID)
+	g.changed()
+	return true
+}
+
+// EdgesWhere returns the edges whose attributes satisfy match, as a
+// from -> []to map like SearchGraph.
+func (g *DepGraph) EdgesWhere(match func(from, to string, attrs EdgeAttrs) bool) map[string][]string {
+	result := make(map[string][]string)
+	for id, imports := range g.imports {
+		from := g.names[id]
+		for _, e := range imports {
+			if to := g.names[e.to]; match(from, to, *e.attrs) {
+				result[from] = append(result[from], to)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,221 @@
+package depgraph
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestEdgeAttrs(t *testing.T) {
+	dg := loadTestGraph(t)
+	if e := dg.Edge("cmd/pprof", "cmd/vendor/github.com/google/pprof/driver"); e == nil || !e.Vendored {
+		t.Error("edge should be vendored", e)
+	}
+	if e := dg.Edge("net/http", "net/url"); e == nil || e.Vendored || e.TestOnly {
+		t.Error("edge should be plain", e)
+	}
+	if dg.Edge("fmt", "net/http") != nil {
+		t.Error("edge should not exist")
+	}
+	if !dg.SetEdge("net/http", "net/url", EdgeAttrs{Pos: []string{"server.go:12"}}) {
+		t.Error("SetEdge failed")
+	}
+	if dg.SetEdge("fmt", "net/http", EdgeAttrs{}) {
+		t.Error("SetEdge should fail on missing edge")
+	}
+	positioned := dg.EdgesWhere(func(from, to string, attrs EdgeAttrs) bool {
+		return len(attrs.Pos) > 0
+	})
+	if len(positioned) != 1 || len(positioned["net/http"]) != 1 || positioned["net/http"][0] != "net/url" {
+		t.Error("result error", positioned)
+	}
+}
+
+func TestSetEdgeTestOnly(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/app", Name: "main", Imports: []string{"example.com/lib", "example.com/alt"},
+		Deps: []string{"example.com/alt", "example.com/lib", "example.com/util"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib", Imports: []string{"example.com/util"}, Deps: []string{"example.com/util"}})
+	dg.Add(DepInfo{ImportPath: "example.com/alt", Imports: []string{"example.com/util"}, Deps: []string{"example.com/util"}})
+	dg.Add(DepInfo{ImportPath: "example.com/util"})
+
+	chains := dg.SearchChain("example.com/util")
+	if !dg.PathExists("example.com/lib", "example.com/util") || len(chains) != 1 || chains[0][2] != "example.com/lib" {
+		t.Fatal("result error", chains)
+	}
+	dg.SetEdge("example.com/lib", "example.com/util", EdgeAttrs{TestOnly: true})
+	chains = dg.SearchChain("example.com/util")
+	if dg.PathExists("example.com/lib", "example.com/util") || len(chains) != 1 || chains[0][2] != "example.com/alt" {
+		t.Error("test-only edge should leave the build", chains)
+	}
+	dg.SetEdge("example.com/lib", "example.com/util", EdgeAttrs{})
+	if !dg.PathExists("example.com/lib", "example.com/util") {
+		t.Error("edge should be back in the build")
+	}
+}
+
+func TestSetEdgeReplaced(t *testing.T) {
+	for _, ignoreDeps := range []bool{false, true} {
+		dg := &DepGraph{}
+		dg.IgnoreDeps(ignoreDeps)
+		dg.AddReplace("example.com/lib", "github.com/fork/lib")
+		dg.Add(DepInfo{ImportPath: "example.com/app", Name: "main", Imports: []string{"example.com/lib"},
+			Deps: []string{"example.com/lib", "example.com/util"}})
+		dg.Add(DepInfo{ImportPath: "example.com/lib", Imports: []string{"example.com/util"}, Deps: []string{"example.com/util"}})
+		dg.Add(DepInfo{ImportPath: "example.com/util"})
+		// build the closure so SetEdge has to update it
+		if !dg.PathExists("example.com/lib", "example.com/util") {
+			t.Fatal("result error")
+		}
+		if !dg.SetEdge("github.com/fork/lib", "example.com/util", EdgeAttrs{TestOnly: true}) {
+			t.Fatal("edge through the replacement should be found")
+		}
+		if e := dg.Edge("example.com/lib", "example.com/util"); e == nil || !e.TestOnly {
+			t.Error("attrs should be set on the replaced package", e)
+		}
+		if dg.PathExists("example.com/lib", "example.com/util") {
+			t.Error("test-only edge should leave the build")
+		}
+		// without go list's Deps the closure is all that links app to util
+		if chains := dg.SearchChain("example.com/util"); ignoreDeps && len(chains) != 0 {
+			t.Error("closure should be updated", chains)
+		}
+		if dg.SetEdge("github.com/fork/missing", "example.com/util", EdgeAttrs{}) {
+			t.Error("unknown package should not match")
+		}
+	}
+}
+
+func TestTestVariants(t *testing.T) {
+	for _, ignoreDeps := range []bool{false, true} {
+		dg := &DepGraph{}
+		dg.IgnoreDeps(ignoreDeps)
+		// the variants come first, the base package must keep their edges
+		dg.Add(DepInfo{ImportPath: "lib [lib.test]", Name: "lib",
+			Imports: []string{"fmt", "testhelper"}, Deps: []string{"fmt", "testhelper"}})
+		dg.Add(DepInfo{ImportPath: "lib_test [lib.test]", Name: "lib_test",
+			Imports: []string{"lib [lib.test]", "cmd/tool"}, Deps: []string{"cmd/tool", "fmt", "lib [lib.test]", "testhelper"}})
+		dg.Add(DepInfo{ImportPath: "cmd/tool", Name: "main", Imports: []string{"lib"}, Deps: []string{"fmt", "lib"}})
+		dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+		dg.Add(DepInfo{ImportPath: "testhelper", Name: "testhelper"})
+		dg.Add(DepInfo{ImportPath: "fmt", Name: "fmt"})
+
+		if dg.Exists("lib [lib.test]") || dg.Exists("lib_test") {
+			t.Error("test variants should not be packages")
+		}
+		if e := dg.Edge("lib", "fmt"); e == nil || e.TestOnly {
+			t.Error("build edge should stay plain", e)
+		}
+		for _, to := range []string{"testhelper", "cmd/tool"} {
+			if e := dg.Edge("lib", to); e == nil || !e.TestOnly {
+				t.Error("edge should be test-only", to, e)
+			}
+		}
+		if dg.Edge("lib", "lib") != nil {
+			t.Error("xtest import of its own package should be dropped")
+		}
+		if importers := dg.Importers("testhelper"); len(importers) != 0 {
+			t.Error("test-only importers should not be listed", importers)
+		}
+		if imports := dg.Imports("lib"); len(imports) != 1 || imports[0] != "fmt" {
+			t.Error("test-only imports should not be listed", imports)
+		}
+		// the build itself doesn't change: cmd/tool -> lib -> cmd/tool
+		// is not a cycle and testhelper is only needed by tests
+		if dg.PathExists("cmd/tool", "testhelper") || len(dg.SearchMain("testhelper")) != 0 {
+			t.Error("test-only edges should not be part of the build")
+		}
+		if unused := dg.ListUnUsed(); len(unused) != 1 || unused[0] != "testhelper" {
+			t.Error("unused error", unused)
+		}
+		if chains := dg.SearchChain("fmt"); len(chains) != 1 || len(chains[0]) != 4 {
+			t.Error("chain error", chains)
+		}
+	}
+}
+
+func TestGoListTest(t *testing.T) {
+	f, err := os.Open("testdata/test_deps.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dg, err := LoadDeps(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		app  = "example.com/fix/cmd/app"
+		lib  = "example.com/fix/lib"
+		util = "example.com/fix/util"
+	)
+	if dangling := dg.Dangling(); len(dangling) != 0 {
+		t.Error("test variants should not be dangling", dangling)
+	}
+	if !dg.IsTestPackage(lib+".test") || !dg.IsMainPackage(app) {
+		t.Error("main packages error")
+	}
+	if e := dg.Edge(lib+".test", lib); e == nil || e.Vendored || e.TestOnly {
+		t.Error("test main should import the tested package", e)
+	}
+	if e := dg.Edge(lib, "bytes"); e == nil || !e.TestOnly {
+		t.Error("edge should be test-only", e)
+	}
+	if imports := dg.Imports(lib); len(imports) != 1 || imports[0] != util {
+		t.Error("imports error", imports)
+	}
+	if importers := dg.Importers("testing"); sliceContains(importers, lib) || sliceContains(importers, util) {
+		t.Error("test-only importers should not be listed", importers)
+	}
+	var buf bytes.Buffer
+	dg.WriteIndex(&buf)
+	ix, err := ParseIndex(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imports := ix.Imports(lib); len(imports) != 1 || imports[0] != util || sliceContains(ix.Importers("testing"), lib) {
+		t.Error("index should leave test-only edges out", imports)
+	}
+	if chain := FindChain(dg, app, "bytes"); chain != nil {
+		t.Error("chain should not go through test-only edges", chain)
+	}
+	mains, tests := dg.Impacted([]string{util})
+	if len(mains) != 1 || mains[0] != app || len(tests) != 2 || tests[0] != lib+".test" || tests[1] != util+".test" {
+		t.Error("impacted error", mains, tests)
+	}
+	if mains, tests := dg.Impacted([]string{lib}); len(mains) != 1 || len(tests) != 1 || tests[0] != lib+".test" {
+		t.Error("impacted error", mains, tests)
+	}
+	chains := dg.TestChains(util)
+	if len(chains) != 2 || strings.Join(chains[0], " ") != lib+".test "+lib+" "+util ||
+		strings.Join(chains[1], " ") != util+".test "+util {
+		t.Error("test chains error", chains)
+	}
+}
+
+func TestNormalizeVendor(t *testing.T) {
+	f, err := os.Open("testdata/go1.12.5_deps.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	dg := &DepGraph{}
+	dg.NormalizeVendor(true)
+	if err := dg.Load(f); err != nil {
+		t.Fatal(err)
+	}
+	if dg.Exists("cmd/vendor/github.com/google/pprof/driver") || !dg.Exists("github.com/google/pprof/driver") {
+		t.Error("vendored path should be normalized")
+	}
+	e := dg.Edge("cmd/pprof", "github.com/google/pprof/driver")
+	if e == nil || !e.Vendored || e.VendorPath != "cmd/vendor/github.com/google/pprof/driver" {
+		t.Error("edge should remember the vendored path", e)
+	}
+	if mains := dg.SearchMain("golang.org/x/tools/go/analysis"); !sliceContains(mains, "cmd/vet") {
+		t.Error("deps should be normalized too", mains)
+	}
+	if e := dg.Edge("net/http", "net/url"); e == nil || e.Vendored || e.VendorPath != "" {
+		t.Error("edge should be plain", e)
+	}
+}
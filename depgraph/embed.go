@@ -0,0 +1,69 @@
+package depgraph
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Embed is a package embedding files with //go:embed, see Embedders.
+type Embed struct {
+	Package string
+	Files   []string // absolute paths of the embedded files, sorted
+}
+
+// EmbedFiles returns the files packageName embeds with //go:embed, as
+// reported by the EmbedFiles field of go list, relative to its directory.
+func (g *DepGraph) EmbedFiles(packageName string) []string {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return nil
+	}
+	return g.embeds[id]
+}
+
+// Embedders returns the packages embedding files below path, a directory
+// or a file, sorted by package. Relative paths are resolved from the
+// working directory, like PackageAt does. Test binaries are left out, they
+// embed the files of the package they test.
+func (g *DepGraph) Embedders(path string) (embeds []Embed) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	for id, files := range g.embeds {
+		if g.testPackages[id] {
+			continue
+		}
+		var below []string
+		for _, f := range files {
+			f = filepath.Join(g.dirs[id], f)
+			if f == abs || strings.HasPrefix(f, abs+string(filepath.Separator)) {
+				below = append(below, f)
+			}
+		}
+		if len(below) > 0 {
+			sort.Strings(below)
+			embeds = append(embeds, Embed{Package: g.names[id], Files: below})
+		}
+	}
+	sort.Slice(embeds, func(i, j int) bool { return embeds[i].Package < embeds[j].Package })
+	return
+}
+
+// EmbeddingMains returns the main packages embedding files below path, or
+// depending on a package that does, sorted: the binaries shipping them.
+func (g *DepGraph) EmbeddingMains(path string) []string {
+	seen := make(map[string]bool)
+	for _, e := range g.Embedders(path) {
+		for _, m := range g.SearchMain(e.Package) {
+			seen[m] = true
+		}
+	}
+	mains := make([]string, 0, len(seen))
+	for m := range seen {
+		mains = append(mains, m)
+	}
+	sort.Strings(mains)
+	return mains
+}
@@ -0,0 +1,44 @@
+package depgraph
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbedders(t *testing.T) {
+	root := filepath.FromSlash("/src/app")
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "app/cmd/web", Name: "main", Dir: filepath.Join(root, "cmd/web"),
+		Imports: []string{"app/ui"}, Deps: []string{"app/ui"}})
+	dg.Add(DepInfo{ImportPath: "app/cmd/cli", Name: "main", Dir: filepath.Join(root, "cmd/cli"),
+		EmbedPatterns: []string{"help.txt"}, EmbedFiles: []string{"help.txt"}})
+	dg.Add(DepInfo{ImportPath: "app/ui", Dir: filepath.Join(root, "ui"),
+		EmbedPatterns: []string{"static"}, EmbedFiles: []string{"static/app.js", "static/index.html"}})
+
+	if files := dg.EmbedFiles("app/ui"); len(files) != 2 {
+		t.Error(files)
+	}
+	embeds := dg.Embedders(filepath.Join(root, "ui/static"))
+	if len(embeds) != 1 || embeds[0].Package != "app/ui" ||
+		embeds[0].Files[1] != filepath.Join(root, "ui/static/index.html") {
+		t.Error(embeds)
+	}
+	// a prefix of a directory name is not below it
+	if embeds := dg.Embedders(filepath.Join(root, "ui/stat")); len(embeds) != 0 {
+		t.Error(embeds)
+	}
+	for path, expect := range map[string]string{
+		root:                                    "app/cmd/cli app/cmd/web",
+		filepath.Join(root, "ui"):               "app/cmd/web",
+		filepath.Join(root, "cmd/cli/help.txt"): "app/cmd/cli",
+		filepath.Join(root, "lib"):              "",
+	} {
+		if mains := dg.EmbeddingMains(path); strings.Join(mains, " ") != expect {
+			t.Error(path, mains)
+		}
+	}
+	if files := dg.Freeze().EmbedFiles("app/cmd/cli"); len(files) != 1 {
+		t.Error("frozen", files)
+	}
+}
@@ -0,0 +1,61 @@
+package depgraph
+
+import "sort"
+
+// Gap is where an import chain may break: Package depends on the target
+// per its Deps, but none of its imports does, or some of its imports have
+// no record in the input. Either its own imports or the records of some of
+// them are missing from the input.
+type Gap struct {
+	Package string
+	Loaded  bool     // the input has a record of Package
+	Missing []string // imports of Package without a record in the input, sorted
+}
+
+// ChainGaps explains why SearchChain gives a "..." chain from main to
+// target: it follows the imports from main through the packages depending
+// on target and returns, sorted, those where the trail ends or may go on
+// through packages without a record. It returns nil if main doesn't
+// depend on target or a chain exists.
+func (g *DepGraph) ChainGaps(main, target string) (gaps []Gap) {
+	mainID, ok := g.lookup(main)
+	if !ok {
+		return
+	}
+	targetID, ok := g.lookup(target)
+	if !ok || !g.dependsOn(mainID, targetID) {
+		return
+	}
+	seen := map[nodeID]bool{mainID: true}
+	queue := []nodeID{mainID}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		onward := false
+		var missing []string
+		for _, e := range g.imports[id] {
+			if !e.inBuild() {
+				continue
+			}
+			if e.to == targetID {
+				return nil
+			}
+			if !g.loaded[e.to] {
+				missing = append(missing, g.names[e.to])
+			}
+			if g.dependsOn(e.to, targetID) {
+				onward = true
+				if !seen[e.to] {
+					seen[e.to] = true
+					queue = append(queue, e.to)
+				}
+			}
+		}
+		if !onward || len(missing) > 0 {
+			sort.Strings(missing)
+			gaps = append(gaps, Gap{Package: g.names[id], Loaded: g.loaded[id], Missing: missing})
+		}
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i].Package < gaps[j].Package })
+	return
+}
@@ -0,0 +1,38 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChainGaps(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "z", Name: "z"})
+	dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"z"}, Deps: []string{"z"}})
+	// shared claims z in Deps without importing it
+	dg.Add(DepInfo{ImportPath: "shared", Name: "shared", Deps: []string{"z"}})
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"lib"}, Deps: []string{"lib", "z"}})
+	dg.Add(DepInfo{ImportPath: "cmd/b", Name: "main", Imports: []string{"shared", "gone"}, Deps: []string{"gone", "shared", "z"}})
+	dg.Add(DepInfo{ImportPath: "cmd/c", Name: "main", Imports: []string{"gone"}, Deps: []string{"gone", "z"}})
+
+	if gaps := dg.ChainGaps("cmd/a", "z"); gaps != nil {
+		t.Error(gaps)
+	}
+	if gaps := dg.ChainGaps("lib", "cmd/a"); gaps != nil {
+		t.Error(gaps)
+	}
+	if gaps := dg.ChainGaps("cmd/b", "z"); !reflect.DeepEqual(gaps, []Gap{
+		{Package: "cmd/b", Loaded: true, Missing: []string{"gone"}},
+		{Package: "shared", Loaded: true},
+	}) {
+		t.Error(gaps)
+	}
+
+	chains := dg.SearchChains("z")
+	if len(chains) != 3 || chains[0].Gaps != nil {
+		t.Fatal(chains)
+	}
+	if c := chains[2]; c.Complete || !reflect.DeepEqual(c.Gaps, []Gap{{Package: "cmd/c", Loaded: true, Missing: []string{"gone"}}}) {
+		t.Error(c)
+	}
+}
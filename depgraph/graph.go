@@ -0,0 +1,234 @@
+package depgraph
+
+import (
+	"container/list"
+	"sort"
+)
+
+// Graph is the minimal view of an import graph the generic search
+// algorithms below need. DepGraph implements it; alternative backends
+// (disk-backed, module-level, test-only views) only have to provide these
+// four methods to reuse the same query code.
+type Graph interface {
+	AddEdge(from, to string)
+	Imports(pkg string) []string
+	Importers(pkg string) []string
+	Has(pkg string) bool
+}
+
+var _ Graph = (*DepGraph)(nil)
+
+func (g *DepGraph) AddEdge(from, to string) {
+	g.mustNotBeFrozen()
+	g.lazyInit()
+	fromID, toID := g.intern(from), g.intern(to)
+	for _, id := range []nodeID{fromID, toID} {
+		g.loaded[id] = true
+		if g.imports[id] == nil {
+			g.imports[id] = []edge{}
+		}
+	}
+	if g.edgeTo(fromID, toID) == nil {
+		imports := append(g.imports[fromID][:len(g.imports[fromID]):len(g.imports[fromID])],
+			edge{to: toID, attrs: &EdgeAttrs{}})
+		g.setImports(fromID, imports)
+		g.updateClosure(fromID)
+	}
+	added := append([]nodeID{toID}, g.deps[toID]...)
+	for _, p := range append([]nodeID{fromID}, g.dependents[fromID]...) {
+		g.setDeps(p, mergeIDs(g.deps[p], added))
+	}
+	g.changed()
+}
+
+// mergeIDs returns the sorted union of the sorted set a and b.
+func mergeIDs(a, b []nodeID) []nodeID {
+	seen := make(map[nodeID]bool, len(a)+len(b))
+	merged := make([]nodeID, 0, len(a)+len(b))
+	for _, ids := range [][]nodeID{a, b} {
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				merged = append(merged, id)
+			}
+		}
+	}
+	sortIDs(merged)
+	return merged
+}
+
+func (g *DepGraph) Imports(pkg string) (packages []string) {
+	id, ok := g.lookup(pkg)
+	if !ok {
+		return
+	}
+	for _, e := range g.imports[id] {
+		if e.inBuild() {
+			packages = append(packages, g.names[e.to])
+		}
+	}
+	sort.Strings(packages)
+	return
+}
+
+func (g *DepGraph) Importers(pkg string) (packages []string) {
+	target, ok := g.lookup(pkg)
+	if !ok {
+		return
+	}
+	for _, id := range g.importers[target] {
+		if g.names[id] != "main" && g.buildImports(id, target) {
+			packages = append(packages, g.names[id])
+		}
+	}
+	sort.Strings(packages)
+	return
+}
+
+func (g *DepGraph) Has(pkg string) bool {
+	return g.Exists(pkg)
+}
+
+// ReachableTo returns every package in g that transitively imports target.
+func ReachableTo(g Graph, target string) map[string]bool {
+	reached := make(map[string]bool)
+	l := list.New()
+	l.PushBack(target)
+	for e := l.Front(); e != nil; e = e.Next() {
+		for _, p := range g.Importers(e.Value.(string)) {
+			if !reached[p] {
+				reached[p] = true
+				l.PushBack(p)
+			}
+		}
+	}
+	return reached
+}
+
+// FindChain returns the shortest import chain from start to target,
+// both included, or nil if target is not reachable from start.
+func FindChain(g Graph, start, target string) []string {
+	if start == target {
+		return []string{start}
+	}
+	parent := map[string]string{start: ""}
+	l := list.New()
+	l.PushBack(start)
+	for e := l.Front(); e != nil; e = e.Next() {
+		from := e.Value.(string)
+		for _, p := range g.Imports(from) {
+			if _, ok := parent[p]; ok {
+				continue
+			}
+			parent[p] = from
+			if p == target {
+				chain := []string{}
+				for ; p != ""; p = parent[p] {
+					chain = append(chain, p)
+				}
+				reverseSlice(chain)
+				return chain
+			}
+			l.PushBack(p)
+		}
+	}
+	return nil
+}
+
+// FindGraph is the Graph counterpart of DepGraph.SearchGraph: it returns
+// every edge lying on some path from start to target.
+func FindGraph(g Graph, start, target string) (result map[string][]string) {
+	reaches := ReachableTo(g, target)
+	if !reaches[start] {
+		return
+	}
+	result = make(map[string][]string)
+	checked := map[string]bool{start: true}
+	l := list.New()
+	l.PushBack(start)
+	for e := l.Front(); e != nil; e = e.Next() {
+		from := e.Value.(string)
+		for _, p := range g.Imports(from) {
+			if p == target {
+				result[from] = append(result[from], p)
+				continue
+			}
+			if reaches[p] {
+				if !checked[p] {
+					checked[p] = true
+					l.PushBack(p)
+				}
+				result[from] = append(result[from], p)
+			}
+		}
+	}
+	return
+}
+
+// ReachableFrom returns every package in g that start transitively imports.
+func ReachableFrom(g Graph, start string) map[string]bool {
+	reached := make(map[string]bool)
+	l := list.New()
+	l.PushBack(start)
+	for e := l.Front(); e != nil; e = e.Next() {
+		for _, p := range g.Imports(e.Value.(string)) {
+			if !reached[p] {
+				reached[p] = true
+				l.PushBack(p)
+			}
+		}
+	}
+	return reached
+}
+
+// Ancestors returns the reverse transitive closure of packageName: every
+// package importing it directly or indirectly. Unlike SearchAll it walks
+// the Imports edges and does not depend on the Deps field being complete.
+func (g *DepGraph) Ancestors(packageName string) map[string]bool {
+	return ReachableTo(g, packageName)
+}
+
+// Descendants returns the forward transitive closure of packageName: every
+// package it imports directly or indirectly.
+func (g *DepGraph) Descendants(packageName string) map[string]bool {
+	return ReachableFrom(g, packageName)
+}
+
+// PathExists reports whether from transitively imports to. The first
+// call computes the transitive closure of the whole graph; after that each
+// check is a single bitset lookup until the graph changes.
+func (g *DepGraph) PathExists(from, to string) bool {
+	fromID, ok := g.lookup(from)
+	if !ok || !g.loaded[fromID] {
+		return false
+	}
+	if from == to {
+		return true
+	}
+	toID, ok := g.lookup(to)
+	return ok && g.closure()[fromID].has(toID)
+}
+
+// WouldCreateCycle reports whether adding an import of to in from would
+// create an import cycle, and if so returns the cycle from -> to -> ... ->
+// from it would close. It lets a pre-commit hook answer "can from import
+// to?" before the import is written; test-only imports are ignored like
+// the go command does.
+func (g *DepGraph) WouldCreateCycle(from, to string) (bool, []string) {
+	if from == to {
+		return true, []string{from, to}
+	}
+	fromID, ok := g.lookup(from)
+	if !ok {
+		return false, nil
+	}
+	toID, ok := g.lookup(to)
+	if !ok || !g.loaded[toID] {
+		return false, nil
+	}
+	g.prepare()
+	if !g.dependsOn(toID, fromID) {
+		return false, nil
+	}
+	return true, append([]string{from}, g.moduleChain(toID, map[nodeID]bool{fromID: true})...)
+}
@@ -0,0 +1,100 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphInterface(t *testing.T) {
+	dg := loadTestGraph(t)
+	var g Graph = dg
+	expect := dg.SearchGraph("net/http", "net")
+	result := FindGraph(g, "net/http", "net")
+	if len(result) != len(expect) {
+		t.Error(len(result), len(expect))
+	}
+	for from, tos := range expect {
+		if len(result[from]) != len(tos) {
+			t.Error(from, result[from], tos)
+		}
+	}
+	chain := FindChain(g, "cmd/vet", "fmt")
+	if len(chain) < 2 || chain[0] != "cmd/vet" || chain[len(chain)-1] != "fmt" {
+		t.Error("result error", chain)
+	}
+	if FindChain(g, "fmt", "net/http") != nil {
+		t.Error("fmt should not import net/http")
+	}
+	if !sliceContains(g.Importers("net/url"), "net/http") {
+		t.Error("net/http should import net/url")
+	}
+
+	g.AddEdge("a", "b")
+	g.AddEdge("b", "fmt")
+	if !g.Has("a") || !sliceContains(dg.SearchAll("fmt"), "a") {
+		t.Error("a should depend on fmt")
+	}
+	if strings.Join(FindChain(g, "a", "fmt"), " ") != "a b fmt" {
+		t.Error(FindChain(g, "a", "fmt"))
+	}
+}
+
+func TestAncestorsAndDescendants(t *testing.T) {
+	dg := loadTestGraph(t)
+	ancestors := dg.Ancestors("net/url")
+	for _, p := range dg.SearchAll("net/url") {
+		if !ancestors[p] {
+			t.Error("ancestors should contain", p)
+		}
+	}
+	if ancestors["net/url"] || ancestors["fmt"] {
+		t.Error("ancestors error")
+	}
+	descendants := dg.Descendants("net/http")
+	for _, p := range []string{"net/url", "net", "io", "runtime"} {
+		if !descendants[p] {
+			t.Error("descendants should contain", p)
+		}
+	}
+	if descendants["net/http"] {
+		t.Error("descendants should not contain itself")
+	}
+}
+
+func TestPathExists(t *testing.T) {
+	dg := loadTestGraph(t)
+	if !dg.PathExists("cmd/vet", "fmt") || !dg.PathExists("net/http", "net/url") {
+		t.Error("path should exist")
+	}
+	if dg.PathExists("fmt", "net/http") || dg.PathExists("fmtxxxxxxx", "fmt") {
+		t.Error("path should not exist")
+	}
+	dg.AddEdge("fmt", "x")
+	if !dg.PathExists("cmd/vet", "x") {
+		t.Error("cache should be reset after AddEdge")
+	}
+}
+
+func TestWouldCreateCycle(t *testing.T) {
+	dg := loadTestGraph(t)
+	if ok, cycle := dg.WouldCreateCycle("net/url", "net/http"); !ok || cycle[0] != "net/url" ||
+		cycle[1] != "net/http" || cycle[len(cycle)-1] != "net/url" {
+		t.Error(ok, cycle)
+	}
+	if ok, cycle := dg.WouldCreateCycle("net/http", "net/url"); ok || cycle != nil {
+		t.Error("importing a dep again should not create a cycle", cycle)
+	}
+	if ok, _ := dg.WouldCreateCycle("fmtxxxxxxx", "fmt"); ok {
+		t.Error("unknown package should not create a cycle")
+	}
+	if ok, cycle := dg.WouldCreateCycle("fmt", "fmt"); !ok || len(cycle) != 2 {
+		t.Error("self import should be a cycle", cycle)
+	}
+
+	small := &DepGraph{}
+	small.AddEdge("a", "b")
+	small.AddEdge("b", "c")
+	if ok, cycle := small.WouldCreateCycle("c", "a"); !ok || strings.Join(cycle, " ") != "c a b c" {
+		t.Error(ok, cycle)
+	}
+}
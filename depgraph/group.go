@@ -0,0 +1,128 @@
+package depgraph
+
+import (
+	"sort"
+	"strings"
+)
+
+// Grouper maps a package to the group it collapses into, or to "" to leave
+// the package out of the grouped graph.
+type Grouper func(pkg string) string
+
+// ByModule groups packages by module path, the standard library into
+// "std". Packages without module information stay on their own.
+func (g *DepGraph) ByModule() Grouper {
+	return func(pkg string) string {
+		if g.IsStandard(pkg) {
+			return "std"
+		}
+		if m := g.Module(pkg); m != nil {
+			return m.Path
+		}
+		return pkg
+	}
+}
+
+// ByPrefix groups packages by the first of prefixes they are under, eg:
+// "team-a/**" collapses team-a and every package below it into "team-a".
+// A trailing "/**" or "/..." is optional. Packages under no prefix are
+// left out.
+func ByPrefix(prefixes []string) Grouper {
+	trimmed := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		p = strings.TrimSuffix(p, "/**")
+		trimmed[i] = strings.TrimSuffix(p, "/...")
+	}
+	return func(pkg string) string {
+		for _, p := range trimmed {
+			if pkg == p || strings.HasPrefix(pkg, p+"/") {
+				return p
+			}
+		}
+		return ""
+	}
+}
+
+// Group returns the coarse-grained graph of g with the packages collapsed
+// by by: group A imports group B when a package of A imports one of B, and
+// likewise for deps. A group is a main package if one of its packages is,
+// standard if all of them are, and keeps their module if they share one.
+// Test-only imports and imports within a group are dropped, so exports and
+// stats of the result show team-to-team or module-to-module dependencies.
+func (g *DepGraph) Group(by Grouper) *DepGraph {
+	g.prepare()
+	type group struct {
+		info    DepInfo
+		imports map[string]bool
+		deps    map[string]bool
+		std     bool
+		modules map[string]*Module
+		size    Size
+	}
+	groups := make(map[string]*group)
+	var order []string
+	for id, name := range g.names {
+		id := nodeID(id)
+		if !g.loaded[id] {
+			continue
+		}
+		self := by(name)
+		if self == "" {
+			continue
+		}
+		gr := groups[self]
+		if gr == nil {
+			gr = &group{info: DepInfo{ImportPath: self}, imports: map[string]bool{},
+				deps: map[string]bool{}, std: true, modules: map[string]*Module{}}
+			groups[self] = gr
+			order = append(order, self)
+		}
+		if g.mainPackages[id] {
+			gr.info.Name = "main"
+		}
+		gr.std = gr.std && g.isStandard(id)
+		if m := g.modules[id]; m != nil {
+			gr.modules[m.Path] = m
+		}
+		gr.size.add(g.sizes[id])
+		add := func(set map[string]bool, dep nodeID) {
+			if other := by(g.names[dep]); other != "" && other != self {
+				set[other] = true
+			}
+		}
+		for _, e := range g.imports[id] {
+			if e.inBuild() {
+				add(gr.imports, e.to)
+			}
+		}
+		g.eachDep(id, func(dep nodeID) { add(gr.deps, dep) })
+	}
+	grouped := &DepGraph{}
+	for _, name := range order {
+		gr := groups[name]
+		gr.info.Standard = gr.std
+		for _, m := range gr.modules {
+			if len(gr.modules) == 1 {
+				gr.info.Module = m
+			}
+		}
+		gr.info.Imports = groupNames(gr.imports)
+		gr.info.Deps = groupNames(gr.deps)
+		grouped.Add(gr.info)
+		grouped.sizes[grouped.ids[name]] = gr.size
+	}
+	// the standard flags are computed from the packages, not guessed from
+	// the group names
+	grouped.sawStandard = true
+	grouped.build = g.build
+	return grouped
+}
+
+func groupNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,59 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroupByPrefix(t *testing.T) {
+	dg := loadTestGraph(t)
+	grouped := dg.Group(ByPrefix([]string{"net/**", "cmd/...", "encoding"}))
+	if got := strings.Join(grouped.Packages(), " "); got != "cmd encoding net" {
+		t.Fatal(got)
+	}
+	if !grouped.IsMainPackage("cmd") || grouped.IsMainPackage("net") || !grouped.IsStandard("net") {
+		t.Error("group flags error")
+	}
+	if !grouped.PathExists("cmd", "net") || grouped.PathExists("net", "cmd") {
+		t.Error("cmd should depend on net only")
+	}
+	for _, p := range grouped.Imports("net") {
+		if p == "net" {
+			t.Error("imports within a group should be dropped")
+		}
+	}
+}
+
+func TestGroupByModule(t *testing.T) {
+	dg := &DepGraph{}
+	app := &Module{Path: "example.com/app", Main: true}
+	lib := &Module{Path: "example.com/lib", Version: "v1.0.0"}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Module: app, GoFiles: []string{"a.go"},
+		Imports: []string{"example.com/app/util", "example.com/lib/x"},
+		Deps:    []string{"example.com/app/util", "example.com/lib/x", "example.com/lib/y", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/util", Module: app, GoFiles: []string{"u.go", "v.go"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib/x", Module: lib, Imports: []string{"example.com/lib/y", "fmt"},
+		Deps: []string{"example.com/lib/y", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib/y", Module: lib})
+	dg.Add(DepInfo{ImportPath: "fmt", Standard: true})
+
+	grouped := dg.Group(dg.ByModule())
+	if got := strings.Join(grouped.Packages(), " "); got != "example.com/app example.com/lib std" {
+		t.Fatal(got)
+	}
+	if got := strings.Join(grouped.Deps("example.com/app"), " "); got != "example.com/lib std" {
+		t.Error(got)
+	}
+	if got := strings.Join(grouped.Imports("example.com/lib"), " "); got != "std" {
+		t.Error(got)
+	}
+	if m := grouped.Module("example.com/lib"); m == nil || m.Version != "v1.0.0" {
+		t.Error("group should keep its module", m)
+	}
+	if s := grouped.Size("example.com/app"); s.Files != 3 {
+		t.Error("group size should add up", s)
+	}
+	if !grouped.IsStandard("std") || grouped.IsStandard("example.com/app") {
+		t.Error("standard flags error")
+	}
+}
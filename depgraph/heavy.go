@@ -0,0 +1,59 @@
+package depgraph
+
+import "sort"
+
+// DepWeight is what one direct import of a main package costs the binary.
+type DepWeight struct {
+	Import    string
+	Exclusive int // packages only this import brings in, itself included
+	Total     int // packages this import brings in, itself included
+}
+
+// HeaviestDeps ranks the direct third-party imports of mainPackage, those
+// neither in the standard library nor first-party (see FirstParty), by the
+// number of packages they alone bring in: packages no other direct import
+// of mainPackage reaches. Those are the packages dropping the import would
+// remove from the binary, so the top entries are the imports to attack
+// first. Ties are broken by Total, then by import path.
+func (g *DepGraph) HeaviestDeps(mainPackage string) (weights []DepWeight) {
+	id, ok := g.lookup(mainPackage)
+	if !ok {
+		return
+	}
+	reach := g.closure()
+	sets := make(map[nodeID]bitset)
+	refs := make(map[nodeID]int) // how many direct imports reach a package
+	for _, e := range g.imports[id] {
+		if !e.inBuild() {
+			continue
+		}
+		var b bitset
+		b.set(e.to)
+		b.or(reach[e.to])
+		sets[e.to] = b
+		b.each(func(p nodeID) { refs[p]++ })
+	}
+	for imp, b := range sets {
+		if !g.isThirdParty(imp) {
+			continue
+		}
+		w := DepWeight{Import: g.names[imp], Total: b.count()}
+		b.each(func(p nodeID) {
+			if refs[p] == 1 {
+				w.Exclusive++
+			}
+		})
+		weights = append(weights, w)
+	}
+	sort.Slice(weights, func(i, j int) bool {
+		a, b := weights[i], weights[j]
+		if a.Exclusive != b.Exclusive {
+			return a.Exclusive > b.Exclusive
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Import < b.Import
+	})
+	return
+}
@@ -0,0 +1,34 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeaviestDeps(t *testing.T) {
+	dg := &DepGraph{}
+	dg.AddEdge("example.com/cmd/a", "example.com/aws")
+	dg.AddEdge("example.com/cmd/a", "example.com/log")
+	dg.AddEdge("example.com/cmd/a", "fmt")
+	dg.AddEdge("example.com/aws", "example.com/aws/s3")
+	dg.AddEdge("example.com/aws/s3", "example.com/xml")
+	dg.AddEdge("example.com/aws", "example.com/log")
+	dg.AddEdge("example.com/log", "fmt")
+	expect := []DepWeight{
+		{Import: "example.com/aws", Exclusive: 3, Total: 5},
+		{Import: "example.com/log", Exclusive: 0, Total: 2},
+	}
+	if got := dg.HeaviestDeps("example.com/cmd/a"); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	if got := dg.HeaviestDeps("nope"); got != nil {
+		t.Error(got)
+	}
+	app := &Module{Path: "example.com/app", Main: true}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/b", Name: "main", Module: app,
+		Imports: []string{"example.com/app/internal/x", "example.com/log"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/internal/x", Name: "x", Module: app})
+	if got := dg.HeaviestDeps("example.com/app/cmd/b"); len(got) != 1 || got[0].Import != "example.com/log" {
+		t.Error("packages of the main module are not third-party", got)
+	}
+}
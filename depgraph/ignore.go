@@ -0,0 +1,52 @@
+package depgraph
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether the import path pkg matches pattern. Elements
+// of pattern are matched with path.Match, except "**" which matches any
+// number of elements, none included, eg: "**/mocks/**" matches the
+// packages in or below any mocks directory, and "example.com/**/gen" the
+// gen packages of example.com.
+func MatchGlob(pattern, pkg string) bool {
+	return matchElems(strings.Split(pattern, "/"), strings.Split(pkg, "/"))
+}
+
+func matchElems(pattern, elems []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(elems); i++ {
+				if matchElems(pattern[1:], elems[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(elems) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], elems[0]); !ok {
+			return false
+		}
+		pattern, elems = pattern[1:], elems[1:]
+	}
+	return len(elems) == 0
+}
+
+// Ignored returns a function reporting whether a package matches one of
+// patterns, see MatchGlob, eg: to Prune generated code and mocks so they
+// don't dominate unused package reports and fan-in statistics. Test
+// variants like "p [p.test]" match like p.
+func Ignored(patterns []string) func(pkg string) bool {
+	return func(pkg string) bool {
+		base, _ := testVariantBase(pkg)
+		for _, p := range patterns {
+			if MatchGlob(p, base) {
+				return true
+			}
+		}
+		return false
+	}
+}
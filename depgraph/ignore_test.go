@@ -0,0 +1,44 @@
+package depgraph
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	for _, c := range []struct {
+		pattern, pkg string
+		match        bool
+	}{
+		{"**/mocks/**", "example.com/app/mocks", true},
+		{"**/mocks/**", "example.com/app/db/mocks/sql", true},
+		{"**/mocks/**", "example.com/app/mocksql", false},
+		{"**/gen", "example.com/app/api/gen", true},
+		{"**/gen", "example.com/app/gen/api", false},
+		{"example.com/**/gen", "example.com/gen", true},
+		{"example.com/**/gen", "other.com/x/gen", false},
+		{"**/*_mock", "example.com/app/db_mock", true},
+		{"**", "fmt", true},
+		{"example.com/app", "example.com/app/cmd", false},
+	} {
+		if MatchGlob(c.pattern, c.pkg) != c.match {
+			t.Error(c.pattern, c.pkg, !c.match)
+		}
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Imports: []string{"example.com/app/db"},
+		Deps: []string{"example.com/app/db"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/db"})
+	dg.Add(DepInfo{ImportPath: "example.com/app/db/mocks", Imports: []string{"example.com/app/db"},
+		Deps: []string{"example.com/app/db"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/db/mocks [example.com/app/db.test]"})
+
+	ignored := Ignored([]string{"**/mocks/**", "**/gen/**"})
+	if !ignored("example.com/app/db/mocks [example.com/app/db.test]") || ignored("example.com/app/db") {
+		t.Error("Ignored error")
+	}
+	p := dg.Prune(ignored)
+	if p.Exists("example.com/app/db/mocks") || len(p.Importers("example.com/app/db")) != 1 {
+		t.Error(p.Packages(), p.Importers("example.com/app/db"))
+	}
+}
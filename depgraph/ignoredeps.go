@@ -0,0 +1,55 @@
+package depgraph
+
+// IgnoreDeps makes queries derive every package's dependencies from the
+// Imports edges instead of trusting the Deps field. Dumps produced without
+// -deps, or filtered afterwards, list incomplete Deps and would otherwise
+// give "..." chains and missing SearchAll results. The closure is computed
+// lazily on the first query and cached until the graph changes.
+func (g *DepGraph) IgnoreDeps(ignore bool) {
+	g.ignoreDeps = ignore
+	g.changed()
+}
+
+// prepare computes the caches queries read, so they can run concurrently
+// afterwards without writing to the graph.
+func (g *DepGraph) prepare() {
+	if g.ignoreDeps {
+		g.closure()
+	}
+}
+
+// dependentsOf returns the packages depending on target, sorted by ID.
+func (g *DepGraph) dependentsOf(target nodeID) []nodeID {
+	if !g.ignoreDeps {
+		return g.dependents[target]
+	}
+	reach := g.closure()
+	return g.filterNodes(func(id nodeID) bool {
+		return g.loaded[id] && reach[id].has(target)
+	})
+}
+
+// used reports whether any package depends on p.
+func (g *DepGraph) used(p nodeID) bool {
+	if !g.ignoreDeps {
+		return len(g.dependents[p]) > 0
+	}
+	// nobody depends on p transitively unless somebody imports it
+	for _, importer := range g.importers[p] {
+		if g.loaded[importer] && !g.edgeTo(importer, p).TestOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// eachDep calls fn for every package id depends on.
+func (g *DepGraph) eachDep(id nodeID, fn func(dep nodeID)) {
+	if g.ignoreDeps {
+		g.closure()[id].each(fn)
+		return
+	}
+	for _, dep := range g.deps[id] {
+		fn(dep)
+	}
+}
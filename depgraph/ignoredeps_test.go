@@ -0,0 +1,29 @@
+package depgraph
+
+import "testing"
+
+func TestIgnoreDeps(t *testing.T) {
+	dg := &DepGraph{}
+	// a partial dump: Deps only lists direct imports
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Deps: []string{"b"}, Imports: []string{"b"}})
+	dg.Add(DepInfo{ImportPath: "b", Name: "b", Deps: []string{"c"}, Imports: []string{"c"}})
+	dg.Add(DepInfo{ImportPath: "c", Name: "c"})
+	if len(dg.SearchChain("c")) != 0 || sliceContains(dg.SearchAll("c"), "cmd/a") {
+		t.Error("Deps of cmd/a does not contain c")
+	}
+	dg.IgnoreDeps(true)
+	chains := dg.SearchChain("c")
+	if len(chains) != 1 || len(chains[0]) != 4 || chains[0][2] != "b" {
+		t.Error("result error", chains)
+	}
+	all := dg.SearchAll("c")
+	if len(all) != 2 || !sliceContains(all, "cmd/a") || !sliceContains(all, "b") {
+		t.Error("result error", all)
+	}
+	if unused := dg.ListUnUsed(); len(unused) != 0 {
+		t.Error("result error", unused)
+	}
+	if graph := dg.SearchGraph("cmd/a", "c"); len(graph) != 2 {
+		t.Error("result error", graph)
+	}
+}
@@ -0,0 +1,79 @@
+package depgraph
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// PackageAt returns the package whose directory holds path, a file or a
+// directory, as reported by the Dir field of go list. It lets file paths
+// from git diff be mapped to packages; relative paths are resolved from
+// the working directory.
+func (g *DepGraph) PackageAt(path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	if fi, err := os.Stat(abs); err != nil || !fi.IsDir() {
+		abs = filepath.Dir(abs)
+	}
+	for id, dir := range g.dirs {
+		// test binaries share the directory of the package they test
+		if dir == abs && !g.testPackages[id] {
+			return g.names[id], true
+		}
+	}
+	return "", false
+}
+
+// PackagesAt returns the packages listed with dir as directory, test
+// binaries included, sorted, eg: to remove them once dir is gone.
+func (g *DepGraph) PackagesAt(dir string) (packages []string) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+	for id, d := range g.dirs {
+		if d == abs {
+			packages = append(packages, g.names[id])
+		}
+	}
+	sort.Strings(packages)
+	return
+}
+
+// Impacted returns the main packages and the test binaries (the ".test"
+// packages of go list -test) that are changed or depend on a changed
+// package, sorted: the binaries a selective CI has to rebuild and the
+// tests it has to rerun. Deps recompiled for a test, like "q [p.test]",
+// count as q.
+func (g *DepGraph) Impacted(changed []string) (mains, tests []string) {
+	g.prepare()
+	isChanged := make(map[string]bool, len(changed))
+	for _, p := range changed {
+		isChanged[p] = true
+	}
+	impacted := func(id nodeID) (yes bool) {
+		if isChanged[g.names[id]] {
+			return true
+		}
+		g.eachDep(id, func(dep nodeID) {
+			yes = yes || isChanged[g.names[dep]]
+		})
+		return
+	}
+	for id := range g.mainPackages {
+		if impacted(id) {
+			mains = append(mains, g.names[id])
+		}
+	}
+	for id := range g.testPackages {
+		if impacted(id) {
+			tests = append(tests, g.names[id])
+		}
+	}
+	sort.Strings(mains)
+	sort.Strings(tests)
+	return
+}
@@ -0,0 +1,56 @@
+package depgraph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImpacted(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "app/cmd/a", Name: "main", Imports: []string{"app/db"}, Deps: []string{"app/db", "app/log"}})
+	dg.Add(DepInfo{ImportPath: "app/cmd/b", Name: "main", Imports: []string{"app/log"}, Deps: []string{"app/log"}})
+	dg.Add(DepInfo{ImportPath: "app/db", Imports: []string{"app/log"}, Deps: []string{"app/log"}})
+	dg.Add(DepInfo{ImportPath: "app/log"})
+	dg.Add(DepInfo{ImportPath: "app/db.test", Name: "main",
+		Deps: []string{"app/db [app/db.test]", "app/log", "testing"}})
+	dg.Add(DepInfo{ImportPath: "app/log.test", Name: "main", Deps: []string{"app/log [app/log.test]", "testing"}})
+
+	for _, c := range []struct {
+		changed      []string
+		mains, tests string
+	}{
+		{[]string{"app/log"}, "app/cmd/a app/cmd/b", "app/db.test app/log.test"},
+		{[]string{"app/db"}, "app/cmd/a", "app/db.test"},
+		{[]string{"app/cmd/b", "testing"}, "app/cmd/b", "app/db.test app/log.test"},
+		{[]string{"fmt"}, "", ""},
+	} {
+		mains, tests := dg.Impacted(c.changed)
+		if strings.Join(mains, " ") != c.mains || strings.Join(tests, " ") != c.tests {
+			t.Error(c.changed, mains, tests)
+		}
+	}
+}
+
+func TestPackageAt(t *testing.T) {
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/depgraph", Dir: dir})
+	dg.Add(DepInfo{ImportPath: "example.com/depgraph.test", Name: "main", Dir: dir})
+	dg.Add(DepInfo{ImportPath: "example.com/testdata", Dir: filepath.Join(dir, "testdata")})
+	for path, expect := range map[string]string{
+		"impact.go":                      "example.com/depgraph",
+		"testdata":                       "example.com/testdata",
+		"testdata/gen.sh":                "example.com/testdata",
+		filepath.Join(dir, "deleted.go"): "example.com/depgraph",
+		"../server/web.go":               "",
+	} {
+		if p, _ := dg.PackageAt(path); p != expect {
+			t.Error(path, p)
+		}
+	}
+}
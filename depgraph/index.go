@@ -0,0 +1,393 @@
+package depgraph
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+)
+
+// The on-disk index stores a loaded graph in a form that can be memory
+// mapped and queried in place, without decoding it into the heap. All
+// integers are little-endian uint32. Nodes are numbered in import path
+// order so a path is found by binary search over the string table.
+//
+//	magic      "GDSIDX01"
+//	n          node count
+//	names      n+1 offsets into the string blob, blob length, blob
+//	flags      n bytes (flagLoaded|flagMain|flagTest), padded to 4
+//	imports    CSR: n+1 offsets, then targets
+//	importers  CSR
+//	deps       CSR
+//	dependents CSR
+//	build      optional: length, JSON of the BuildProfile, padded to 4
+
+const indexMagic = "GDSIDX01"
+
+const (
+	flagLoaded = 1 << iota
+	flagMain
+	flagTest
+)
+
+var ErrBadIndex = errors.New("depgraph: malformed index")
+
+// WriteIndex serializes g in the index format read by OpenIndex.
+func (g *DepGraph) WriteIndex(w io.Writer) error {
+	order := make([]nodeID, len(g.names))
+	for i := range order {
+		order[i] = nodeID(i)
+	}
+	sort.Slice(order, func(i, j int) bool { return g.names[order[i]] < g.names[order[j]] })
+	pos := make([]uint32, len(order))
+	for i, id := range order {
+		pos[id] = uint32(i)
+	}
+
+	bw := bufio.NewWriter(w)
+	var err error
+	put := func(v uint32) {
+		if err == nil {
+			err = binary.Write(bw, binary.LittleEndian, v)
+		}
+	}
+	write := func(b []byte) {
+		if err == nil {
+			_, err = bw.Write(b)
+		}
+	}
+	csr := func(list func(id nodeID) []nodeID) {
+		var off uint32
+		put(off)
+		for _, id := range order {
+			off += uint32(len(list(id)))
+			put(off)
+		}
+		for _, id := range order {
+			targets := make([]uint32, 0, len(list(id)))
+			for _, to := range list(id) {
+				targets = append(targets, pos[to])
+			}
+			sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+			for _, t := range targets {
+				put(t)
+			}
+		}
+	}
+
+	write([]byte(indexMagic))
+	put(uint32(len(order)))
+	var off uint32
+	put(off)
+	for _, id := range order {
+		off += uint32(len(g.names[id]))
+		put(off)
+	}
+	put(off)
+	for _, id := range order {
+		write([]byte(g.names[id]))
+	}
+	write(make([]byte, (4-off%4)%4))
+	flags := make([]byte, len(order)+(4-len(order)%4)%4)
+	for i, id := range order {
+		if g.loaded[id] {
+			flags[i] |= flagLoaded
+		}
+		if g.mainPackages[id] {
+			flags[i] |= flagMain
+		}
+		if g.testPackages[id] {
+			flags[i] |= flagTest
+		}
+	}
+	write(flags)
+	// like Imports and Importers, the index leaves test-only edges out
+	csr(func(id nodeID) []nodeID {
+		targets := make([]nodeID, 0, len(g.imports[id]))
+		for _, e := range g.imports[id] {
+			if e.inBuild() {
+				targets = append(targets, e.to)
+			}
+		}
+		return targets
+	})
+	csr(func(id nodeID) []nodeID {
+		importers := make([]nodeID, 0, len(g.importers[id]))
+		for _, from := range g.importers[id] {
+			if g.buildImports(from, id) {
+				importers = append(importers, from)
+			}
+		}
+		return importers
+	})
+	deps, dependents := g.deps, g.dependents
+	if g.ignoreDeps {
+		// the deps the graph answers with are the import closure
+		deps, dependents = make([][]nodeID, len(g.names)), make([][]nodeID, len(g.names))
+		for id, loaded := range g.loaded {
+			id := nodeID(id)
+			if !loaded {
+				continue
+			}
+			g.eachDep(id, func(dep nodeID) {
+				deps[id] = append(deps[id], dep)
+				dependents[dep] = append(dependents[dep], id)
+			})
+		}
+	}
+	csr(func(id nodeID) []nodeID { return deps[id] })
+	csr(func(id nodeID) []nodeID { return dependents[id] })
+	if g.build.String() != "" {
+		build, jsonErr := json.Marshal(g.build)
+		if err == nil {
+			err = jsonErr
+		}
+		put(uint32(len(build)))
+		write(build)
+		write(make([]byte, (4-len(build)%4)%4))
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Index is a read-only graph backed by the bytes of an index file,
+// usually memory mapped by OpenIndex. Queries decode only the entries they
+// touch.
+type Index struct {
+	data   []byte
+	n      int
+	names  []byte // n+1 offsets
+	blob   []byte
+	flags  []byte
+	tables [4]csrTable // imports, importers, deps, dependents
+	build  BuildProfile
+	close  func() error
+}
+
+type csrTable struct {
+	offsets []byte // n+1 uint32
+	targets []byte
+}
+
+const (
+	tableImports = iota
+	tableImporters
+	tableDeps
+	tableDependents
+)
+
+func u32(b []byte, i int) int {
+	return int(binary.LittleEndian.Uint32(b[4*i:]))
+}
+
+// ParseIndex wraps data, which must stay unchanged while the Index is in
+// use, without copying it. The offsets and targets are checked upfront,
+// so a corrupt index gives ErrBadIndex instead of panicking queries.
+func ParseIndex(data []byte) (*Index, error) {
+	if len(data) < len(indexMagic)+4 || string(data[:len(indexMagic)]) != indexMagic {
+		return nil, ErrBadIndex
+	}
+	ix := &Index{data: data}
+	rest := data[len(indexMagic):]
+	take := func(size int) []byte {
+		if size < 0 || size > len(rest) {
+			rest = nil
+			return nil
+		}
+		b := rest[:size]
+		rest = rest[size:]
+		return b
+	}
+	ix.n = u32(take(4), 0)
+	if ix.n > len(data) {
+		return nil, ErrBadIndex
+	}
+	if ix.names = take(4 * (ix.n + 1)); ix.names == nil {
+		return nil, ErrBadIndex
+	}
+	length := take(4)
+	if length == nil {
+		return nil, ErrBadIndex
+	}
+	blobLen := u32(length, 0)
+	if ix.blob = take(blobLen); ix.blob == nil && blobLen > 0 {
+		return nil, ErrBadIndex
+	}
+	take((4 - blobLen%4) % 4)
+	if ix.flags = take(ix.n + (4-ix.n%4)%4); ix.flags == nil && ix.n > 0 {
+		return nil, ErrBadIndex
+	}
+	for i := range ix.tables {
+		offsets := take(4 * (ix.n + 1))
+		if offsets == nil {
+			return nil, ErrBadIndex
+		}
+		targets := take(4 * u32(offsets, ix.n))
+		if targets == nil && u32(offsets, ix.n) > 0 {
+			return nil, ErrBadIndex
+		}
+		ix.tables[i] = csrTable{offsets: offsets, targets: targets}
+	}
+	if !monotonic(ix.names, ix.n, len(ix.blob)) || u32(ix.names, ix.n) != len(ix.blob) {
+		return nil, ErrBadIndex
+	}
+	for _, t := range ix.tables {
+		if !monotonic(t.offsets, ix.n, len(t.targets)/4) {
+			return nil, ErrBadIndex
+		}
+		for j := 0; j < len(t.targets)/4; j++ {
+			if u32(t.targets, j) >= ix.n {
+				return nil, ErrBadIndex
+			}
+		}
+	}
+	// indexes written without build profile end here
+	if length := take(4); length != nil {
+		build := take(u32(length, 0))
+		if build == nil || json.Unmarshal(build, &ix.build) != nil {
+			return nil, ErrBadIndex
+		}
+	}
+	return ix, nil
+}
+
+// monotonic reports whether the n+1 offsets never decrease and stay
+// within limit, so every range they delimit can be sliced.
+func monotonic(offsets []byte, n, limit int) bool {
+	prev := 0
+	for i := 0; i <= n; i++ {
+		off := u32(offsets, i)
+		if off < prev || off > limit {
+			return false
+		}
+		prev = off
+	}
+	return true
+}
+
+// Build returns the build profile of the graph the index was written
+// from.
+func (ix *Index) Build() BuildProfile {
+	return ix.build
+}
+
+// Close releases the mapping created by OpenIndex.
+func (ix *Index) Close() error {
+	if ix.close == nil {
+		return nil
+	}
+	return ix.close()
+}
+
+func (ix *Index) name(i int) string {
+	return string(ix.blob[u32(ix.names, i):u32(ix.names, i+1)])
+}
+
+func (ix *Index) lookup(pkg string) (int, bool) {
+	i := sort.Search(ix.n, func(i int) bool { return ix.name(i) >= pkg })
+	return i, i < ix.n && ix.name(i) == pkg
+}
+
+func (ix *Index) list(table, i int) []string {
+	t := ix.tables[table]
+	var packages []string
+	for j := u32(t.offsets, i); j < u32(t.offsets, i+1); j++ {
+		packages = append(packages, ix.name(u32(t.targets, j)))
+	}
+	return packages
+}
+
+func (ix *Index) contains(table, i, target int) bool {
+	t := ix.tables[table]
+	lo, hi := u32(t.offsets, i), u32(t.offsets, i+1)
+	j := lo + sort.Search(hi-lo, func(j int) bool { return u32(t.targets, lo+j) >= target })
+	return j < hi && u32(t.targets, j) == target
+}
+
+var _ Graph = (*Index)(nil)
+
+// AddEdge panics: an Index is read-only.
+func (ix *Index) AddEdge(from, to string) {
+	panic("depgraph: Index is read-only")
+}
+
+func (ix *Index) Imports(pkg string) []string {
+	if i, ok := ix.lookup(pkg); ok {
+		return ix.list(tableImports, i)
+	}
+	return nil
+}
+
+func (ix *Index) Importers(pkg string) (packages []string) {
+	i, ok := ix.lookup(pkg)
+	if !ok {
+		return
+	}
+	for _, p := range ix.list(tableImporters, i) {
+		if p != "main" {
+			packages = append(packages, p)
+		}
+	}
+	return
+}
+
+func (ix *Index) Has(pkg string) bool {
+	i, ok := ix.lookup(pkg)
+	return ok && ix.flags[i]&flagLoaded != 0
+}
+
+func (ix *Index) CountAll() int {
+	return ix.n
+}
+
+func (ix *Index) IsMainPackage(pkg string) bool {
+	i, ok := ix.lookup(pkg)
+	return ok && ix.flags[i]&flagMain != 0
+}
+
+func (ix *Index) IsTestPackage(pkg string) bool {
+	i, ok := ix.lookup(pkg)
+	return ok && ix.flags[i]&flagTest != 0
+}
+
+// SearchAll is DepGraph.SearchAll answered from the index.
+func (ix *Index) SearchAll(packageName string) []string {
+	if i, ok := ix.lookup(packageName); ok {
+		return ix.list(tableDependents, i)
+	}
+	return nil
+}
+
+// SearchMain is DepGraph.SearchMain answered from the index, sorted. Only
+// the names of the main packages are decoded.
+func (ix *Index) SearchMain(packageName string) (packages []string) {
+	i, ok := ix.lookup(packageName)
+	if !ok {
+		return
+	}
+	if ix.flags[i]&flagMain != 0 {
+		packages = append(packages, packageName)
+	}
+	t := ix.tables[tableDependents]
+	for j := u32(t.offsets, i); j < u32(t.offsets, i+1); j++ {
+		if p := u32(t.targets, j); ix.flags[p]&flagMain != 0 {
+			packages = append(packages, ix.name(p))
+		}
+	}
+	sort.Strings(packages)
+	return
+}
+
+// DependsOn reports whether packageName is in the Deps of from.
+func (ix *Index) DependsOn(from, packageName string) bool {
+	i, ok := ix.lookup(from)
+	if !ok {
+		return false
+	}
+	target, ok := ix.lookup(packageName)
+	return ok && ix.contains(tableDeps, i, target)
+}
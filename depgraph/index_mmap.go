@@ -0,0 +1,36 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package depgraph
+
+import (
+	"os"
+	"syscall"
+)
+
+// OpenIndex memory maps the index file at path. The Index must be closed
+// to release the mapping.
+func OpenIndex(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() == 0 {
+		return nil, ErrBadIndex
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	ix, err := ParseIndex(data)
+	if err != nil {
+		syscall.Munmap(data)
+		return nil, err
+	}
+	ix.close = func() error { return syscall.Munmap(data) }
+	return ix, nil
+}
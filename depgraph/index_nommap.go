@@ -0,0 +1,15 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package depgraph
+
+import "io/ioutil"
+
+// OpenIndex reads the index file at path into memory on platforms without
+// mmap support.
+func OpenIndex(path string) (*Index, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIndex(data)
+}
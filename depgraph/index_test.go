@@ -0,0 +1,178 @@
+package depgraph
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	dg := loadTestGraph(t)
+	dir, err := ioutil.TempDir("", "depgraph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "deps.idx")
+	f, err := os.Create(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = dg.WriteIndex(f); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	ix, err := OpenIndex(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ix.Close()
+	if !ix.Has("fmt") || ix.Has("fmtxxxxxxx") || !ix.IsMainPackage("cmd/vet") || ix.IsMainPackage("fmt") {
+		t.Error("flags error")
+	}
+	for _, p := range []string{"net/http", "fmt", "cmd/vet"} {
+		if strings.Join(ix.Imports(p), " ") != strings.Join(dg.Imports(p), " ") {
+			t.Error(p, ix.Imports(p), dg.Imports(p))
+		}
+		if strings.Join(ix.Importers(p), " ") != strings.Join(dg.Importers(p), " ") {
+			t.Error(p, ix.Importers(p), dg.Importers(p))
+		}
+	}
+	all := dg.SearchAll("net/url")
+	sort.Strings(all)
+	if strings.Join(ix.SearchAll("net/url"), " ") != strings.Join(all, " ") {
+		t.Error(ix.SearchAll("net/url"), all)
+	}
+	if !ix.DependsOn("net/http", "net/url") || ix.DependsOn("fmt", "net/url") {
+		t.Error("DependsOn error")
+	}
+	if len(FindGraph(ix, "net/http", "net")) != len(dg.SearchGraph("net/http", "net")) {
+		t.Error("FindGraph on Index differs")
+	}
+
+	var buf bytes.Buffer
+	dg.WriteIndex(&buf)
+	if _, err := ParseIndex(buf.Bytes()[:buf.Len()/2]); err != ErrBadIndex {
+		t.Error("truncated index should be rejected", err)
+	}
+}
+
+func TestIndexBuild(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Standard: true})
+	var buf bytes.Buffer
+	dg.WriteIndex(&buf)
+	ix, err := ParseIndex(buf.Bytes())
+	if err != nil || ix.Build().String() != "" {
+		t.Fatal(err, ix.Build())
+	}
+
+	build := BuildProfile{GOOS: "windows", Tags: []string{"netgo", "osusergo"}, GOFLAGS: "-mod=vendor"}
+	dg.SetBuild(build)
+	if got := dg.Freeze().Build().String(); got != "GOOS=windows -tags=netgo,osusergo GOFLAGS=-mod=vendor" {
+		t.Error(got)
+	}
+	buf.Reset()
+	dg.WriteIndex(&buf)
+	if ix, err = ParseIndex(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if ix.Build().String() != build.String() || !ix.DependsOn("cmd/a", "fmt") {
+		t.Error(ix.Build())
+	}
+	if _, err := ParseIndex(buf.Bytes()[:buf.Len()-8]); err != ErrBadIndex {
+		t.Error("truncated build profile should be rejected", err)
+	}
+}
+
+func TestIndexCorrupt(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"net/url"}, Deps: []string{"fmt", "net/url"}})
+	dg.Add(DepInfo{ImportPath: "net/url", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "fmt"})
+	dg.SetBuild(BuildProfile{GOOS: "linux"})
+	var buf bytes.Buffer
+	dg.WriteIndex(&buf)
+	data := buf.Bytes()
+
+	// the second name offset, past the magic, n and the first offset
+	corrupt := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(corrupt[len(indexMagic)+8:], 1000)
+	if _, err := ParseIndex(corrupt); err != ErrBadIndex {
+		t.Error("out of range name offset should be rejected", err)
+	}
+	// whatever word is broken, the index is rejected or queried safely
+	for i := len(indexMagic); i+4 <= len(data); i += 4 {
+		for _, v := range []uint32{0, 1, 3, 1 << 20, 1<<32 - 1} {
+			corrupt := append([]byte(nil), data...)
+			binary.LittleEndian.PutUint32(corrupt[i:], v)
+			ix, err := ParseIndex(corrupt)
+			if err != nil {
+				continue
+			}
+			for _, p := range []string{"cmd/a", "net/url", "fmt", "os"} {
+				ix.Has(p)
+				ix.Imports(p)
+				ix.Importers(p)
+				ix.SearchAll(p)
+				ix.SearchMain(p)
+				ix.DependsOn("cmd/a", p)
+				FindChain(ix, "cmd/a", p)
+			}
+		}
+	}
+}
+
+func TestIndexSearchMain(t *testing.T) {
+	dg := loadTestGraph(t)
+	var buf bytes.Buffer
+	dg.WriteIndex(&buf)
+	ix, err := ParseIndex(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"net/url", "cmd/vet", "fmt"} {
+		mains := dg.SearchMain(p)
+		sort.Strings(mains)
+		if got := ix.SearchMain(p); strings.Join(got, " ") != strings.Join(mains, " ") {
+			t.Error(p, got, mains)
+		}
+	}
+}
+
+func TestIndexIgnoreDeps(t *testing.T) {
+	dg := &DepGraph{}
+	dg.IgnoreDeps(true)
+	// a partial dump: no Deps, the closure of the imports stands for them
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"lib"}})
+	dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Name: "fmt"})
+	var buf bytes.Buffer
+	if err := dg.WriteIndex(&buf); err != nil {
+		t.Fatal(err)
+	}
+	ix, err := ParseIndex(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"cmd/a", "lib", "fmt"} {
+		all := dg.SearchAll(p)
+		sort.Strings(all)
+		if got := ix.SearchAll(p); strings.Join(got, " ") != strings.Join(all, " ") {
+			t.Error(p, got, all)
+		}
+		if got := ix.SearchMain(p); strings.Join(got, " ") != strings.Join(dg.SearchMain(p), " ") {
+			t.Error(p, got, dg.SearchMain(p))
+		}
+	}
+	if !ix.DependsOn("cmd/a", "fmt") || ix.DependsOn("fmt", "lib") {
+		t.Error("DependsOn error")
+	}
+}
@@ -0,0 +1,93 @@
+package depgraph
+
+import "sort"
+
+// nodeID is the interned form of an import path. Every path is stored
+// once in DepGraph.names and all adjacency data refers to it by ID, which
+// keeps dumps with tens of thousands of packages from duplicating the same
+// strings in every import and dependency set.
+type nodeID int32
+
+type edge struct {
+	to    nodeID
+	attrs *EdgeAttrs
+}
+
+func (g *DepGraph) intern(packageName string) nodeID {
+	if id, ok := g.ids[packageName]; ok {
+		return id
+	}
+	if g.ids == nil {
+		g.ids = make(map[string]nodeID)
+	}
+	id := nodeID(len(g.names))
+	g.ids[packageName] = id
+	g.names = append(g.names, packageName)
+	g.imports = append(g.imports, nil)
+	g.deps = append(g.deps, nil)
+	g.importers = append(g.importers, nil)
+	g.dependents = append(g.dependents, nil)
+	g.loaded = append(g.loaded, false)
+	return id
+}
+
+func (g *DepGraph) lookup(packageName string) (nodeID, bool) {
+	id, ok := g.ids[packageName]
+	if !ok && len(g.replaces) > 0 {
+		if old, replaced := g.unreplace(packageName); replaced {
+			id, ok = g.ids[old]
+		}
+	}
+	return id, ok
+}
+
+func (g *DepGraph) internAll(packages []string) []nodeID {
+	ids := make([]nodeID, len(packages))
+	for i, p := range packages {
+		ids[i] = g.intern(p)
+	}
+	sortIDs(ids)
+	return ids
+}
+
+func sortIDs(ids []nodeID) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
+
+// dependsOn reports whether to is in the Deps of from, or in its import
+// closure if the graph ignores Deps.
+func (g *DepGraph) dependsOn(from, to nodeID) bool {
+	if g.ignoreDeps {
+		return g.closure()[from].has(to)
+	}
+	deps := g.deps[from]
+	i := sort.Search(len(deps), func(i int) bool { return deps[i] >= to })
+	return i < len(deps) && deps[i] == to
+}
+
+// dependsOnName is dependsOn for import paths.
+func (g *DepGraph) dependsOnName(from, to string) bool {
+	fromID, ok := g.lookup(from)
+	if !ok {
+		return false
+	}
+	toID, ok := g.lookup(to)
+	return ok && g.dependsOn(fromID, toID)
+}
+
+func (g *DepGraph) edgeTo(from, to nodeID) *EdgeAttrs {
+	for _, e := range g.imports[from] {
+		if e.to == to {
+			return e.attrs
+		}
+	}
+	return nil
+}
+
+func (g *DepGraph) pathsOf(ids []nodeID) []string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = g.names[id]
+	}
+	return names
+}
@@ -0,0 +1,25 @@
+package depgraph
+
+import "testing"
+
+func TestIntern(t *testing.T) {
+	dg := loadTestGraph(t)
+	for id, p := range dg.names {
+		if dg.ids[p] != nodeID(id) {
+			t.Error("intern table inconsistent", p, id, dg.ids[p])
+		}
+	}
+	n := len(dg.names)
+	dg.Add(DepInfo{
+		ImportPath: "fmt",
+		Name:       "fmt",
+		Deps:       []string{"io", "os"},
+		Imports:    []string{"io", "os"},
+	})
+	if len(dg.names) != n {
+		t.Error("known paths should not be interned twice", n, len(dg.names))
+	}
+	if !dg.dependsOnName("fmt", "io") || dg.dependsOnName("fmt", "unicode/utf8") {
+		t.Error("deps of fmt should be replaced")
+	}
+}
@@ -0,0 +1,163 @@
+package depgraph
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LoadError describes a record LoadLenient skipped.
+type LoadError struct {
+	Offset int64 // byte offset of the record in the input
+	Line   int   // line of the record in the input, starting at 1
+	Err    error
+}
+
+func (e LoadError) Error() string {
+	return fmt.Sprintf("offset %d (line %d): %v", e.Offset, e.Line, e.Err)
+}
+
+var (
+	errTruncated = errors.New("truncated record")
+	errGarbage   = errors.New("data outside of a record")
+)
+
+// LoadLenient is Load for damaged dumps: records that fail to decode are
+// skipped and returned instead of aborting the load. It relies on the
+// layout go list -json writes, where records start with a "{" line and end
+// with a "}" line, and also accepts one record per line. Records going
+// over the limits set by SetLimits are skipped with a *LimitError, except
+// for MaxPackages which stops the load. Records and lines are never
+// buffered past MaxRecordSize, DefaultLimits.MaxRecordSize if it is not
+// set, so a dump missing its newlines or closing lines is skipped with a
+// *LimitError too. err is only set if reading r fails or MaxPackages is
+// reached.
+func (g *DepGraph) LoadLenient(r io.Reader) (skipped []LoadError, err error) {
+	br := bufio.NewReader(r)
+	max := g.limits.MaxRecordSize
+	if max <= 0 {
+		max = DefaultLimits.MaxRecordSize
+	}
+	tooLarge := &LimitError{Limit: "MaxRecordSize", Max: max}
+	var (
+		record   []byte
+		inRecord bool
+		dropped  bool      // the current record went over max
+		start    LoadError // position of the current record
+		offset   int64
+		line     int
+		packages = g.loadedCount()
+		full     error // MaxPackages reached
+	)
+	decode := func(data []byte, pos LoadError) {
+		var di DepInfo
+		if err := json.Unmarshal(data, &di); err != nil {
+			pos.Err = err
+			skipped = append(skipped, pos)
+			return
+		}
+		if err := g.checkLimits(&di, packages); err != nil {
+			if err.(*LimitError).Limit == "MaxPackages" {
+				full = err
+				return
+			}
+			pos.Err = err
+			skipped = append(skipped, pos)
+			return
+		}
+		if !g.Exists(di.ImportPath) {
+			packages++
+		}
+		g.Add(di)
+	}
+	for {
+		text, n, readErr := readLine(br, max)
+		if readErr != nil && readErr != io.EOF {
+			return skipped, readErr
+		}
+		if n > 0 {
+			line++
+			pos := LoadError{Offset: offset, Line: line}
+			offset += n
+			long := n > int64(len(text))
+			trimmed := strings.TrimRight(text, "\r\n")
+			switch {
+			case strings.HasPrefix(trimmed, "{") && inRecord:
+				// the previous record never got its closing line
+				start.Err = errTruncated
+				if dropped {
+					start.Err = tooLarge
+				}
+				skipped = append(skipped, start)
+				fallthrough
+			case strings.HasPrefix(trimmed, "{"):
+				switch {
+				case strings.TrimSpace(trimmed) == "{":
+					record, inRecord, dropped, start = []byte(text), true, false, pos
+				case long:
+					inRecord = false
+					pos.Err = tooLarge
+					skipped = append(skipped, pos)
+				default:
+					inRecord = false
+					decode([]byte(text), pos)
+				}
+			case inRecord:
+				if dropped = dropped || long || int64(len(record)+len(text)) > max; dropped {
+					record = nil
+				} else {
+					record = append(record, text...)
+				}
+				if trimmed == "}" {
+					inRecord = false
+					if dropped {
+						start.Err = tooLarge
+						skipped = append(skipped, start)
+					} else {
+						decode(record, start)
+					}
+				}
+			case strings.TrimSpace(trimmed) != "":
+				pos.Err = errGarbage
+				skipped = append(skipped, pos)
+			}
+		}
+		if full != nil {
+			return skipped, full
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+	if inRecord {
+		start.Err = errTruncated
+		if dropped {
+			start.Err = tooLarge
+		}
+		skipped = append(skipped, start)
+	}
+	return skipped, nil
+}
+
+// readLine reads the next line of br, returning at most max bytes of it
+// and its full length n, so a line missing its newline is never buffered
+// whole.
+func readLine(br *bufio.Reader, max int64) (text string, n int64, err error) {
+	var buf []byte
+	for {
+		chunk, err := br.ReadSlice('\n')
+		n += int64(len(chunk))
+		if room := max - int64(len(buf)); room > 0 {
+			if int64(len(chunk)) > room {
+				chunk = chunk[:room]
+			}
+			buf = append(buf, chunk...)
+		}
+		if err != bufio.ErrBufferFull {
+			return string(buf), n, err
+		}
+	}
+}
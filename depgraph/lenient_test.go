@@ -0,0 +1,100 @@
+package depgraph
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadLenient(t *testing.T) {
+	input := `{
+	"ImportPath": "cmd/a",
+	"Name": "main",
+	"Imports": ["lib"],
+	"Deps": ["lib"]
+}
+{
+	"ImportPath": "broken",
+	"Name": 
+}
+{"ImportPath": "lib", "Name": "lib", "Imports": ["fmt"], "Deps": ["fmt"]}
+garbage
+{"ImportPath": "bad", "Deps": [1]}
+{
+	"ImportPath": "truncated",
+{
+	"ImportPath": "fmt",
+	"Name": "fmt"
+}
+{
+	"ImportPath": "eof",
+`
+	dg := &DepGraph{}
+	skipped, err := dg.LoadLenient(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range skipped {
+		got = append(got, e.Error())
+	}
+	if len(got) != 5 || !strings.HasPrefix(got[0], "offset 83 (line 7): ") ||
+		got[1] != "offset 196 (line 12): data outside of a record" ||
+		!strings.HasPrefix(got[2], "offset 204 (line 13): ") ||
+		got[3] != "offset 239 (line 14): truncated record" ||
+		got[4] != "offset 310 (line 20): truncated record" {
+		t.Error(strings.Join(got, "\n"))
+	}
+	for _, p := range []string{"cmd/a", "lib", "fmt"} {
+		if !dg.Exists(p) {
+			t.Error("missing", p)
+		}
+	}
+	if len(dg.Packages()) != 3 {
+		t.Error(dg.Packages())
+	}
+
+	f, err := os.Open("testdata/go1.12.5_deps.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	full := &DepGraph{}
+	if skipped, err := full.LoadLenient(f); err != nil || len(skipped) != 0 || full.CountAll() != 371 {
+		t.Error("intact dump", skipped, err, full.CountAll())
+	}
+}
+
+func TestLoadLenientRecordSize(t *testing.T) {
+	long := strings.Repeat("x", 8192)
+	input := `{
+	"ImportPath": "cmd/a",
+	"Name": "main",
+	"Doc": "` + long + `"
+}
+{"ImportPath": "` + long + `"}
+{
+	"ImportPath": "never closed",
+	"Doc": "` + long + `"
+{"ImportPath": "fmt", "Name": "fmt"}
+{"ImportPath": "no newline", "Doc": "` + long + `"}`
+	dg := &DepGraph{}
+	dg.SetLimits(Limits{MaxRecordSize: 1024})
+	skipped, err := dg.LoadLenient(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []int
+	for _, e := range skipped {
+		if le, ok := e.Err.(*LimitError); !ok || le.Limit != "MaxRecordSize" {
+			t.Error("expect MaxRecordSize", e)
+		}
+		lines = append(lines, e.Line)
+	}
+	if len(lines) != 4 || lines[0] != 1 || lines[1] != 6 || lines[2] != 7 || lines[3] != 11 {
+		t.Error("skipped error", skipped)
+	}
+	if !dg.Exists("fmt") || len(dg.Packages()) != 1 {
+		t.Error(dg.Packages())
+	}
+}
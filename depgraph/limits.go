@@ -0,0 +1,106 @@
+package depgraph
+
+import (
+	"fmt"
+	"io"
+)
+
+// Limits bounds what Load accepts, so a hostile or corrupted input can't
+// exhaust memory, eg: in server mode. Zero fields mean no limit.
+type Limits struct {
+	MaxPackages   int   // packages in the graph
+	MaxPathLen    int   // bytes of an import path
+	MaxImports    int   // Imports of a package
+	MaxDeps       int   // Deps of a package
+	MaxRecordSize int64 // bytes of a JSON record of the input
+}
+
+// DefaultLimits fit the largest real-world graphs with room to spare, for
+// servers loading graphs they don't trust.
+var DefaultLimits = Limits{
+	MaxPackages:   500000,
+	MaxPathLen:    1024,
+	MaxImports:    10000,
+	MaxDeps:       500000,
+	MaxRecordSize: 64 << 20,
+}
+
+// LimitError is returned by Load for input going over one of the Limits.
+type LimitError struct {
+	Limit   string // name of the field of Limits, eg: "MaxImports"
+	Max     int64
+	Package string // offending package, empty if unknown
+}
+
+func (e *LimitError) Error() string {
+	if e.Package == "" {
+		return fmt.Sprintf("input exceeds %s of %d", e.Limit, e.Max)
+	}
+	return fmt.Sprintf("package %.100q exceeds %s of %d", e.Package, e.Limit, e.Max)
+}
+
+// SetLimits bounds the input of the next Load and LoadLenient calls.
+func (g *DepGraph) SetLimits(l Limits) {
+	g.limits = l
+}
+
+// checkLimits returns a *LimitError if adding di goes over the limits of
+// g, packages is the number of packages already loaded.
+func (g *DepGraph) checkLimits(di *DepInfo, packages int) error {
+	l := g.limits
+	if l.MaxPathLen > 0 {
+		if len(di.ImportPath) > l.MaxPathLen {
+			return &LimitError{Limit: "MaxPathLen", Max: int64(l.MaxPathLen), Package: di.ImportPath}
+		}
+		for _, paths := range [][]string{di.Imports, di.Deps} {
+			for _, p := range paths {
+				if len(p) > l.MaxPathLen {
+					return &LimitError{Limit: "MaxPathLen", Max: int64(l.MaxPathLen), Package: di.ImportPath}
+				}
+			}
+		}
+	}
+	if l.MaxImports > 0 && len(di.Imports) > l.MaxImports {
+		return &LimitError{Limit: "MaxImports", Max: int64(l.MaxImports), Package: di.ImportPath}
+	}
+	if l.MaxDeps > 0 && len(di.Deps) > l.MaxDeps {
+		return &LimitError{Limit: "MaxDeps", Max: int64(l.MaxDeps), Package: di.ImportPath}
+	}
+	if l.MaxPackages > 0 && packages >= l.MaxPackages && !g.Exists(di.ImportPath) {
+		return &LimitError{Limit: "MaxPackages", Max: int64(l.MaxPackages), Package: di.ImportPath}
+	}
+	return nil
+}
+
+// loadedCount returns the number of packages added to g.
+func (g *DepGraph) loadedCount() (n int) {
+	for _, loaded := range g.loaded {
+		if loaded {
+			n++
+		}
+	}
+	return
+}
+
+// recordReader fails reads past limit, which Load moves to MaxRecordSize
+// bytes after the start of every record, so the decoder never buffers a
+// larger record.
+type recordReader struct {
+	r     io.Reader
+	read  int64
+	limit int64
+}
+
+var errRecordTooLarge = &LimitError{Limit: "MaxRecordSize"}
+
+func (r *recordReader) Read(p []byte) (int, error) {
+	if r.read >= r.limit {
+		return 0, errRecordTooLarge
+	}
+	if max := r.limit - r.read; int64(len(p)) > max {
+		p = p[:max]
+	}
+	n, err := r.r.Read(p)
+	r.read += int64(n)
+	return n, err
+}
@@ -0,0 +1,68 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadLimits(t *testing.T) {
+	input := `{"ImportPath": "cmd/a", "Name": "main", "Imports": ["lib", "fmt"], "Deps": ["fmt", "lib"]}
+{"ImportPath": "lib", "Imports": ["fmt"], "Deps": ["fmt"]}
+{"ImportPath": "fmt", "Standard": true}
+`
+	for _, tc := range []struct {
+		limits Limits
+		want   string // Limit of the error, empty for none
+	}{
+		{Limits{}, ""},
+		{DefaultLimits, ""},
+		{Limits{MaxPackages: 3, MaxPathLen: 5, MaxImports: 2, MaxDeps: 2, MaxRecordSize: 100}, ""},
+		{Limits{MaxPackages: 2}, "MaxPackages"},
+		{Limits{MaxPathLen: 4}, "MaxPathLen"},
+		{Limits{MaxImports: 1}, "MaxImports"},
+		{Limits{MaxDeps: 1}, "MaxDeps"},
+		{Limits{MaxRecordSize: 60}, "MaxRecordSize"},
+	} {
+		dg := &DepGraph{}
+		dg.SetLimits(tc.limits)
+		err := dg.Load(strings.NewReader(input))
+		if tc.want == "" {
+			if err != nil || len(dg.Packages()) != 3 {
+				t.Error(tc.limits, err, dg.Packages())
+			}
+			continue
+		}
+		if e, ok := err.(*LimitError); !ok || e.Limit != tc.want {
+			t.Errorf("%+v: %v, want %v", tc.limits, err, tc.want)
+		}
+	}
+
+	// the same package listed twice doesn't count twice
+	dg := &DepGraph{}
+	dg.SetLimits(Limits{MaxPackages: 3})
+	if err := dg.Load(strings.NewReader(input + input)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoadLenientLimits(t *testing.T) {
+	input := `{"ImportPath": "cmd/a", "Name": "main", "Imports": ["lib", "fmt"], "Deps": ["fmt", "lib"]}
+{"ImportPath": "lib", "Imports": ["fmt"], "Deps": ["fmt"]}
+{"ImportPath": "fmt", "Standard": true}
+`
+	dg := &DepGraph{}
+	dg.SetLimits(Limits{MaxImports: 1})
+	skipped, err := dg.LoadLenient(strings.NewReader(input))
+	if err != nil || len(skipped) != 1 || skipped[0].Line != 1 || len(dg.Packages()) != 2 {
+		t.Fatal(skipped, err, dg.Packages())
+	}
+	if e, ok := skipped[0].Err.(*LimitError); !ok || e.Package != "cmd/a" {
+		t.Error(skipped[0].Err)
+	}
+
+	dg = &DepGraph{}
+	dg.SetLimits(Limits{MaxPackages: 2})
+	if _, err := dg.LoadLenient(strings.NewReader(input)); err == nil || len(dg.Packages()) != 2 {
+		t.Error(err, dg.Packages())
+	}
+}
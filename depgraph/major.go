@@ -0,0 +1,90 @@
+package depgraph
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MajorConflict is a main package depending on more than one major
+// version of a module, eg: github.com/foo/bar and github.com/foo/bar/v2.
+// Both end up in the binary, bloating it, and values of one aren't
+// interchangeable with the other.
+type MajorConflict struct {
+	Main   string
+	Module string              // module path without the major version suffix
+	Chains map[string][]string // module path -> main -> ... -> first package of it
+}
+
+// majorBase returns modulePath without its major version suffix, eg:
+// github.com/foo/bar for github.com/foo/bar/v2 and gopkg.in/yaml for
+// gopkg.in/yaml.v3.
+func majorBase(modulePath string) string {
+	if strings.HasPrefix(modulePath, "gopkg.in/") {
+		if i := strings.LastIndex(modulePath, ".v"); i >= 0 && isNumber(modulePath[i+2:]) {
+			return modulePath[:i]
+		}
+		return modulePath
+	}
+	i := strings.LastIndex(modulePath, "/v")
+	if i < 0 {
+		return modulePath
+	}
+	if n, err := strconv.Atoi(modulePath[i+2:]); err == nil && n >= 2 && isNumber(modulePath[i+2:]) {
+		return modulePath[:i]
+	}
+	return modulePath
+}
+
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MajorConflicts returns the main packages depending on several major
+// versions of one module, with the chain pulling in each, sorted by main
+// package and module. It needs the module information of module mode.
+func (g *DepGraph) MajorConflicts() (conflicts []MajorConflict) {
+	majors := make(map[string]map[string]map[nodeID]bool) // base -> module path -> packages
+	for id, m := range g.modules {
+		base := majorBase(m.Path)
+		if majors[base] == nil {
+			majors[base] = make(map[string]map[nodeID]bool)
+		}
+		if majors[base][m.Path] == nil {
+			majors[base][m.Path] = make(map[nodeID]bool)
+		}
+		majors[base][m.Path][id] = true
+	}
+	g.prepare()
+	for base, paths := range majors {
+		if len(paths) < 2 {
+			continue
+		}
+		for p := range g.mainPackages {
+			chains := make(map[string][]string)
+			for path, inModule := range paths {
+				if chain := g.moduleChain(p, inModule); chain != nil {
+					chains[path] = append([]string{"main"}, chain...)
+				}
+			}
+			if len(chains) > 1 {
+				conflicts = append(conflicts, MajorConflict{Main: g.names[p], Module: base, Chains: chains})
+			}
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool {
+		if conflicts[i].Main != conflicts[j].Main {
+			return conflicts[i].Main < conflicts[j].Main
+		}
+		return conflicts[i].Module < conflicts[j].Module
+	})
+	return
+}
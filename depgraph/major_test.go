@@ -0,0 +1,44 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMajorConflicts(t *testing.T) {
+	for path, base := range map[string]string{
+		"github.com/foo/bar":    "github.com/foo/bar",
+		"github.com/foo/bar/v2": "github.com/foo/bar",
+		"github.com/foo/bar/v1": "github.com/foo/bar/v1",
+		"github.com/foo/v2x":    "github.com/foo/v2x",
+		"gopkg.in/yaml.v3":      "gopkg.in/yaml",
+	} {
+		if got := majorBase(path); got != base {
+			t.Error(path, got)
+		}
+	}
+	v1 := &Module{Path: "github.com/foo/bar", Version: "v1.5.0"}
+	v2 := &Module{Path: "github.com/foo/bar/v2", Version: "v2.1.0"}
+	app := &Module{Path: "example.com/app", Main: true}
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Module: app,
+		Imports: []string{"github.com/foo/bar/v2/x", "example.com/app/old"},
+		Deps:    []string{"github.com/foo/bar/v2/x", "example.com/app/old", "github.com/foo/bar/x"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/b", Name: "main", Module: app,
+		Imports: []string{"github.com/foo/bar/v2/x"}, Deps: []string{"github.com/foo/bar/v2/x"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/old", Name: "old", Module: app,
+		Imports: []string{"github.com/foo/bar/x"}, Deps: []string{"github.com/foo/bar/x"}})
+	dg.Add(DepInfo{ImportPath: "github.com/foo/bar/x", Name: "x", Module: v1})
+	dg.Add(DepInfo{ImportPath: "github.com/foo/bar/v2/x", Name: "x", Module: v2})
+	expect := []MajorConflict{{
+		Main:   "example.com/app/cmd/a",
+		Module: "github.com/foo/bar",
+		Chains: map[string][]string{
+			"github.com/foo/bar":    {"main", "example.com/app/cmd/a", "example.com/app/old", "github.com/foo/bar/x"},
+			"github.com/foo/bar/v2": {"main", "example.com/app/cmd/a", "github.com/foo/bar/v2/x"},
+		},
+	}}
+	if got := dg.MajorConflicts(); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+}
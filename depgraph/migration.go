@@ -0,0 +1,81 @@
+package depgraph
+
+import (
+	"sort"
+	"strings"
+)
+
+// Migration is a move of import path, eg: from github.com/dgrijalva/jwt-go
+// to github.com/golang-jwt/jwt, the packages at or below Old being
+// replaced by those at or below New.
+type Migration struct {
+	Old, New string
+}
+
+// side tells whether packageName is at or below m.Old, -1, or m.New, 1,
+// the longer path winning when one is below the other, eg: foo to foo/v2.
+func (m Migration) side(packageName string) int {
+	under := func(prefix string) bool {
+		return packageName == prefix || strings.HasPrefix(packageName, prefix+"/")
+	}
+	isOld, isNew := under(m.Old), under(m.New)
+	switch {
+	case isOld && isNew && len(m.Old) > len(m.New), isOld && !isNew:
+		return -1
+	case isNew:
+		return 1
+	}
+	return 0
+}
+
+// MigrationStatus is the side of a Migration a main package is on.
+type MigrationStatus struct {
+	Main     string
+	OldChain []string // main -> ... -> first package of the old path, nil if it has none
+	NewChain []string // main -> ... -> first package of the new path, nil if it has none
+}
+
+// State returns "old" for a main package still on the old path only,
+// "new" for a migrated one and "both" for one including both.
+func (s MigrationStatus) State() string {
+	switch {
+	case s.OldChain != nil && s.NewChain != nil:
+		return "both"
+	case s.OldChain != nil:
+		return "old"
+	}
+	return "new"
+}
+
+// MigrationStatuses returns the main packages depending on a package of
+// either path of m, with the chain to each, sorted by main package.
+func (g *DepGraph) MigrationStatuses(m Migration) []MigrationStatus {
+	inOld, inNew := make(map[nodeID]bool), make(map[nodeID]bool)
+	for id, name := range g.names {
+		switch m.side(name) {
+		case -1:
+			inOld[nodeID(id)] = true
+		case 1:
+			inNew[nodeID(id)] = true
+		}
+	}
+	statuses := make([]MigrationStatus, 0)
+	if len(inOld) == 0 && len(inNew) == 0 {
+		return statuses
+	}
+	g.prepare()
+	for p := range g.mainPackages {
+		s := MigrationStatus{Main: g.names[p]}
+		if chain := g.moduleChain(p, inOld); chain != nil {
+			s.OldChain = append([]string{"main"}, chain...)
+		}
+		if chain := g.moduleChain(p, inNew); chain != nil {
+			s.NewChain = append([]string{"main"}, chain...)
+		}
+		if s.OldChain != nil || s.NewChain != nil {
+			statuses = append(statuses, s)
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Main < statuses[j].Main })
+	return statuses
+}
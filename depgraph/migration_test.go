@@ -0,0 +1,37 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMigrationStatuses(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/api", Name: "main", Imports: []string{"github.com/golang-jwt/jwt/v4"},
+		Deps: []string{"github.com/golang-jwt/jwt/v4"}})
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/worker", Name: "main", Imports: []string{"example.com/auth"},
+		Deps: []string{"example.com/auth", "github.com/dgrijalva/jwt-go"}})
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/admin", Name: "main", Imports: []string{"example.com/auth", "github.com/golang-jwt/jwt/v4"},
+		Deps: []string{"example.com/auth", "github.com/dgrijalva/jwt-go", "github.com/golang-jwt/jwt/v4"}})
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/tool", Name: "main"})
+	dg.Add(DepInfo{ImportPath: "example.com/auth", Imports: []string{"github.com/dgrijalva/jwt-go"}, Deps: []string{"github.com/dgrijalva/jwt-go"}})
+	dg.Add(DepInfo{ImportPath: "github.com/dgrijalva/jwt-go"})
+	dg.Add(DepInfo{ImportPath: "github.com/golang-jwt/jwt/v4"})
+
+	statuses := dg.MigrationStatuses(Migration{Old: "github.com/dgrijalva/jwt-go", New: "github.com/golang-jwt/jwt"})
+	var got []string
+	for _, s := range statuses {
+		got = append(got, s.Main+":"+s.State())
+	}
+	if strings.Join(got, " ") != "example.com/cmd/admin:both example.com/cmd/api:new example.com/cmd/worker:old" {
+		t.Error(got)
+	}
+	if chain := strings.Join(statuses[2].OldChain, " -> "); chain != "main -> example.com/cmd/worker -> example.com/auth -> github.com/dgrijalva/jwt-go" {
+		t.Error(chain)
+	}
+	// the new path below the old one
+	m := Migration{Old: "github.com/golang-jwt/jwt", New: "github.com/golang-jwt/jwt/v4"}
+	if m.side("github.com/golang-jwt/jwt/v4") != 1 || m.side("github.com/golang-jwt/jwt/request") != -1 || m.side("github.com/golang-jwt/jwtx") != 0 {
+		t.Error("side")
+	}
+}
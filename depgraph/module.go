@@ -0,0 +1,161 @@
+package depgraph
+
+import "sort"
+
+// Module is the module a package belongs to, as reported by go list in
+// module mode.
+type Module struct {
+	Path    string  `json:"Path"`    // module path
+	Version string  `json:"Version"` // module version
+	Replace *Module `json:"Replace"` // replaced by this module
+	Main    bool    `json:"Main"`    // is this the main module?
+}
+
+// Module returns the module packageName belongs to, or nil in GOPATH mode
+// and for the standard library.
+func (g *DepGraph) Module(packageName string) *Module {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return nil
+	}
+	return g.modules[id]
+}
+
+// Modules returns every distinct module of the loaded packages, sorted by
+// path and version.
+func (g *DepGraph) Modules() (modules []Module) {
+	seen := make(map[Module]bool)
+	for _, m := range g.modules {
+		key := Module{Path: m.Path, Version: m.Version, Main: m.Main}
+		if !seen[key] {
+			seen[key] = true
+			modules = append(modules, *m)
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].Path != modules[j].Path {
+			return modules[i].Path < modules[j].Path
+		}
+		return modules[i].Version < modules[j].Version
+	})
+	return
+}
+
+// PackagesOf returns the loaded packages belonging to module path.
+func (g *DepGraph) PackagesOf(path string) (packages []string) {
+	for id, m := range g.modules {
+		if m.Path == path {
+			packages = append(packages, g.names[id])
+		}
+	}
+	sort.Strings(packages)
+	return
+}
+
+// ModuleUsers returns the packages outside module path that depend on one
+// of its packages: direct ones import a package of the module themselves,
+// transitive ones only reach it through other packages. Both are sorted.
+func (g *DepGraph) ModuleUsers(path string) (direct, transitive []string) {
+	inModule := make(map[nodeID]bool)
+	for id, m := range g.modules {
+		if m.Path == path {
+			inModule[id] = true
+		}
+	}
+	isDirect := make(map[nodeID]bool)
+	users := make(map[nodeID]bool)
+	for id := range inModule {
+		for _, importer := range g.importers[id] {
+			if g.loaded[importer] && !inModule[importer] {
+				isDirect[importer] = true
+			}
+		}
+		for _, dependent := range g.dependentsOf(id) {
+			if !inModule[dependent] {
+				users[dependent] = true
+			}
+		}
+	}
+	for id := range isDirect {
+		users[id] = true
+	}
+	for id := range users {
+		if isDirect[id] {
+			direct = append(direct, g.names[id])
+		} else {
+			transitive = append(transitive, g.names[id])
+		}
+	}
+	sort.Strings(direct)
+	sort.Strings(transitive)
+	return
+}
+
+// WhyModule explains, per main package, why the build needs module path:
+// it returns the shortest import chain main -> p -> ... -> q from each
+// main package p depending on the module to the first package q of the
+// module on the way, sorted by p. This is more precise than go mod why -m,
+// which doesn't tell which binary needs the module.
+func (g *DepGraph) WhyModule(path string) (chains [][]string) {
+	inModule := make(map[nodeID]bool)
+	for id, m := range g.modules {
+		if m.Path == path {
+			inModule[id] = true
+		}
+	}
+	if len(inModule) == 0 {
+		return
+	}
+	g.prepare()
+	for p := range g.mainPackages {
+		if chain := g.moduleChain(p, inModule); chain != nil {
+			chains = append(chains, append([]string{"main"}, chain...))
+		}
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i][1] < chains[j][1] })
+	return
+}
+
+// moduleChain returns the shortest import chain from start to a package
+// in inModule, or nil if start doesn't depend on the module.
+func (g *DepGraph) moduleChain(start nodeID, inModule map[nodeID]bool) []string {
+	if inModule[start] {
+		return []string{g.names[start]}
+	}
+	var target nodeID = -1
+	for id := range inModule {
+		if g.dependsOn(start, id) && (target < 0 || id < target) {
+			target = id
+		}
+	}
+	if target < 0 {
+		return nil
+	}
+	parent := map[nodeID]nodeID{start: -1}
+	queue := []nodeID{start}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, e := range g.imports[from] {
+			if !e.inBuild() {
+				continue
+			}
+			if _, ok := parent[e.to]; ok {
+				continue
+			}
+			parent[e.to] = from
+			if inModule[e.to] {
+				var chain []string
+				for id := e.to; id >= 0; id = parent[id] {
+					chain = append(chain, g.names[id])
+				}
+				reverseSlice(chain)
+				return chain
+			}
+			queue = append(queue, e.to)
+		}
+	}
+	// start depends on the module but the imports leading to it weren't
+	// loaded, eg: the standard library is missing
+	return []string{g.names[start], "...", g.names[target]}
+}
@@ -0,0 +1,93 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func moduleTestGraph() *DepGraph {
+	dg := &DepGraph{}
+	lib := &Module{Path: "example.com/lib", Version: "v1.4.0"}
+	app := &Module{Path: "example.com/app", Main: true}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Module: app,
+		Imports: []string{"example.com/app/internal/x"},
+		Deps:    []string{"example.com/app/internal/x", "example.com/lib/y", "example.com/lib/z"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/b", Name: "main", Module: app,
+		Imports: []string{"example.com/lib/y"},
+		Deps:    []string{"example.com/lib/y", "example.com/lib/z"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/internal/x", Name: "x", Module: app,
+		Imports: []string{"example.com/lib/y"},
+		Deps:    []string{"example.com/lib/y", "example.com/lib/z"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib/y", Name: "y", Module: lib,
+		Imports: []string{"example.com/lib/z"},
+		Deps:    []string{"example.com/lib/z"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib/z", Name: "z", Module: lib})
+	return dg
+}
+
+func TestModuleUsers(t *testing.T) {
+	dg := moduleTestGraph()
+	direct, transitive := dg.ModuleUsers("example.com/lib")
+	if strings.Join(direct, " ") != "example.com/app/cmd/b example.com/app/internal/x" {
+		t.Error("direct error", direct)
+	}
+	if strings.Join(transitive, " ") != "example.com/app/cmd/a" {
+		t.Error("transitive error", transitive)
+	}
+	if m := dg.Module("example.com/lib/z"); m == nil || m.Version != "v1.4.0" {
+		t.Error("module error", m)
+	}
+	if ms := dg.Modules(); len(ms) != 2 || ms[0].Path != "example.com/app" {
+		t.Error("modules error", ms)
+	}
+}
+
+func TestWhyModule(t *testing.T) {
+	dg := moduleTestGraph()
+	var chains []string
+	for _, chain := range dg.WhyModule("example.com/lib") {
+		chains = append(chains, strings.Join(chain, " -> "))
+	}
+	expect := "main -> example.com/app/cmd/a -> example.com/app/internal/x -> example.com/lib/y\n" +
+		"main -> example.com/app/cmd/b -> example.com/lib/y"
+	if strings.Join(chains, "\n") != expect {
+		t.Error(chains)
+	}
+	if chains := dg.WhyModule("example.com/app"); len(chains) != 2 || len(chains[0]) != 2 {
+		t.Error("main package in the module", chains)
+	}
+	if chains := dg.WhyModule("example.com/nope"); chains != nil {
+		t.Error(chains)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	dg := &DepGraph{}
+	fork := &Module{Path: "example.com/lib", Version: "v1.4.0",
+		Replace: &Module{Path: "github.com/fork/lib", Version: "v1.4.1"}}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main",
+		Imports: []string{"example.com/lib/y", "example.com/patched/z"},
+		Deps:    []string{"example.com/lib/y", "example.com/patched/z"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib/y", Name: "y", Module: fork})
+	dg.Add(DepInfo{ImportPath: "example.com/patched/z", Name: "z"})
+	dg.AddReplace("example.com/patched", "github.com/fork/patched")
+	dg.AddReplace("example.com/local", "../local")
+
+	for _, p := range []string{"example.com/lib/y", "github.com/fork/lib/y"} {
+		if mains := dg.SearchMain(p); len(mains) != 1 || mains[0] != "example.com/app/cmd/a" {
+			t.Error(p, mains)
+		}
+	}
+	if !dg.Freeze().Exists("github.com/fork/patched/z") || dg.Exists("github.com/fork/lib/x") {
+		t.Error("lookup through replace error")
+	}
+	if r := dg.Replacement("github.com/fork/lib/y"); r != "github.com/fork/lib" {
+		t.Error("replacement error", r)
+	}
+	if r := dg.Replacement("example.com/patched/z"); r != "github.com/fork/patched" {
+		t.Error("replacement error", r)
+	}
+	if r := dg.Replacement("example.com/app/cmd/a"); r != "" {
+		t.Error("replacement error", r)
+	}
+}
@@ -0,0 +1,110 @@
+package depgraph
+
+import (
+	"sort"
+	"sync"
+)
+
+// MultiGraph holds several named graphs, eg: one per repository or per
+// build profile, and runs queries on all of them, each graph answering
+// for itself: unlike concatenating their go list outputs into one graph,
+// packages of the same path in different graphs stay apart.
+type MultiGraph struct {
+	graphs map[string]*DepGraph
+}
+
+func NewMultiGraph() *MultiGraph {
+	return &MultiGraph{graphs: make(map[string]*DepGraph)}
+}
+
+// Add adds the graph g under name, replacing the previous one. Queries
+// run on the graphs concurrently, so g must not be added twice.
+func (m *MultiGraph) Add(name string, g *DepGraph) {
+	m.graphs[name] = g
+}
+
+// Graph returns the graph named name, nil if there is none.
+func (m *MultiGraph) Graph(name string) *DepGraph {
+	return m.graphs[name]
+}
+
+// Names returns the names of the graphs, sorted.
+func (m *MultiGraph) Names() []string {
+	names := make([]string, 0, len(m.graphs))
+	for name := range m.graphs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tagged is the answer of one graph of a MultiGraph to a query.
+type Tagged struct {
+	Graph  string
+	Answer interface{}
+}
+
+// Run runs query on every graph, one goroutine per graph, and returns the
+// answers by graph name, sorted. Answers for which empty returns true,
+// eg: no chain found, are left out; empty may be nil.
+func (m *MultiGraph) Run(query func(*DepGraph) interface{}, empty func(interface{}) bool) []Tagged {
+	names := m.Names()
+	answers := make([]interface{}, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, g *DepGraph) {
+			defer wg.Done()
+			answers[i] = query(g)
+		}(i, m.graphs[name])
+	}
+	wg.Wait()
+	tagged := make([]Tagged, 0, len(names))
+	for i, name := range names {
+		if empty == nil || !empty(answers[i]) {
+			tagged = append(tagged, Tagged{Graph: name, Answer: answers[i]})
+		}
+	}
+	return tagged
+}
+
+// TaggedPackages is the answer of one graph to a query listing packages.
+type TaggedPackages struct {
+	Graph    string
+	Packages []string
+}
+
+// TaggedChains is the answer of one graph to a query listing chains.
+type TaggedChains struct {
+	Graph  string
+	Chains [][]string
+}
+
+// SearchMain is DepGraph.SearchMain on every graph, sorted by graph name,
+// the main packages of each sorted. Graphs without any are left out.
+func (m *MultiGraph) SearchMain(packageName string) []TaggedPackages {
+	result := make([]TaggedPackages, 0)
+	for _, t := range m.Run(func(g *DepGraph) interface{} {
+		mains := g.SearchMain(packageName)
+		sort.Strings(mains)
+		return mains
+	}, func(a interface{}) bool { return len(a.([]string)) == 0 }) {
+		result = append(result, TaggedPackages{Graph: t.Graph, Packages: t.Answer.([]string)})
+	}
+	return result
+}
+
+// SearchChain is DepGraph.SearchChain on every graph, sorted by graph
+// name, the chains of each by main package. Graphs without any chain are
+// left out.
+func (m *MultiGraph) SearchChain(packageName string) []TaggedChains {
+	result := make([]TaggedChains, 0)
+	for _, t := range m.Run(func(g *DepGraph) interface{} {
+		chains := g.SearchChain(packageName)
+		sort.Slice(chains, func(i, j int) bool { return chains[i][1] < chains[j][1] })
+		return chains
+	}, func(a interface{}) bool { return len(a.([][]string)) == 0 }) {
+		result = append(result, TaggedChains{Graph: t.Graph, Chains: t.Answer.([][]string)})
+	}
+	return result
+}
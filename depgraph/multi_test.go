@@ -0,0 +1,43 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMultiGraph(t *testing.T) {
+	a := &DepGraph{}
+	a.Add(DepInfo{ImportPath: "a/cmd/x", Name: "main", Imports: []string{"lib"}, Deps: []string{"lib"}})
+	a.Add(DepInfo{ImportPath: "lib"})
+	b := &DepGraph{}
+	b.Add(DepInfo{ImportPath: "b/cmd/z", Name: "main", Imports: []string{"b/util"}, Deps: []string{"b/util", "lib"}})
+	b.Add(DepInfo{ImportPath: "b/cmd/y", Name: "main", Imports: []string{"lib"}, Deps: []string{"lib"}})
+	b.Add(DepInfo{ImportPath: "b/util", Imports: []string{"lib"}, Deps: []string{"lib"}})
+	b.Add(DepInfo{ImportPath: "lib"})
+	c := &DepGraph{}
+	c.Add(DepInfo{ImportPath: "c/cmd/w", Name: "main"})
+
+	m := NewMultiGraph()
+	m.Add("repo-b", b)
+	m.Add("repo-a", a)
+	m.Add("repo-c", c)
+	if names := m.Names(); !reflect.DeepEqual(names, []string{"repo-a", "repo-b", "repo-c"}) || m.Graph("repo-b") != b {
+		t.Error(names)
+	}
+
+	expect := []TaggedPackages{{"repo-a", []string{"a/cmd/x"}}, {"repo-b", []string{"b/cmd/y", "b/cmd/z"}}}
+	if mains := m.SearchMain("lib"); !reflect.DeepEqual(mains, expect) {
+		t.Error(mains)
+	}
+	expectChains := []TaggedChains{
+		{"repo-a", [][]string{{"main", "a/cmd/x", "lib"}}},
+		{"repo-b", [][]string{{"main", "b/cmd/y", "lib"}, {"main", "b/cmd/z", "b/util", "lib"}}},
+	}
+	if chains := m.SearchChain("lib"); !reflect.DeepEqual(chains, expectChains) {
+		t.Error(chains)
+	}
+	counts := m.Run(func(g *DepGraph) interface{} { return g.CountMain() }, nil)
+	if len(counts) != 3 || counts[1].Graph != "repo-b" || counts[1].Answer != 2 {
+		t.Error(counts)
+	}
+}
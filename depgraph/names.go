@@ -0,0 +1,46 @@
+package depgraph
+
+import "sort"
+
+// PackageName returns the package name of packageName from the Name field
+// of go list, or "" if unknown.
+func (g *DepGraph) PackageName(packageName string) string {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return ""
+	}
+	return g.pkgNames[id]
+}
+
+// NameCollision is a package name several import paths of one binary
+// share.
+type NameCollision struct {
+	Name     string
+	Packages []string // sorted
+}
+
+// NameCollisions returns the package names shared by distinct import
+// paths among the deps of mainPackage, sorted by name, eg: three
+// different "utils" packages. Outside of well-known cases like
+// math/rand and crypto/rand, they often are forked or copy-pasted code.
+func (g *DepGraph) NameCollisions(mainPackage string) (collisions []NameCollision) {
+	id, ok := g.lookup(mainPackage)
+	if !ok {
+		return
+	}
+	g.prepare()
+	byName := make(map[string][]string)
+	g.eachDep(id, func(dep nodeID) {
+		if name := g.pkgNames[dep]; name != "" {
+			byName[name] = append(byName[name], g.names[dep])
+		}
+	})
+	for name, packages := range byName {
+		if len(packages) > 1 {
+			sort.Strings(packages)
+			collisions = append(collisions, NameCollision{Name: name, Packages: packages})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].Name < collisions[j].Name })
+	return
+}
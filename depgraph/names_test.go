@@ -0,0 +1,40 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNameCollisions(t *testing.T) {
+	dg := loadTestGraph(t)
+	if dg.PackageName("net/http") != "http" || dg.PackageName("cmd/vet") != "main" || dg.PackageName("nothing") != "" {
+		t.Error("package name error")
+	}
+	var rands []string
+	for _, c := range dg.NameCollisions("cmd/go") {
+		if len(c.Packages) < 2 {
+			t.Error("not a collision", c)
+		}
+		if c.Name == "rand" {
+			rands = c.Packages
+		}
+	}
+	if !reflect.DeepEqual(rands, []string{"crypto/rand", "math/rand"}) {
+		t.Error(rands)
+	}
+
+	dg = &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/a", Name: "main",
+		Deps: []string{"example.com/a/utils", "example.com/b/utils", "example.com/c/util"}})
+	dg.Add(DepInfo{ImportPath: "example.com/a/utils", Name: "utils"})
+	dg.Add(DepInfo{ImportPath: "example.com/b/utils", Name: "utils"})
+	dg.Add(DepInfo{ImportPath: "example.com/c/util", Name: "util"})
+	expect := []NameCollision{{"utils", []string{"example.com/a/utils", "example.com/b/utils"}}}
+	if got := dg.NameCollisions("example.com/cmd/a"); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	dg.Remove("example.com/b/utils")
+	if got := dg.NameCollisions("example.com/cmd/a"); len(got) != 0 {
+		t.Error("removed package should not collide", got)
+	}
+}
@@ -0,0 +1,99 @@
+package depgraph
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SortOrder is an order of search results, see ResultLess.
+type SortOrder string
+
+const (
+	SortByPath   SortOrder = "path"   // import path
+	SortByModule SortOrder = "module" // module path, the standard library first, then import path
+	SortByDeps   SortOrder = "deps"   // most dependencies first
+	SortByChain  SortOrder = "chain"  // shortest chain to the target first
+)
+
+// ParseSortOrder returns the SortOrder named s.
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch by := SortOrder(s); by {
+	case SortByPath, SortByModule, SortByDeps, SortByChain:
+		return by, nil
+	}
+	return "", fmt.Errorf("unknown sort order %q, supported: path,module,deps,chain", s)
+}
+
+// Result is a package a search for a target found, and the chain it was
+// found through, if any.
+type Result struct {
+	Package string
+	Chain   []string
+}
+
+// ResultLess returns the less function sorting results of a search for
+// target by by. Results without chain are ranked by their shortest import
+// chain to target, the packages not importing it last. Ties are broken by
+// import path.
+func (g *DepGraph) ResultLess(target string, by SortOrder) func(a, b Result) bool {
+	keys := make(map[string]int)
+	key := func(r Result) int {
+		if by == SortByChain && r.Chain != nil {
+			return len(r.Chain)
+		}
+		k, ok := keys[r.Package]
+		if !ok {
+			k = g.sortKey(r.Package, target, by)
+			keys[r.Package] = k
+		}
+		return k
+	}
+	return func(a, b Result) bool {
+		if by == SortByModule {
+			if ma, mb := g.sortModule(a.Package), g.sortModule(b.Package); ma != mb {
+				return ma < mb
+			}
+		}
+		if ka, kb := key(a), key(b); ka != kb {
+			return ka < kb
+		}
+		return a.Package < b.Package
+	}
+}
+
+// SortResults sorts results of a search for target by by.
+func (g *DepGraph) SortResults(results []Result, target string, by SortOrder) {
+	less := g.ResultLess(target, by)
+	sort.SliceStable(results, func(i, j int) bool { return less(results[i], results[j]) })
+}
+
+func (g *DepGraph) sortKey(pkg, target string, by SortOrder) int {
+	switch by {
+	case SortByDeps:
+		return -len(g.Deps(pkg))
+	case SortByChain:
+		from, ok := g.lookup(pkg)
+		to, found := g.lookup(target)
+		if !ok || !found {
+			return 0
+		}
+		if chain := g.moduleChain(from, map[nodeID]bool{to: true}); chain != nil {
+			return len(chain)
+		}
+		// not importing target at all, last
+		return math.MaxInt32
+	}
+	return 0
+}
+
+// sortModule is the module part of the SortByModule key of pkg.
+func (g *DepGraph) sortModule(pkg string) string {
+	if g.IsStandard(pkg) {
+		return ""
+	}
+	if m := g.Module(pkg); m != nil {
+		return m.Path
+	}
+	return pkg
+}
@@ -0,0 +1,55 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortResults(t *testing.T) {
+	dg := &DepGraph{}
+	app := &Module{Path: "example.com/app", Main: true}
+	lib := &Module{Path: "example.com/lib"}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Module: app,
+		Imports: []string{"example.com/lib"}, Deps: []string{"example.com/lib", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/b", Name: "main", Module: app,
+		Imports: []string{"example.com/app/db"}, Deps: []string{"example.com/app/db", "example.com/lib", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/db", Module: app,
+		Imports: []string{"example.com/lib"}, Deps: []string{"example.com/lib", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib", Module: lib, Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Standard: true})
+
+	sorted := func(by SortOrder, packages ...string) (got []string) {
+		results := make([]Result, len(packages))
+		for i, p := range packages {
+			results[i] = Result{Package: p}
+		}
+		dg.SortResults(results, "example.com/lib", by)
+		for _, r := range results {
+			got = append(got, r.Package)
+		}
+		return
+	}
+	all := []string{"example.com/lib", "example.com/app/db", "example.com/app/cmd/b", "fmt", "example.com/app/cmd/a"}
+	for by, expect := range map[SortOrder][]string{
+		SortByPath:   {"example.com/app/cmd/a", "example.com/app/cmd/b", "example.com/app/db", "example.com/lib", "fmt"},
+		SortByModule: {"fmt", "example.com/app/cmd/a", "example.com/app/cmd/b", "example.com/app/db", "example.com/lib"},
+		SortByDeps:   {"example.com/app/cmd/b", "example.com/app/cmd/a", "example.com/app/db", "example.com/lib", "fmt"},
+		SortByChain:  {"example.com/lib", "example.com/app/cmd/a", "example.com/app/db", "example.com/app/cmd/b", "fmt"},
+	} {
+		if got := sorted(by, all...); !reflect.DeepEqual(got, expect) {
+			t.Error(by, got)
+		}
+	}
+
+	results := []Result{
+		{"example.com/app/cmd/b", []string{"example.com/app/cmd/b", "example.com/app/db", "example.com/lib"}},
+		{"example.com/app/cmd/a", []string{"example.com/app/cmd/a", "example.com/lib"}},
+	}
+	dg.SortResults(results, "example.com/lib", SortByChain)
+	if results[0].Package != "example.com/app/cmd/a" {
+		t.Error(results)
+	}
+	if _, err := ParseSortOrder("size"); err == nil {
+		t.Error("expect error for unknown order")
+	}
+}
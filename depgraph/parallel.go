@@ -0,0 +1,62 @@
+package depgraph
+
+import (
+	"runtime"
+	"sync"
+)
+
+// SetConcurrency limits how many goroutines full-graph scans such as
+// ListUnUsed, Roots and Leaves may use. n <= 0 means GOMAXPROCS.
+func (g *DepGraph) SetConcurrency(n int) {
+	g.concurrency = n
+}
+
+// minShard keeps small graphs from paying for goroutines they don't need.
+const minShard = 4096
+
+// filterNodes shards the node IDs across goroutines and returns, in ID
+// order, those for which keep returns true. keep must only read the graph.
+func (g *DepGraph) filterNodes(keep func(id nodeID) bool) []nodeID {
+	g.prepare()
+	n := len(g.names)
+	workers := g.concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if max := (n + minShard - 1) / minShard; workers > max {
+		workers = max
+	}
+	if workers <= 1 {
+		var kept []nodeID
+		for id := 0; id < n; id++ {
+			if keep(nodeID(id)) {
+				kept = append(kept, nodeID(id))
+			}
+		}
+		return kept
+	}
+	shards := make([][]nodeID, workers)
+	size := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			end := (w + 1) * size
+			if end > n {
+				end = n
+			}
+			for id := w * size; id < end; id++ {
+				if keep(nodeID(id)) {
+					shards[w] = append(shards[w], nodeID(id))
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	var kept []nodeID
+	for _, shard := range shards {
+		kept = append(kept, shard...)
+	}
+	return kept
+}
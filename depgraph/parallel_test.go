@@ -0,0 +1,29 @@
+package depgraph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParallelScan(t *testing.T) {
+	dg := &DepGraph{}
+	for i := 0; i < 20000; i++ {
+		d := DepInfo{ImportPath: fmt.Sprintf("example.com/p%d", i), Name: "p"}
+		if i%3 != 0 {
+			d.Imports = []string{fmt.Sprintf("example.com/p%d", i/3)}
+			d.Deps = d.Imports
+		}
+		dg.Add(d)
+	}
+	dg.SetConcurrency(1)
+	serialUnused, serialRoots := dg.ListUnUsed(), dg.Roots()
+	dg.SetConcurrency(8)
+	unused, roots := dg.ListUnUsed(), dg.Roots()
+	if strings.Join(unused, " ") != strings.Join(serialUnused, " ") || len(unused) == 0 {
+		t.Error("ListUnUsed differs", len(unused), len(serialUnused))
+	}
+	if strings.Join(roots, " ") != strings.Join(serialRoots, " ") {
+		t.Error("Roots differs", len(roots), len(serialRoots))
+	}
+}
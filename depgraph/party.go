@@ -0,0 +1,60 @@
+package depgraph
+
+// FirstParty sets the import path prefixes of first-party code, eg:
+// "corp.example.com/**". Without prefixes, the packages of the main
+// module are first-party. Packages neither first-party nor in the standard
+// library are third-party.
+func (g *DepGraph) FirstParty(prefixes []string) {
+	g.firstParty = nil
+	if len(prefixes) > 0 {
+		g.firstParty = ByPrefix(prefixes)
+	}
+	g.changed()
+}
+
+// IsFirstParty reports whether packageName is first-party code, see
+// FirstParty.
+func (g *DepGraph) IsFirstParty(packageName string) bool {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return g.firstParty != nil && g.firstParty(packageName) != ""
+	}
+	return g.isFirstParty(id)
+}
+
+func (g *DepGraph) isFirstParty(id nodeID) bool {
+	if g.firstParty != nil {
+		return g.firstParty(g.names[id]) != ""
+	}
+	m := g.modules[id]
+	return m != nil && m.Main
+}
+
+// IsThirdParty reports whether packageName is neither first-party nor in
+// the standard library.
+func (g *DepGraph) IsThirdParty(packageName string) bool {
+	return !g.IsFirstParty(packageName) && !g.IsStandard(packageName)
+}
+
+func (g *DepGraph) isThirdParty(id nodeID) bool {
+	return !g.isFirstParty(id) && !g.isStandard(id)
+}
+
+// ThirdPartyShare returns how many of the packages pkg is built from,
+// itself and its deps, are third-party, out of total. For a main package,
+// it tells how much of the binary is external code.
+func (g *DepGraph) ThirdPartyShare(pkg string) (thirdParty, total int) {
+	id, ok := g.lookup(pkg)
+	if !ok || !g.loaded[id] {
+		return
+	}
+	count := func(id nodeID) {
+		total++
+		if g.isThirdParty(id) {
+			thirdParty++
+		}
+	}
+	count(id)
+	g.eachDep(id, count)
+	return
+}
@@ -0,0 +1,34 @@
+package depgraph
+
+import "testing"
+
+func TestFirstParty(t *testing.T) {
+	dg := &DepGraph{}
+	app := &Module{Path: "example.com/app", Main: true}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Module: app,
+		Deps: []string{"example.com/app/log", "example.com/shared/auth", "github.com/pkg/errors", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/log", Module: app})
+	dg.Add(DepInfo{ImportPath: "example.com/shared/auth", Module: &Module{Path: "example.com/shared"}})
+	dg.Add(DepInfo{ImportPath: "github.com/pkg/errors", Module: &Module{Path: "github.com/pkg/errors"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Standard: true})
+
+	if !dg.IsFirstParty("example.com/app/log") || !dg.IsThirdParty("example.com/shared/auth") ||
+		dg.IsFirstParty("fmt") || dg.IsThirdParty("fmt") {
+		t.Error("main module should be first-party")
+	}
+	if n, total := dg.ThirdPartyShare("example.com/app/cmd/a"); n != 2 || total != 5 {
+		t.Error(n, total)
+	}
+
+	dg.FirstParty([]string{"example.com/**"})
+	if !dg.IsFirstParty("example.com/shared/auth") || !dg.IsThirdParty("github.com/pkg/errors") ||
+		!dg.IsFirstParty("example.com/missing") {
+		t.Error("prefixes should set first-party")
+	}
+	if n, total := dg.ThirdPartyShare("example.com/app/cmd/a"); n != 1 || total != 5 {
+		t.Error(n, total)
+	}
+	if first, third := dg.MostDepended(); len(first) != 3 || len(third) != 1 {
+		t.Error(first, third)
+	}
+}
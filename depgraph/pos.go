@@ -0,0 +1,59 @@
+package depgraph
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+)
+
+// RecordImportPos makes Add record where every import of a package whose
+// record doesn't carry ImportPos is written, parsing the import
+// declarations of its GoFiles in Dir, see EdgeAttrs.Pos. Like CountLines
+// it only works on the machine go list ran on; unreadable files are left
+// out.
+func (g *DepGraph) RecordImportPos(record bool) {
+	g.recordImportPos = record
+}
+
+// importPos returns the "file:line" positions of the imports of files in
+// dir, by import path as written in the source.
+func importPos(dir string, files []string) map[string][]string {
+	pos := make(map[string][]string)
+	fset := token.NewFileSet()
+	for _, name := range files {
+		file := filepath.Join(dir, name)
+		f, err := parser.ParseFile(fset, file, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range f.Imports {
+			p, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			pos[p] = append(pos[p], fmt.Sprintf("%s:%d", file, fset.Position(spec.Pos()).Line))
+		}
+	}
+	return pos
+}
+
+// setImportPos copies the positions of d.ImportPos, keyed by import path
+// as written in the source, to the edges of the resolved imports.
+func (d *DepInfo) setImportPos(edges map[string]*EdgeAttrs, origins map[string]string) {
+	resolved := make(map[string][]string, len(d.ImportPos))
+	for src, pos := range d.ImportPos {
+		resolved[src] = pos
+		if dst, ok := d.ImportMap[src]; ok {
+			resolved[dst] = pos
+		}
+	}
+	for p, attrs := range edges {
+		pos, ok := resolved[p]
+		if !ok {
+			pos = resolved[origins[p]]
+		}
+		attrs.Pos = append([]string(nil), pos...)
+	}
+}
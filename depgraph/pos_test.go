@@ -0,0 +1,55 @@
+package depgraph
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRecordImportPos(t *testing.T) {
+	dir, err := ioutil.TempDir("", "importpos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"token.go": "package auth\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/x/jwt\"\n)\n",
+		"auth.go":  "package auth\n\nimport \"fmt\"\n",
+	}
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	info := DepInfo{ImportPath: "pkg/auth", Name: "auth", Dir: dir, GoFiles: []string{"auth.go", "token.go"},
+		Imports:   []string{"fmt", "vendor/github.com/x/jwt"},
+		ImportMap: map[string]string{"github.com/x/jwt": "vendor/github.com/x/jwt"}}
+
+	dg := &DepGraph{}
+	dg.Add(info)
+	if e := dg.Edge("pkg/auth", "fmt"); e == nil || e.Pos != nil {
+		t.Error("positions recorded without RecordImportPos", e)
+	}
+
+	dg = &DepGraph{}
+	dg.RecordImportPos(true)
+	dg.Add(info)
+	expect := []string{filepath.Join(dir, "auth.go") + ":3", filepath.Join(dir, "token.go") + ":4"}
+	if e := dg.Edge("pkg/auth", "fmt"); e == nil || !reflect.DeepEqual(e.Pos, expect) {
+		t.Error(e)
+	}
+	if e := dg.Edge("pkg/auth", "vendor/github.com/x/jwt"); e == nil || !reflect.DeepEqual(e.Pos, []string{filepath.Join(dir, "token.go") + ":6"}) {
+		t.Error(e)
+	}
+
+	// unvendored, and positions given by the record
+	dg = &DepGraph{}
+	dg.NormalizeVendor(true)
+	info.ImportPos = map[string][]string{"github.com/x/jwt": {"token.go:6"}}
+	dg.Add(info)
+	if e := dg.Edge("pkg/auth", "github.com/x/jwt"); e == nil || !reflect.DeepEqual(e.Pos, []string{"token.go:6"}) {
+		t.Error(e)
+	}
+}
@@ -0,0 +1,302 @@
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PruneOptions tells PruneGraph how to shrink a SearchGraph result.
+type PruneOptions struct {
+	MaxDepth int  // keep the packages at most MaxDepth imports away from start, 0 for no limit
+	MaxNodes int  // keep at most MaxNodes packages, the closest to start, 0 for no limit
+	Collapse bool // merge runs of packages with one importer and one import into one node
+	// Summarize, if > 0, keeps the Summarize packages with the most
+	// imports and importers in the graph and collapses the others into
+	// one "group (N packages)" node per group of GroupBy, eg: ByModule.
+	Summarize int
+	GroupBy   Grouper // nil collapses every other package into one node
+}
+
+// PruneGraph shrinks result, a SearchGraph(start, target) graph, so it
+// stays readable for popular targets. Runs collapsed by opts.Collapse
+// become one "first ... last (N packages)" node. The packages dropped by
+// the limits are replaced by a single "...N more" node, imported by the
+// kept packages that imported them and importing target, which is always
+// kept.
+func PruneGraph(result map[string][]string, start, target string, opts PruneOptions) map[string][]string {
+	return PruneGraphFrom(result, []string{start}, target, opts)
+}
+
+// PruneGraphFrom is PruneGraph for a SearchGraphFrom(starts, target)
+// graph: distances are counted from the closest start and every start is
+// kept out of collapsed runs.
+func PruneGraphFrom(result map[string][]string, starts []string, target string, opts PruneOptions) map[string][]string {
+	if len(result) == 0 {
+		return result
+	}
+	isStart := make(map[string]bool, len(starts))
+	for _, start := range starts {
+		isStart[start] = true
+	}
+	if opts.Collapse {
+		result = collapseRuns(result, isStart, target)
+	}
+	if opts.Summarize > 0 {
+		result = summarizeGraph(result, opts.Summarize, opts.GroupBy, isStart, target)
+	}
+	depth := make(map[string]int)
+	var order []string
+	for _, start := range starts {
+		if _, ok := depth[start]; !ok && len(result[start]) > 0 {
+			depth[start] = 0
+			order = append(order, start)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, to := range result[order[i]] {
+			if _, ok := depth[to]; !ok {
+				depth[to] = depth[order[i]] + 1
+				order = append(order, to)
+			}
+		}
+	}
+	keep := make(map[string]bool)
+	for _, p := range order {
+		if p == target || opts.MaxDepth > 0 && depth[p] > opts.MaxDepth {
+			continue
+		}
+		if opts.MaxNodes > 0 && len(keep) >= opts.MaxNodes-1 {
+			break
+		}
+		keep[p] = true
+	}
+	keep[target] = true
+	dropped := len(order) - len(keep)
+	if dropped == 0 {
+		return result
+	}
+	more := fmt.Sprintf("...%d more", dropped)
+	pruned := make(map[string][]string)
+	for from, tos := range result {
+		if !keep[from] {
+			continue
+		}
+		toMore := false
+		for _, to := range tos {
+			if keep[to] {
+				pruned[from] = append(pruned[from], to)
+			} else if !toMore {
+				toMore = true
+				pruned[from] = append(pruned[from], more)
+			}
+		}
+	}
+	pruned[more] = []string{target}
+	return pruned
+}
+
+// collapseRuns merges the runs of packages of result with exactly one
+// importer and one import, the starts and target excepted.
+func collapseRuns(result map[string][]string, isStart map[string]bool, target string) map[string][]string {
+	importers := make(map[string]int)
+	for _, tos := range result {
+		for _, to := range tos {
+			importers[to]++
+		}
+	}
+	linear := func(p string) bool {
+		return !isStart[p] && p != target && importers[p] == 1 && len(result[p]) == 1
+	}
+	froms := make([]string, 0, len(result))
+	for from := range result {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+	collapsed := make(map[string][]string)
+	for _, from := range froms {
+		if linear(from) {
+			continue
+		}
+		for _, to := range result[from] {
+			var run []string
+			for p := to; linear(p) && len(run) <= len(result); p = result[p][0] {
+				run = append(run, p)
+			}
+			if len(run) < 2 {
+				collapsed[from] = append(collapsed[from], to)
+				for _, p := range run {
+					collapsed[p] = result[p]
+				}
+				continue
+			}
+			node := fmt.Sprintf("%s ... %s (%d packages)", run[0], run[len(run)-1], len(run))
+			collapsed[from] = append(collapsed[from], node)
+			collapsed[node] = result[run[len(run)-1]]
+		}
+	}
+	return collapsed
+}
+
+// Prune returns a copy of g without the packages drop returns true for:
+// their records and every import of or dependency on them are left out,
+// so exports and analyses of the result only see the rest. Package flags,
+// modules, sizes, edge attributes and the graph settings are kept.
+func (g *DepGraph) Prune(drop func(pkg string) bool) *DepGraph {
+	g.prepare()
+	p := &DepGraph{ignoreDeps: g.ignoreDeps, concurrency: g.concurrency, firstParty: g.firstParty,
+		build: g.build, sawStandard: g.sawStandard, testClassifier: g.testClassifier}
+	dropped := make([]bool, len(g.names))
+	for id, name := range g.names {
+		dropped[id] = drop(name)
+	}
+	type attrsOf struct {
+		from, to string
+		attrs    EdgeAttrs
+	}
+	var attrs []attrsOf
+	for id, name := range g.names {
+		id := nodeID(id)
+		if !g.loaded[id] || dropped[id] {
+			continue
+		}
+		info := DepInfo{ImportPath: name, Name: g.pkgNames[id], Standard: g.standard[id],
+			Module: g.modules[id], Dir: g.dirs[id], EmbedFiles: g.embeds[id]}
+		if g.mainPackages[id] || g.testPackages[id] {
+			info.Name = "main"
+		}
+		if g.cgoPackages[id] {
+			info.Imports = append(info.Imports, "C")
+		}
+		for _, e := range g.imports[id] {
+			if !dropped[e.to] {
+				to := g.names[e.to]
+				info.Imports = append(info.Imports, to)
+				attrs = append(attrs, attrsOf{name, to, *e.attrs})
+			}
+		}
+		g.eachDep(id, func(dep nodeID) {
+			if !dropped[dep] {
+				info.Deps = append(info.Deps, g.names[dep])
+			}
+		})
+		p.Add(info)
+		g.copyMainFlags(p, id, p.ids[name])
+		p.sizes[p.ids[name]] = g.sizes[id]
+	}
+	for _, a := range attrs {
+		p.SetEdge(a.from, a.to, a.attrs)
+	}
+	for old, new := range g.replaces {
+		p.AddReplace(old, new)
+	}
+	for pkg, w := range g.weights {
+		if id, ok := g.lookup(pkg); !ok || !dropped[id] {
+			p.SetWeight(pkg, w)
+		}
+	}
+	for pkg := range g.tools {
+		if id, ok := g.lookup(pkg); !ok || !dropped[id] {
+			p.AddTool(pkg)
+		}
+	}
+	return p
+}
+
+// PruneStdlib returns a copy of g without the standard library, see
+// Prune.
+func (g *DepGraph) PruneStdlib() *DepGraph {
+	return g.Prune(g.IsStandard)
+}
+
+// PruneSubtree returns a copy of g without prefix and the packages below
+// it, see Prune. A trailing "/..." or "/**" is optional.
+func (g *DepGraph) PruneSubtree(prefix string) *DepGraph {
+	under := ByPrefix([]string{prefix})
+	return g.Prune(func(pkg string) bool { return under(pkg) != "" })
+}
+
+// summarizeGraph keeps the keep packages of result with the highest degree,
+// the starts and target first, and merges the others by group into one
+// node each, so the summary has at most keep packages plus one node per
+// group. A group of a single package keeps its name.
+func summarizeGraph(result map[string][]string, keep int, group Grouper, isStart map[string]bool, target string) map[string][]string {
+	degree := make(map[string]int)
+	for from, tos := range result {
+		degree[from] += len(tos)
+		for _, to := range tos {
+			degree[to]++
+		}
+	}
+	if len(degree) <= keep {
+		return result
+	}
+	nodes := make([]string, 0, len(degree))
+	for p := range degree {
+		nodes = append(nodes, p)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if degree[nodes[i]] != degree[nodes[j]] {
+			return degree[nodes[i]] > degree[nodes[j]]
+		}
+		return nodes[i] < nodes[j]
+	})
+	kept := make(map[string]bool)
+	for _, p := range nodes {
+		if isStart[p] || p == target {
+			kept[p] = true
+		}
+	}
+	for _, p := range nodes {
+		if len(kept) >= keep {
+			break
+		}
+		kept[p] = true
+	}
+	members := make(map[string][]string)
+	for _, p := range nodes {
+		if kept[p] {
+			continue
+		}
+		g := "others"
+		if group != nil {
+			if g = group(p); g == "" {
+				g = p
+			}
+		}
+		members[g] = append(members[g], p)
+	}
+	name := make(map[string]string, len(nodes))
+	for p := range kept {
+		name[p] = p
+	}
+	for g, packages := range members {
+		node := packages[0]
+		if len(packages) > 1 {
+			node = fmt.Sprintf("%s (%d packages)", g, len(packages))
+		}
+		for _, p := range packages {
+			name[p] = node
+		}
+	}
+	edges := make(map[string]map[string]bool)
+	for from, tos := range result {
+		for _, to := range tos {
+			a, b := name[from], name[to]
+			if a == b {
+				continue
+			}
+			if edges[a] == nil {
+				edges[a] = make(map[string]bool)
+			}
+			edges[a][b] = true
+		}
+	}
+	summary := make(map[string][]string, len(edges))
+	for from, tos := range edges {
+		for to := range tos {
+			summary[from] = append(summary[from], to)
+		}
+		sort.Strings(summary[from])
+	}
+	return summary
+}
@@ -0,0 +1,117 @@
+package depgraph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPruneGraph(t *testing.T) {
+	// main -> a -> b -> c -> target, main -> d -> target, main -> e -> f -> target
+	result := map[string][]string{
+		"main": {"a", "d", "e"},
+		"a":    {"b"},
+		"b":    {"c"},
+		"c":    {"target"},
+		"d":    {"target"},
+		"e":    {"f"},
+		"f":    {"target"},
+	}
+	if got := PruneGraph(result, "main", "target", PruneOptions{}); !reflect.DeepEqual(got, result) {
+		t.Error("no options should keep the graph", got)
+	}
+	expect := map[string][]string{
+		"main":                 {"a ... c (3 packages)", "d", "e ... f (2 packages)"},
+		"a ... c (3 packages)": {"target"},
+		"d":                    {"target"},
+		"e ... f (2 packages)": {"target"},
+	}
+	if got := PruneGraph(result, "main", "target", PruneOptions{Collapse: true}); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	expect = map[string][]string{
+		"main":      {"a", "d", "e"},
+		"a":         {"...3 more"},
+		"d":         {"target"},
+		"e":         {"...3 more"},
+		"...3 more": {"target"},
+	}
+	if got := PruneGraph(result, "main", "target", PruneOptions{MaxDepth: 1}); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	expect = map[string][]string{
+		"main":      {"a", "...5 more"},
+		"a":         {"...5 more"},
+		"...5 more": {"target"},
+	}
+	if got := PruneGraph(result, "main", "target", PruneOptions{MaxNodes: 3}); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/cmd/a", Name: "main", GoFiles: []string{"a.go"},
+		Imports: []string{"example.com/gen/pb", "example.com/lib", "fmt"},
+		Deps:    []string{"example.com/gen/pb", "example.com/gen/pb/internal", "example.com/lib", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/gen/pb", Imports: []string{"example.com/gen/pb/internal"},
+		Deps: []string{"example.com/gen/pb/internal"}})
+	dg.Add(DepInfo{ImportPath: "example.com/gen/pb/internal"})
+	dg.Add(DepInfo{ImportPath: "example.com/lib", Imports: []string{"C", "fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib [example.com/lib.test]", Imports: []string{"example.com/gen/pb"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Standard: true})
+
+	pruned := dg.PruneSubtree("example.com/gen/...")
+	if got := strings.Join(pruned.Packages(), " "); got != "example.com/cmd/a example.com/lib fmt" {
+		t.Fatal(got)
+	}
+	if got := strings.Join(pruned.Deps("example.com/cmd/a"), " "); got != "example.com/lib fmt" {
+		t.Error(got)
+	}
+	if got := strings.Join(pruned.Imports("example.com/lib"), " "); got != "fmt" {
+		t.Error("test-only import of a pruned package should be gone", got)
+	}
+	if !pruned.IsMainPackage("example.com/cmd/a") || !pruned.UsesCgo("example.com/lib") ||
+		pruned.Size("example.com/cmd/a").Files != 1 {
+		t.Error("package info lost")
+	}
+	if dg.Exists("example.com/gen/pb") == false {
+		t.Error("Prune should not change g")
+	}
+
+	nostd := dg.PruneStdlib()
+	if nostd.Exists("fmt") || !nostd.PathExists("example.com/cmd/a", "example.com/gen/pb/internal") {
+		t.Error("PruneStdlib error", nostd.Packages())
+	}
+}
+
+func TestPruneGraphSummarize(t *testing.T) {
+	// main imports lib, which everything else goes through; x/a, x/b and
+	// y/c are leaves of lib, target is imported by lib and x/a
+	result := map[string][]string{
+		"main": {"lib", "x/a"},
+		"lib":  {"target", "x/a", "x/b", "y/c"},
+		"x/a":  {"target"},
+	}
+	byDir := func(pkg string) string { return strings.Split(pkg, "/")[0] }
+	expect := map[string][]string{
+		"main":           {"lib", "x (2 packages)"},
+		"lib":            {"target", "x (2 packages)", "y/c"},
+		"x (2 packages)": {"target"},
+	}
+	got := PruneGraph(result, "main", "target", PruneOptions{Summarize: 3, GroupBy: byDir})
+	if !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	expect = map[string][]string{
+		"main":                {"lib", "others (3 packages)"},
+		"lib":                 {"others (3 packages)", "target"},
+		"others (3 packages)": {"target"},
+	}
+	if got := PruneGraph(result, "main", "target", PruneOptions{Summarize: 3}); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	if got := PruneGraph(result, "main", "target", PruneOptions{Summarize: 10}); !reflect.DeepEqual(got, result) {
+		t.Error("small graphs should be kept", got)
+	}
+}
@@ -0,0 +1,56 @@
+package depgraph
+
+import "strings"
+
+// AddReplace registers the replace directive old => new between module
+// paths. Packages keep the import path go list reported, which is under
+// old, but can also be looked up under new: with
+// example.com/foo => github.com/fork/foo, github.com/fork/foo/bar finds
+// example.com/foo/bar. Replacements by local directories are ignored.
+// Add registers the replacements of the modules it sees.
+func (g *DepGraph) AddReplace(old, new string) {
+	g.mustNotBeFrozen()
+	if isLocalPath(new) || old == new {
+		return
+	}
+	if g.replaces == nil {
+		g.replaces = make(map[string]string)
+	}
+	g.replaces[old] = new
+	g.changed()
+}
+
+func isLocalPath(p string) bool {
+	return p == "." || p == ".." || strings.HasPrefix(p, "./") || strings.HasPrefix(p, "../") ||
+		strings.HasPrefix(p, "/")
+}
+
+// unreplace maps packageName under a replacement module back to the
+// module it replaces.
+func (g *DepGraph) unreplace(packageName string) (string, bool) {
+	for old, new := range g.replaces {
+		if packageName == new || strings.HasPrefix(packageName, new+"/") {
+			return old + packageName[len(new):], true
+		}
+	}
+	return packageName, false
+}
+
+// Replacement returns the path of the module replacing the one
+// packageName belongs to, or "" if it isn't replaced.
+func (g *DepGraph) Replacement(packageName string) string {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return ""
+	}
+	if m := g.modules[id]; m != nil && m.Replace != nil {
+		return m.Replace.Path
+	}
+	name := g.names[id]
+	for old, new := range g.replaces {
+		if name == old || strings.HasPrefix(name, old+"/") {
+			return new
+		}
+	}
+	return ""
+}
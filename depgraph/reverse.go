@@ -0,0 +1,47 @@
+package depgraph
+
+import "sort"
+
+// The reverse indexes mirror imports and deps: importers[id] lists the
+// packages importing id directly and dependents[id] the packages whose
+// Deps contain id, both sorted. They are kept up to date by setImports and
+// setDeps so lookups by target never scan the whole graph.
+
+func insertID(ids []nodeID, id nodeID) []nodeID {
+	i := sort.Search(len(ids), func(i int) bool { return ids[i] >= id })
+	if i < len(ids) && ids[i] == id {
+		return ids
+	}
+	ids = append(ids, 0)
+	copy(ids[i+1:], ids[i:])
+	ids[i] = id
+	return ids
+}
+
+func removeID(ids []nodeID, id nodeID) []nodeID {
+	i := sort.Search(len(ids), func(i int) bool { return ids[i] >= id })
+	if i == len(ids) || ids[i] != id {
+		return ids
+	}
+	return append(ids[:i], ids[i+1:]...)
+}
+
+func (g *DepGraph) setImports(id nodeID, imports []edge) {
+	for _, e := range g.imports[id] {
+		g.importers[e.to] = removeID(g.importers[e.to], id)
+	}
+	g.imports[id] = imports
+	for _, e := range imports {
+		g.importers[e.to] = insertID(g.importers[e.to], id)
+	}
+}
+
+func (g *DepGraph) setDeps(id nodeID, deps []nodeID) {
+	for _, dep := range g.deps[id] {
+		g.dependents[dep] = removeID(g.dependents[dep], id)
+	}
+	g.deps[id] = deps
+	for _, dep := range deps {
+		g.dependents[dep] = insertID(g.dependents[dep], id)
+	}
+}
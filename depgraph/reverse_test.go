@@ -0,0 +1,29 @@
+package depgraph
+
+import "testing"
+
+func TestReverseIndex(t *testing.T) {
+	dg := loadTestGraph(t)
+	if !sliceContains(dg.Importers("net/url"), "net/http") {
+		t.Error("net/http should import net/url")
+	}
+	dg.Add(DepInfo{
+		ImportPath: "net/http",
+		Name:       "http",
+		Deps:       []string{"fmt"},
+		Imports:    []string{"fmt"},
+	})
+	if sliceContains(dg.Importers("net/url"), "net/http") {
+		t.Error("re-added net/http no longer imports net/url")
+	}
+	if sliceContains(dg.SearchAll("net/url"), "net/http") {
+		t.Error("re-added net/http no longer depends on net/url")
+	}
+	if !sliceContains(dg.SearchAll("fmt"), "net/http") {
+		t.Error("net/http should depend on fmt")
+	}
+	dg.AddEdge("x", "net/http")
+	if !sliceContains(dg.SearchAll("fmt"), "x") || !sliceContains(dg.Importers("net/http"), "x") {
+		t.Error("AddEdge should update reverse indexes")
+	}
+}
@@ -0,0 +1,224 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+)
+
+// ShardFunc returns the shard a package record belongs to.
+type ShardFunc func(d *DepInfo) string
+
+// ShardByModule shards packages by module path, the standard library
+// into "std". Packages without module information, eg: test binaries,
+// share the "" shard.
+func ShardByModule(d *DepInfo) string {
+	if d.Module != nil {
+		return d.Module.Path
+	}
+	if d.Standard {
+		return "std"
+	}
+	return ""
+}
+
+// ShardByPrefix shards packages by the first of prefixes they are under,
+// like ByPrefix. Packages under no prefix share the "" shard.
+func ShardByPrefix(prefixes []string) ShardFunc {
+	by := ByPrefix(prefixes)
+	return func(d *DepInfo) string {
+		base, _ := testVariantBase(d.ImportPath)
+		return by(base)
+	}
+}
+
+// ShardedGraph is a read-only graph for dumps too large for one DepGraph:
+// the packages are partitioned into shards, each a frozen DepGraph loaded
+// in its own goroutine. A shard keeps the imports of its packages, also
+// those leaving the shard, but only the Deps within it, which is where
+// most of the memory of a DepGraph goes. Imports entering a shard are
+// kept in a cross-shard importer table. Queries crossing shards walk the
+// imports.
+type ShardedGraph struct {
+	shards map[string]*DepGraph
+	home   map[string]*DepGraph // package -> shard
+	cross  map[string][]string  // package -> importers in other shards, sorted
+}
+
+var _ Graph = (*ShardedGraph)(nil)
+
+// LoadSharded loads the go list -json output in r into a ShardedGraph,
+// sharded by shard. Records are decoded in order and added to their
+// shard concurrently. Deps in other shards are only dropped if their
+// record came first, as go list -deps prints them.
+func LoadSharded(r io.Reader, shard ShardFunc) (*ShardedGraph, error) {
+	var (
+		wg     sync.WaitGroup
+		graphs = make(map[string]*DepGraph)
+		queues = make(map[string]chan DepInfo)
+		keys   = make(map[string]string) // package -> shard name
+	)
+	dec := json.NewDecoder(r)
+	var err error
+	for {
+		var d DepInfo
+		if err = dec.Decode(&d); err != nil {
+			break
+		}
+		name := shard(&d)
+		base, _ := testVariantBase(d.ImportPath)
+		if _, ok := keys[base]; !ok {
+			keys[base] = name
+		}
+		queue := queues[name]
+		if queue == nil {
+			g := &DepGraph{}
+			queue = make(chan DepInfo, 64)
+			graphs[name], queues[name] = g, queue
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for d := range queue {
+					g.Add(d)
+				}
+			}()
+		}
+		var deps []string
+		for _, dep := range d.Deps {
+			if n, ok := keys[dep]; !ok || n == name {
+				deps = append(deps, dep)
+			}
+		}
+		d.Deps = deps
+		queue <- d
+	}
+	for _, queue := range queues {
+		close(queue)
+	}
+	wg.Wait()
+	if err != io.EOF {
+		return nil, err
+	}
+	s := &ShardedGraph{shards: make(map[string]*DepGraph, len(graphs)), home: make(map[string]*DepGraph),
+		cross: make(map[string][]string)}
+	var mu sync.Mutex
+	for name, g := range graphs {
+		wg.Add(1)
+		go func(name string, g *DepGraph) {
+			defer wg.Done()
+			f := g.Freeze()
+			mu.Lock()
+			s.shards[name] = f
+			mu.Unlock()
+		}(name, g)
+	}
+	wg.Wait()
+	for _, g := range s.shards {
+		for _, p := range g.Packages() {
+			s.home[p] = g
+		}
+	}
+	for _, g := range s.shards {
+		for _, p := range g.Packages() {
+			for _, to := range g.Imports(p) {
+				if home := s.home[to]; home != nil && home != g {
+					s.cross[to] = append(s.cross[to], p)
+				}
+			}
+		}
+	}
+	for _, importers := range s.cross {
+		sort.Strings(importers)
+	}
+	return s, nil
+}
+
+// Shards returns the shard names, sorted.
+func (s *ShardedGraph) Shards() []string {
+	names := make([]string, 0, len(s.shards))
+	for name := range s.shards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shard returns the frozen graph of shard name, nil if there is none.
+func (s *ShardedGraph) Shard(name string) *DepGraph {
+	return s.shards[name]
+}
+
+// AddEdge panics: a ShardedGraph is read-only.
+func (s *ShardedGraph) AddEdge(from, to string) {
+	panic("depgraph: ShardedGraph is read-only")
+}
+
+func (s *ShardedGraph) Imports(pkg string) []string {
+	if g := s.home[pkg]; g != nil {
+		return g.Imports(pkg)
+	}
+	return nil
+}
+
+func (s *ShardedGraph) Importers(pkg string) []string {
+	g := s.home[pkg]
+	if g == nil {
+		return nil
+	}
+	importers := append(g.Importers(pkg), s.cross[pkg]...)
+	sort.Strings(importers)
+	return importers
+}
+
+func (s *ShardedGraph) Has(pkg string) bool {
+	return s.home[pkg] != nil
+}
+
+// CountAll returns the number of loaded packages.
+func (s *ShardedGraph) CountAll() int {
+	return len(s.home)
+}
+
+func (s *ShardedGraph) IsMainPackage(pkg string) bool {
+	g := s.home[pkg]
+	return g != nil && g.IsMainPackage(pkg)
+}
+
+func (s *ShardedGraph) IsTestPackage(pkg string) bool {
+	g := s.home[pkg]
+	return g != nil && g.IsTestPackage(pkg)
+}
+
+// Module returns the module of pkg, see DepGraph.Module.
+func (s *ShardedGraph) Module(pkg string) *Module {
+	if g := s.home[pkg]; g != nil {
+		return g.Module(pkg)
+	}
+	return nil
+}
+
+// SearchAll returns every package transitively importing packageName,
+// sorted, like DepGraph.Ancestors.
+func (s *ShardedGraph) SearchAll(packageName string) (packages []string) {
+	for p := range ReachableTo(s, packageName) {
+		packages = append(packages, p)
+	}
+	sort.Strings(packages)
+	return
+}
+
+// SearchMain returns the main packages transitively importing
+// packageName, or packageName itself if it is one, sorted.
+func (s *ShardedGraph) SearchMain(packageName string) (packages []string) {
+	if s.IsMainPackage(packageName) {
+		packages = append(packages, packageName)
+	}
+	for _, p := range s.SearchAll(packageName) {
+		if s.IsMainPackage(p) {
+			packages = append(packages, p)
+		}
+	}
+	sort.Strings(packages)
+	return
+}
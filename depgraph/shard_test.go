@@ -0,0 +1,74 @@
+package depgraph
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLoadSharded(t *testing.T) {
+	f, err := os.Open("testdata/go1.12.5_deps.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	s, err := LoadSharded(f, ShardByPrefix([]string{"cmd/...", "net/...", "crypto"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(s.Shards(), " "); got != " cmd crypto net" {
+		t.Fatal(got)
+	}
+	dg := loadTestGraph(t)
+	if s.CountAll() != len(dg.Packages()) {
+		t.Error(s.CountAll(), len(dg.Packages()))
+	}
+	for _, p := range []string{"fmt", "net/url", "crypto/tls", "cmd/vet"} {
+		ancestors := make([]string, 0)
+		for a := range dg.Ancestors(p) {
+			ancestors = append(ancestors, a)
+		}
+		sort.Strings(ancestors)
+		if got := s.SearchAll(p); strings.Join(got, " ") != strings.Join(ancestors, " ") {
+			t.Error("SearchAll differs", p, len(got), len(ancestors))
+		}
+		mains := dg.SearchMain(p)
+		sort.Strings(mains)
+		if got := s.SearchMain(p); !reflect.DeepEqual(got, mains) {
+			t.Error("SearchMain differs", p, got, mains)
+		}
+		if got := s.Importers(p); strings.Join(got, " ") != strings.Join(dg.Importers(p), " ") {
+			t.Error("Importers differs", p, got, dg.Importers(p))
+		}
+	}
+}
+
+func TestShardByModule(t *testing.T) {
+	input := `{"ImportPath": "fmt", "Standard": true}
+{"ImportPath": "example.com/lib", "Module": {"Path": "example.com/lib"}, "Imports": ["fmt"], "Deps": ["fmt"]}
+{"ImportPath": "example.com/app", "Name": "main", "Module": {"Path": "example.com/app"}, "Imports": ["example.com/lib"], "Deps": ["example.com/lib", "fmt"]}`
+	s, err := LoadSharded(strings.NewReader(input), ShardByModule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(s.Shards(), " "); got != "example.com/app example.com/lib std" {
+		t.Fatal(got)
+	}
+	if got := s.SearchMain("fmt"); !reflect.DeepEqual(got, []string{"example.com/app"}) {
+		t.Error(got)
+	}
+	if got := s.Importers("example.com/lib"); !reflect.DeepEqual(got, []string{"example.com/app"}) {
+		t.Error(got)
+	}
+	if got := s.Shard("example.com/app").Deps("example.com/app"); len(got) != 0 {
+		t.Error("deps should stay within the shard", got)
+	}
+	if m := s.Module("example.com/lib"); m == nil || m.Path != "example.com/lib" {
+		t.Error(m)
+	}
+	if _, err := LoadSharded(strings.NewReader("{"), ShardByModule); err == nil {
+		t.Error("expect error for truncated input")
+	}
+}
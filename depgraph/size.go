@@ -0,0 +1,97 @@
+package depgraph
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+)
+
+// Size is the amount of source code of one or more packages.
+type Size struct {
+	Files int // GoFiles
+	Lines int // lines of GoFiles, 0 unless counted, see CountLines
+}
+
+// Weight returns the lines of code if they were counted, else the files.
+func (s Size) Weight() int {
+	if s.Lines > 0 {
+		return s.Lines
+	}
+	return s.Files
+}
+
+// Unit names what Weight counts.
+func (s Size) Unit() string {
+	if s.Lines > 0 {
+		return "lines"
+	}
+	return "files"
+}
+
+func (s *Size) add(other Size) {
+	s.Files += other.Files
+	s.Lines += other.Lines
+}
+
+// CountLines makes Add count the lines of the GoFiles of every package
+// whose record doesn't carry Lines, reading them from Dir. It only works
+// on the machine go list ran on; unreadable files are left out.
+func (g *DepGraph) CountLines(count bool) {
+	g.countLines = count
+}
+
+// countLines returns the number of lines of files in dir.
+func countLines(dir string, files []string) (n int) {
+	for _, name := range files {
+		f, err := os.Open(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		sc := bufio.NewScanner(f)
+		sc.Buffer(nil, 1<<20)
+		sc.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+			if i := bytes.IndexByte(data, '\n'); i >= 0 {
+				return i + 1, data[:i], nil
+			}
+			if atEOF && len(data) > 0 {
+				return len(data), data, nil
+			}
+			return 0, nil, nil
+		})
+		for sc.Scan() {
+			n++
+		}
+		f.Close()
+	}
+	return
+}
+
+// Size returns the source size of packageName alone.
+func (g *DepGraph) Size(packageName string) Size {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return Size{}
+	}
+	return g.sizes[id]
+}
+
+// TransitiveSize returns the source size of packageName and everything it
+// depends on: what it weighs on a binary importing it.
+func (g *DepGraph) TransitiveSize(packageName string) Size {
+	id, ok := g.lookup(packageName)
+	if !ok {
+		return Size{}
+	}
+	s := g.sizes[id]
+	g.eachDep(id, func(dep nodeID) { s.add(g.sizes[dep]) })
+	return s
+}
+
+// TotalSize returns the source size of all loaded packages.
+func (g *DepGraph) TotalSize() (s Size) {
+	for _, size := range g.sizes {
+		s.add(size)
+	}
+	return
+}
@@ -0,0 +1,38 @@
+package depgraph
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSize(t *testing.T) {
+	dg := loadTestGraph(t)
+	if s := dg.Size("fmt"); s != (Size{Files: 4}) || s.Weight() != 4 || s.Unit() != "files" {
+		t.Error("size error", s)
+	}
+	if s := dg.Freeze().TransitiveSize("fmt"); s.Files != 310 {
+		t.Error("transitive size error", s)
+	}
+	if s := dg.TotalSize(); s.Files != 1636 {
+		t.Error("total size error", s)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n\nfunc A() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.go"), []byte("package a\n// no newline at the end"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	counted := &DepGraph{}
+	counted.CountLines(true)
+	counted.Add(DepInfo{ImportPath: "a", Name: "a", Dir: dir, GoFiles: []string{"a.go", "b.go", "gone.go"}})
+	counted.Add(DepInfo{ImportPath: "b", Name: "b", Dir: dir, GoFiles: []string{"a.go"}, Lines: 100})
+	if s := counted.Size("a"); s != (Size{Files: 3, Lines: 5}) || s.Unit() != "lines" {
+		t.Error("counted size error", s)
+	}
+	if s := counted.Size("b"); s.Lines != 100 {
+		t.Error("Lines from the record should be kept", s)
+	}
+}
@@ -0,0 +1,36 @@
+package depgraph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// StableID returns the identifier of package path at module version: the
+// first 8 bytes of the SHA-256 of "path@version", in hex. It only depends
+// on its arguments, so the same package gets the same identifier in every
+// graph, snapshot and repository, letting external systems correlate them.
+func StableID(path, version string) string {
+	sum := sha256.Sum256([]byte(path + "@" + version))
+	return hex.EncodeToString(sum[:8])
+}
+
+// StableID returns the StableID of packageName at the version of its
+// module. Packages of main modules and of the standard library, which
+// have no version, are identified by their path alone, so they keep their
+// identifier from one snapshot to the next.
+func (g *DepGraph) StableID(packageName string) string {
+	version := ""
+	if m := g.Module(packageName); m != nil && !m.Main {
+		version = m.Version
+	}
+	return StableID(packageName, version)
+}
+
+// StableIDs returns the StableID of every loaded package.
+func (g *DepGraph) StableIDs() map[string]string {
+	ids := make(map[string]string)
+	for _, p := range g.Packages() {
+		ids[p] = g.StableID(p)
+	}
+	return ids
+}
@@ -0,0 +1,28 @@
+package depgraph
+
+import "testing"
+
+func TestStableID(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Imports: []string{"golang.org/x/text"},
+		Deps: []string{"golang.org/x/text"}, Module: &Module{Path: "example.com/app", Version: "v1.2.0", Main: true}})
+	dg.Add(DepInfo{ImportPath: "golang.org/x/text", Module: &Module{Path: "golang.org/x/text", Version: "v0.3.7"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Standard: true})
+
+	if id := dg.StableID("golang.org/x/text"); id != StableID("golang.org/x/text", "v0.3.7") || len(id) != 16 {
+		t.Error(id)
+	}
+	if dg.StableID("example.com/app/cmd/a") != StableID("example.com/app/cmd/a", "") {
+		t.Error("main module packages should be identified by path")
+	}
+	if StableID("golang.org/x/text", "v0.3.7") == StableID("golang.org/x/text", "v0.3.8") {
+		t.Error("versions should have distinct IDs")
+	}
+	ids := dg.StableIDs()
+	if len(ids) != 3 || ids["fmt"] != StableID("fmt", "") {
+		t.Error(ids)
+	}
+	if dg.Freeze().StableID("golang.org/x/text") != ids["golang.org/x/text"] {
+		t.Error("frozen graph should give the same IDs")
+	}
+}
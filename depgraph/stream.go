@@ -0,0 +1,74 @@
+package depgraph
+
+// The Stream variants send results on a channel as soon as they are found,
+// so callers can start emitting output before the whole result set is
+// materialized. The channel is closed once the search is finished or done
+// is closed. The graph must not be modified while a stream is running.
+
+func (g *DepGraph) SearchAllStream(packageName string, done <-chan struct{}) <-chan string {
+	ch := make(chan string)
+	g.prepare()
+	go func() {
+		defer close(ch)
+		target, ok := g.lookup(packageName)
+		if !ok {
+			return
+		}
+		for _, id := range g.dependentsOf(target) {
+			select {
+			case ch <- g.names[id]:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (g *DepGraph) SearchMainStream(packageName string, done <-chan struct{}) <-chan string {
+	return g.filterStream(g.mainPackages, packageName, true, done)
+}
+
+func (g *DepGraph) SearchTestStream(packageName string, done <-chan struct{}) <-chan string {
+	return g.filterStream(g.testPackages, packageName, false, done)
+}
+
+func (g *DepGraph) filterStream(roots map[nodeID]bool, packageName string, includeSelf bool,
+	done <-chan struct{}) <-chan string {
+	ch := make(chan string)
+	g.prepare()
+	go func() {
+		defer close(ch)
+		target, ok := g.lookup(packageName)
+		if !ok {
+			return
+		}
+		for v := range roots {
+			if !g.dependsOn(v, target) && !(includeSelf && v == target) {
+				continue
+			}
+			select {
+			case ch <- g.names[v]:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func (g *DepGraph) SearchChainStream(packageName string, done <-chan struct{}) <-chan []string {
+	ch := make(chan []string)
+	go func() {
+		defer close(ch)
+		deadEnds := make(map[nodeID]bool)
+		for p := range g.SearchMainStream(packageName, done) {
+			select {
+			case ch <- g.chainFrom(p, packageName, deadEnds):
+			case <-done:
+				return
+			}
+		}
+	}()
+	return ch
+}
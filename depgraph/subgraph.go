@@ -0,0 +1,55 @@
+package depgraph
+
+import "sort"
+
+// SubgraphBetween returns the graph of the packages lying on some import
+// path from from to to, both included, with the imports between them, so
+// exports and visualizations show exactly how from reaches to. Deps are
+// cut down to the packages of the subgraph; package flags, modules, sizes
+// and edge attributes are kept. Test-only imports are ignored like in
+// SearchGraph. The result is empty if from does not import to.
+func (g *DepGraph) SubgraphBetween(from, to string) *DepGraph {
+	sub := &DepGraph{build: g.build}
+	edges := g.SearchGraph(from, to)
+	if len(edges) == 0 && !(from == to && g.Exists(from)) {
+		return sub
+	}
+	keep := map[string]bool{from: true, to: true}
+	for p, imports := range edges {
+		keep[p] = true
+		for _, imp := range imports {
+			keep[imp] = true
+		}
+	}
+	packages := make([]string, 0, len(keep))
+	for p := range keep {
+		packages = append(packages, p)
+	}
+	sort.Strings(packages)
+	for _, p := range packages {
+		id, _ := g.lookup(p)
+		info := DepInfo{ImportPath: p, Name: g.pkgNames[id], Standard: g.isStandard(id),
+			Module: g.modules[id], Dir: g.dirs[id], EmbedFiles: g.embeds[id], Imports: edges[p]}
+		if g.mainPackages[id] || g.testPackages[id] {
+			info.Name = "main"
+		}
+		for _, dep := range g.Deps(p) {
+			if keep[dep] {
+				info.Deps = append(info.Deps, dep)
+			}
+		}
+		sub.Add(info)
+		g.copyMainFlags(sub, id, sub.ids[p])
+		sub.sizes[sub.ids[p]] = g.sizes[id]
+	}
+	for p, imports := range edges {
+		for _, imp := range imports {
+			if attrs := g.Edge(p, imp); attrs != nil {
+				sub.SetEdge(p, imp, *attrs)
+			}
+		}
+	}
+	sub.sawStandard = g.sawStandard
+	sub.firstParty = g.firstParty
+	return sub
+}
@@ -0,0 +1,36 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubgraphBetween(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"x", "y", "z"}, Deps: []string{"w", "x", "y", "z"}})
+	dg.Add(DepInfo{ImportPath: "x", Imports: []string{"w"}, Deps: []string{"w"}, GoFiles: []string{"x.go"}})
+	dg.Add(DepInfo{ImportPath: "y", Imports: []string{"x"}, Deps: []string{"w", "x"}})
+	dg.Add(DepInfo{ImportPath: "z"})
+	dg.Add(DepInfo{ImportPath: "w", Standard: true})
+	dg.SetEdge("y", "x", EdgeAttrs{Vendored: true})
+
+	sub := dg.SubgraphBetween("cmd/a", "x")
+	if got := sub.Packages(); !reflect.DeepEqual(got, []string{"cmd/a", "x", "y"}) {
+		t.Fatal(got)
+	}
+	if got := sub.Imports("cmd/a"); !reflect.DeepEqual(got, []string{"x", "y"}) {
+		t.Error(got)
+	}
+	if got := sub.Deps("y"); !reflect.DeepEqual(got, []string{"x"}) {
+		t.Error(got)
+	}
+	if !sub.IsMainPackage("cmd/a") || sub.Size("x").Files != 1 {
+		t.Error("package info lost")
+	}
+	if e := sub.Edge("y", "x"); e == nil || !e.Vendored {
+		t.Error("edge attributes lost", e)
+	}
+	if got := dg.SubgraphBetween("z", "x").Packages(); len(got) != 0 {
+		t.Error(got)
+	}
+}
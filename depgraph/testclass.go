@@ -0,0 +1,87 @@
+package depgraph
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// TestClassifier tells whether the main package record d is a test
+// binary rather than a command, see SetTestClassifier.
+type TestClassifier func(d *DepInfo) bool
+
+// TestBySuffix classifies the packages named like go list -test names
+// test binaries, eg: "p.test". It is the default.
+func TestBySuffix(d *DepInfo) bool {
+	return strings.HasSuffix(d.ImportPath, ".test")
+}
+
+// TestByForTest classifies the packages built for a test: records with
+// the ForTest field set, and packages importing a test variant like the
+// test mains of go list -test do. Build layouts naming test binaries
+// freely are classified right as long as they fill ForTest.
+func TestByForTest(d *DepInfo) bool {
+	if d.ForTest != "" {
+		return true
+	}
+	for _, p := range d.Imports {
+		if _, ok := testVariantBase(p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TestByRegexp classifies the packages whose import path matches re.
+func TestByRegexp(re *regexp.Regexp) TestClassifier {
+	return func(d *DepInfo) bool {
+		return re.MatchString(d.ImportPath)
+	}
+}
+
+// ParseTestClassifier returns the classifier named by s: "suffix",
+// "fortest" or "regexp:RE".
+func ParseTestClassifier(s string) (TestClassifier, error) {
+	switch {
+	case s == "suffix":
+		return TestBySuffix, nil
+	case s == "fortest":
+		return TestByForTest, nil
+	case strings.HasPrefix(s, "regexp:"):
+		re, err := regexp.Compile(strings.TrimPrefix(s, "regexp:"))
+		if err != nil {
+			return nil, err
+		}
+		return TestByRegexp(re), nil
+	}
+	return nil, fmt.Errorf("unknown test classifier %q, want suffix, fortest or regexp:RE", s)
+}
+
+// SetTestClassifier sets how Add tells test binaries from the other main
+// packages, TestBySuffix if c is nil. It must be set before packages are
+// added; IsTestPackage, CountTest and SearchTest report the result.
+func (g *DepGraph) SetTestClassifier(c TestClassifier) {
+	g.testClassifier = c
+}
+
+// isTestBinary classifies the main package record d.
+func (g *DepGraph) isTestBinary(d *DepInfo) bool {
+	if g.testClassifier == nil {
+		return TestBySuffix(d)
+	}
+	return g.testClassifier(d)
+}
+
+// copyMainFlags gives the package id of copy, rebuilt from the package
+// from of g, the main and test flags of from, whatever the classifier of
+// copy makes of the rebuilt record.
+func (g *DepGraph) copyMainFlags(copy *DepGraph, from, id nodeID) {
+	delete(copy.mainPackages, id)
+	delete(copy.testPackages, id)
+	if g.mainPackages[from] {
+		copy.mainPackages[id] = true
+	}
+	if g.testPackages[from] {
+		copy.testPackages[id] = true
+	}
+}
@@ -0,0 +1,55 @@
+package depgraph
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestTestClassifier(t *testing.T) {
+	records := []DepInfo{
+		{ImportPath: "cmd/a", Name: "main", Imports: []string{"lib"}},
+		{ImportPath: "lib.test", Name: "main", Imports: []string{"lib [lib.test]"}},
+		{ImportPath: "lib [lib.test]", Name: "lib", ForTest: "lib"},
+		{ImportPath: "lib_test_bin", Name: "main", Imports: []string{"lib"}, ForTest: "lib"},
+		{ImportPath: "lib", Name: "lib"},
+	}
+	for _, c := range []struct {
+		name  string
+		class TestClassifier
+		tests []string
+	}{
+		{"default", nil, []string{"lib.test"}},
+		{"fortest", TestByForTest, []string{"lib.test", "lib_test_bin"}},
+		{"regexp", TestByRegexp(regexp.MustCompile(`_bin$`)), []string{"lib_test_bin"}},
+	} {
+		dg := &DepGraph{}
+		dg.SetTestClassifier(c.class)
+		for _, d := range records {
+			dg.Add(d)
+		}
+		if dg.CountTest() != len(c.tests) || dg.CountMain()+dg.CountTest() != 3 {
+			t.Errorf("%v: %v tests, %v mains", c.name, dg.CountTest(), dg.CountMain())
+		}
+		for _, p := range c.tests {
+			if !dg.IsTestPackage(p) || dg.IsMainPackage(p) {
+				t.Errorf("%v: %v not a test binary", c.name, p)
+			}
+		}
+		if p := dg.Prune(func(string) bool { return false }); p.CountTest() != len(c.tests) || p.CountMain() != dg.CountMain() {
+			t.Errorf("%v: Prune reclassified the main packages", c.name)
+		}
+	}
+}
+
+func TestParseTestClassifier(t *testing.T) {
+	for _, s := range []string{"suffix", "fortest", "regexp:^x"} {
+		if _, err := ParseTestClassifier(s); err != nil {
+			t.Error(s, err)
+		}
+	}
+	for _, s := range []string{"", "name", "regexp:("} {
+		if _, err := ParseTestClassifier(s); err == nil {
+			t.Error(s, "accepted")
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package depgraph
+
+import (
+	"sort"
+	"strings"
+)
+
+// testWalk returns the shortest import chain parents of the packages the
+// test binary bin, eg: "p.test" from go list -test, builds: only p, the
+// package under test, has its test-only imports followed.
+func (g *DepGraph) testWalk(bin nodeID) map[nodeID]nodeID {
+	tested, _ := g.lookup(strings.TrimSuffix(g.names[bin], ".test"))
+	parent := map[nodeID]nodeID{bin: -1}
+	queue := []nodeID{bin}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for _, e := range g.imports[from] {
+			if !e.inBuild() && from != tested {
+				continue
+			}
+			if _, ok := parent[e.to]; !ok {
+				parent[e.to] = from
+				queue = append(queue, e.to)
+			}
+		}
+	}
+	return parent
+}
+
+// TestChains returns, for every test binary of go list -test exercising
+// packageName, the shortest import chain p.test -> p -> ... -> packageName,
+// sorted by binary.
+func (g *DepGraph) TestChains(packageName string) (chains [][]string) {
+	target, ok := g.lookup(packageName)
+	if !ok {
+		return
+	}
+	for bin := range g.testPackages {
+		parent := g.testWalk(bin)
+		if _, ok := parent[target]; !ok || bin == target {
+			continue
+		}
+		var chain []string
+		for id := target; id >= 0; id = parent[id] {
+			chain = append(chain, g.names[id])
+		}
+		reverseSlice(chain)
+		chains = append(chains, chain)
+	}
+	sort.Slice(chains, func(i, j int) bool { return chains[i][0] < chains[j][0] })
+	return
+}
+
+// Untested returns the packages no test binary exercises, standard
+// library and test binaries left out, sorted. It is only meaningful for
+// go list -test output.
+func (g *DepGraph) Untested() (packages []string) {
+	tested := make(map[nodeID]bool)
+	for bin := range g.testPackages {
+		for id := range g.testWalk(bin) {
+			tested[id] = true
+		}
+	}
+	for id, loaded := range g.loaded {
+		id := nodeID(id)
+		if loaded && !tested[id] && !g.testPackages[id] && !g.isStandard(id) {
+			packages = append(packages, g.names[id])
+		}
+	}
+	sort.Strings(packages)
+	return
+}
@@ -0,0 +1,82 @@
+package depgraph
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestTestChains(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "lib [lib.test]", Name: "lib",
+		Imports: []string{"fmt", "testhelper"}, Deps: []string{"fmt", "testhelper"}})
+	dg.Add(DepInfo{ImportPath: "lib_test [lib.test]", Name: "lib_test",
+		Imports: []string{"lib [lib.test]", "mock"}, Deps: []string{"fmt", "lib [lib.test]", "mock", "testhelper"}})
+	dg.Add(DepInfo{ImportPath: "lib.test", Name: "main", Imports: []string{"lib [lib.test]", "lib_test [lib.test]", "testing"},
+		Deps: []string{"fmt", "lib [lib.test]", "lib_test [lib.test]", "mock", "testhelper", "testing"}})
+	dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "mock", Name: "mock", Imports: []string{"unused"}, Deps: []string{"unused"}})
+	dg.Add(DepInfo{ImportPath: "mock [mock.test]", Name: "mock", Imports: []string{"other"}, Deps: []string{"other"}})
+	dg.Add(DepInfo{ImportPath: "cmd/tool", Name: "main", Imports: []string{"lib"}, Deps: []string{"fmt", "lib"}})
+	for _, p := range []string{"testhelper", "fmt", "testing", "unused", "other"} {
+		dg.Add(DepInfo{ImportPath: p, Name: p, Standard: p == "fmt" || p == "testing"})
+	}
+
+	for p, expect := range map[string]string{
+		"lib":        "lib.test lib",
+		"fmt":        "lib.test lib fmt",
+		"testhelper": "lib.test lib testhelper",
+		"unused":     "lib.test lib mock unused",
+		"other":      "",
+		"cmd/tool":   "",
+	} {
+		var got []string
+		for _, chain := range dg.TestChains(p) {
+			got = append(got, strings.Join(chain, " "))
+		}
+		if strings.Join(got, ",") != expect {
+			t.Error(p, got)
+		}
+	}
+	if tests := dg.SearchTest("testhelper"); len(tests) != 1 || tests[0] != "lib.test" {
+		t.Error(tests)
+	}
+	// the test-only imports of mock are not part of lib.test
+	if untested := strings.Join(dg.Untested(), " "); untested != "cmd/tool other" {
+		t.Error(untested)
+	}
+}
+
+func TestSearchTestStream(t *testing.T) {
+	for _, ignoreDeps := range []bool{false, true} {
+		dg := &DepGraph{}
+		dg.IgnoreDeps(ignoreDeps)
+		dg.Add(DepInfo{ImportPath: "lib [lib.test]", Name: "lib",
+			Imports: []string{"fmt", "testhelper"}, Deps: []string{"fmt", "testhelper"}})
+		dg.Add(DepInfo{ImportPath: "lib.test", Name: "main", Imports: []string{"lib [lib.test]", "testing"},
+			Deps: []string{"fmt", "lib [lib.test]", "testhelper", "testing"}})
+		dg.Add(DepInfo{ImportPath: "util.test", Name: "main", Imports: []string{"util", "testing"},
+			Deps: []string{"fmt", "testing", "util"}})
+		dg.Add(DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+		dg.Add(DepInfo{ImportPath: "util", Name: "util", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+		for _, p := range []string{"testhelper", "fmt", "testing"} {
+			dg.Add(DepInfo{ImportPath: p, Name: p, Standard: p != "testhelper"})
+		}
+
+		for _, p := range append(dg.Packages(), "missing") {
+			got := dg.SearchTest(p)
+			var stream []string
+			for v := range dg.SearchTestStream(p, nil) {
+				stream = append(stream, v)
+			}
+			sort.Strings(got)
+			sort.Strings(stream)
+			if strings.Join(got, " ") != strings.Join(stream, " ") {
+				t.Error(ignoreDeps, p, got, stream)
+			}
+		}
+		if tests := strings.Join(dg.SearchTest("fmt"), " "); tests != "lib.test util.test" && tests != "util.test lib.test" {
+			t.Error(ignoreDeps, tests)
+		}
+	}
+}
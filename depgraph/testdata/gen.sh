@@ -1,3 +1,6 @@
 root@b7e158d83ff2:/go# go version
 go version go1.12.5 linux/amd64
 root@b7e158d83ff2:/usr/local/go/src# go list -json all > ~/go1.12.5_deps.json
+~/fix$ go version
+go version go1.27.1 linux/amd64
+~/fix$ go list -json -deps -test ./... > ~/test_deps.json # trimmed to ImportPath, Name, Standard, ForTest, Module, ImportMap, Imports and Deps
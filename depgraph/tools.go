@@ -0,0 +1,77 @@
+package depgraph
+
+import "sort"
+
+// AddTool marks packageName as a tool: a main package the module only
+// needs to run, eg: listed by a tool directive of go.mod or blank
+// imported by a tools.go file, see DetectTools.
+func (g *DepGraph) AddTool(packageName string) {
+	g.mustNotBeFrozen()
+	if g.tools == nil {
+		g.tools = make(map[string]bool)
+	}
+	g.tools[packageName] = true
+	g.changed()
+}
+
+// IsTool reports whether packageName was marked with AddTool.
+func (g *DepGraph) IsTool(packageName string) bool {
+	return g.tools[packageName]
+}
+
+// DetectTools marks the packages following the tools.go pattern, loaded
+// with -tags tools: a package named tools nobody imports, and the
+// packages it imports, as tools. It returns the tools packages found,
+// sorted.
+func (g *DepGraph) DetectTools() (found []string) {
+	for _, p := range g.Packages() {
+		id, _ := g.lookup(p)
+		if g.pkgNames[id] != "tools" || g.mainPackages[id] || len(g.loadedOf(g.importers[id])) > 0 {
+			continue
+		}
+		found = append(found, p)
+		g.AddTool(p)
+		for _, imp := range g.Imports(p) {
+			g.AddTool(imp)
+		}
+	}
+	return
+}
+
+// ToolOnly returns, sorted, the tools and the packages only tools bring
+// in: those no package other than a tool or one of them imports.
+func (g *DepGraph) ToolOnly() (packages []string) {
+	only := make(map[nodeID]bool)
+	for p := range g.tools {
+		id, ok := g.lookup(p)
+		if !ok {
+			continue
+		}
+		only[id] = true
+		for _, dep := range g.Deps(p) {
+			depID, _ := g.lookup(dep)
+			only[depID] = true
+		}
+	}
+	// drop the deps some other package imports, until none is left
+	for changed := true; changed; {
+		changed = false
+		for id := range only {
+			if g.tools[g.names[id]] {
+				continue
+			}
+			for _, importer := range g.importers[id] {
+				if e := g.edgeTo(importer, id); g.loaded[importer] && !only[importer] && e != nil && !e.TestOnly {
+					delete(only, id)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	for id := range only {
+		packages = append(packages, g.names[id])
+	}
+	sort.Strings(packages)
+	return
+}
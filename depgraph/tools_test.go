@@ -0,0 +1,36 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTools(t *testing.T) {
+	dg := &DepGraph{}
+	// tools.go loaded with -tags tools
+	dg.Add(DepInfo{ImportPath: "app/tools", Name: "tools", Imports: []string{"x/stringer"},
+		Deps: []string{"fmt", "x/stringer", "x/typeutil"}})
+	dg.Add(DepInfo{ImportPath: "x/stringer", Name: "main", Imports: []string{"fmt", "x/typeutil"}, Deps: []string{"fmt", "x/typeutil"}})
+	dg.Add(DepInfo{ImportPath: "x/typeutil", Name: "typeutil"})
+	dg.Add(DepInfo{ImportPath: "y/lint", Name: "main", Imports: []string{"y/rules"}, Deps: []string{"y/rules"}})
+	dg.Add(DepInfo{ImportPath: "y/rules", Name: "rules", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "app/cmd/api", Name: "main", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Name: "fmt", Standard: true})
+
+	if found := dg.DetectTools(); !reflect.DeepEqual(found, []string{"app/tools"}) {
+		t.Error(found)
+	}
+	// tool directive of go.mod
+	dg.AddTool("y/lint")
+	if !dg.IsTool("x/stringer") || !dg.IsTool("y/lint") || dg.IsTool("app/cmd/api") {
+		t.Error("tools error")
+	}
+	// fmt is used by app/cmd/api too
+	expect := []string{"app/tools", "x/stringer", "x/typeutil", "y/lint", "y/rules"}
+	if got := dg.ToolOnly(); !reflect.DeepEqual(got, expect) {
+		t.Error(got)
+	}
+	if got := dg.Freeze().ToolOnly(); !reflect.DeepEqual(got, expect) {
+		t.Error("frozen", got)
+	}
+}
@@ -0,0 +1,41 @@
+package depgraph
+
+import "sort"
+
+// Popularity is how many packages depend on Package.
+type Popularity struct {
+	Package    string
+	Dependents int // packages depending on Package directly or indirectly
+}
+
+// MostDepended ranks the non-standard packages by number of transitive
+// dependents, most depended on first, split into first-party packages,
+// see FirstParty, and third-party ones. First-party packages
+// on top of the list are de-facto platform APIs of the repository.
+func (g *DepGraph) MostDepended() (firstParty, thirdParty []Popularity) {
+	g.prepare()
+	for id, loaded := range g.loaded {
+		id := nodeID(id)
+		if !loaded || g.isStandard(id) {
+			continue
+		}
+		p := Popularity{Package: g.names[id], Dependents: len(g.dependentsOf(id))}
+		if g.isFirstParty(id) {
+			firstParty = append(firstParty, p)
+		} else {
+			thirdParty = append(thirdParty, p)
+		}
+	}
+	sortPopularity(firstParty)
+	sortPopularity(thirdParty)
+	return
+}
+
+func sortPopularity(list []Popularity) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Dependents != list[j].Dependents {
+			return list[i].Dependents > list[j].Dependents
+		}
+		return list[i].Package < list[j].Package
+	})
+}
@@ -0,0 +1,30 @@
+package depgraph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMostDepended(t *testing.T) {
+	dg := &DepGraph{}
+	app := &Module{Path: "example.com/app", Main: true}
+	lib := &Module{Path: "example.com/lib"}
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main", Module: app,
+		Deps: []string{"example.com/app/log", "example.com/lib", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/cmd/b", Name: "main", Module: app,
+		Deps: []string{"example.com/app/log", "example.com/app/db", "example.com/lib", "fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/db", Module: app, Deps: []string{"example.com/app/log", "example.com/lib"}})
+	dg.Add(DepInfo{ImportPath: "example.com/app/log", Module: app, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "example.com/lib", Module: lib})
+	dg.Add(DepInfo{ImportPath: "fmt", Standard: true})
+
+	first, third := dg.MostDepended()
+	expect := []Popularity{{"example.com/app/log", 3}, {"example.com/app/db", 1},
+		{"example.com/app/cmd/a", 0}, {"example.com/app/cmd/b", 0}}
+	if !reflect.DeepEqual(first, expect) {
+		t.Error(first)
+	}
+	if !reflect.DeepEqual(third, []Popularity{{"example.com/lib", 3}}) {
+		t.Error(third)
+	}
+}
@@ -0,0 +1,172 @@
+package depgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity tells how much an Issue of Validate matters.
+type Severity string
+
+const (
+	// SeverityError is input queries give wrong results on.
+	SeverityError Severity = "error"
+	// SeverityWarning is input worth a look, queries may be degraded.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem of the input found by Validate.
+type Issue struct {
+	Severity Severity
+	Check    string // dangling, closure, conflict or names
+	Package  string
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s %s %s: %s", i.Severity, i.Check, i.Package, i.Message)
+}
+
+// Validate runs the consistency checks of the graph in one pass:
+//
+//	dangling  error    packages referenced but missing from the input, see Dangling
+//	closure   error    Deps missing packages the imports bring in
+//	closure   warning  Deps listing packages no import leads to, "..." chains
+//	conflict  warning  packages listed twice with different records, see Conflicts
+//	names     warning  third-party or first-party package names several
+//	                   packages of a binary share, see NameCollisions
+//
+// Issues are sorted by severity, errors first, then by check and package.
+func (g *DepGraph) Validate() (issues []Issue) {
+	dangling := g.Dangling()
+	for p, referrers := range dangling {
+		issues = append(issues, Issue{SeverityError, "dangling", p, "referenced by " + summarize(referrers)})
+	}
+	issues = append(issues, g.closureIssues()...)
+	for _, c := range g.Conflicts() {
+		issues = append(issues, Issue{SeverityWarning, "conflict", c.Package,
+			"listed more than once with different imports or deps, the last record is used"})
+	}
+	collisions := make(map[string]map[string]bool)
+	for id := range g.mainPackages {
+		for _, c := range g.NameCollisions(g.names[id]) {
+			for _, p := range c.Packages {
+				if !g.IsStandard(p) {
+					if collisions[c.Name] == nil {
+						collisions[c.Name] = make(map[string]bool)
+					}
+					collisions[c.Name][p] = true
+				}
+			}
+		}
+	}
+	for name, set := range collisions {
+		if len(set) < 2 {
+			continue
+		}
+		var packages []string
+		for p := range set {
+			packages = append(packages, p)
+		}
+		sort.Strings(packages)
+		issues = append(issues, Issue{SeverityWarning, "names", packages[0],
+			fmt.Sprintf("package name %s shared by %s in one binary", name, strings.Join(packages, " "))})
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Severity != b.Severity {
+			return a.Severity == SeverityError
+		}
+		if a.Check != b.Check {
+			return a.Check < b.Check
+		}
+		return a.Package < b.Package
+	})
+	return
+}
+
+// cgoImports are the packages import "C" makes the go command add to Deps.
+var cgoImports = []string{"runtime/cgo", "syscall", "unsafe"}
+
+// closureIssues compares the Deps of every loaded package with the
+// closure of its imports, where packages using cgo may also list the
+// closure of cgoImports. Packages missing deps are only reported for the
+// deps the records of their imports don't miss too.
+// Extra Deps are only reported for packages whose imports all have a
+// record, dangling ones already explain them.
+func (g *DepGraph) closureIssues() (issues []Issue) {
+	reach := g.computeClosure()
+	var cgo bitset
+	for _, p := range cgoImports {
+		if id, ok := g.lookup(p); ok {
+			cgo.set(id)
+			cgo.or(reach[id])
+		}
+	}
+	// a package misses the deps the records of its imports miss too,
+	// only report them where they start
+	missingOf := make(map[nodeID]map[nodeID]bool)
+	order, _ := g.postorder(nil)
+	for _, id := range order {
+		if !g.loaded[id] {
+			continue
+		}
+		closure := reach[id]
+		usesCgo := g.cgoPackages[id]
+		closure.each(func(p nodeID) {
+			usesCgo = usesCgo || g.cgoPackages[p]
+		})
+		deps := make(map[nodeID]bool, len(g.deps[id]))
+		var missing, extra []string
+		for _, dep := range g.deps[id] {
+			deps[dep] = true
+			if !closure.has(dep) && !(usesCgo && cgo.has(dep)) {
+				extra = append(extra, g.names[dep])
+			}
+		}
+		inherited := make(map[nodeID]bool)
+		for _, e := range g.imports[id] {
+			if e.inBuild() {
+				for p := range missingOf[e.to] {
+					inherited[p] = true
+				}
+			}
+		}
+		complete := true
+		closure.each(func(p nodeID) {
+			if !g.loaded[p] {
+				complete = false
+			}
+			if !deps[p] {
+				if missingOf[id] == nil {
+					missingOf[id] = make(map[nodeID]bool)
+				}
+				missingOf[id][p] = true
+				if !inherited[p] {
+					missing = append(missing, g.names[p])
+				}
+			}
+		})
+		if len(missing) > 0 {
+			issues = append(issues, Issue{SeverityError, "closure", g.names[id],
+				fmt.Sprintf("Deps misses %d packages its imports bring in: %s", len(missing), summarize(missing))})
+		}
+		if len(extra) > 0 && complete {
+			issues = append(issues, Issue{SeverityWarning, "closure", g.names[id],
+				fmt.Sprintf("Deps lists %d packages no import leads to: %s", len(extra), summarize(extra))})
+		}
+	}
+	return
+}
+
+// summarize joins the first packages, sorted, and tells how many more
+// there are.
+func summarize(packages []string) string {
+	const max = 5
+	sort.Strings(packages)
+	if len(packages) <= max {
+		return strings.Join(packages, " ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(packages[:max], " "), len(packages)-max)
+}
@@ -0,0 +1,38 @@
+package depgraph
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	if issues := loadTestGraph(t).Validate(); len(issues) != 0 {
+		t.Error("go list output should validate", issues)
+	}
+
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"a/util", "b/util"},
+		Deps: []string{"a/util", "b/util", "fmt", "ghost"}})
+	dg.Add(DepInfo{ImportPath: "a/util", Name: "util", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "b/util", Name: "util", Imports: []string{"gone"}})
+	dg.Add(DepInfo{ImportPath: "b/util", Name: "util", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(DepInfo{ImportPath: "fmt", Name: "fmt", Standard: true, Imports: []string{"os"}})
+	dg.Add(DepInfo{ImportPath: "os", Name: "os", Standard: true})
+
+	var got []string
+	for _, i := range dg.Validate() {
+		got = append(got, i.String())
+	}
+	expect := []string{
+		"error closure fmt: Deps misses 1 packages its imports bring in: os",
+		"error dangling ghost: referenced by cmd/a",
+		"warning closure cmd/a: Deps lists 1 packages no import leads to: ghost",
+		"warning conflict b/util: listed more than once with different imports or deps, the last record is used",
+		"warning names a/util: package name util shared by a/util b/util in one binary",
+	}
+	if len(got) != len(expect) {
+		t.Fatal(got)
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Errorf("%q, want %q", got[i], expect[i])
+		}
+	}
+}
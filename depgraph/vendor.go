@@ -0,0 +1,48 @@
+package depgraph
+
+import "strings"
+
+// NormalizeVendor makes Add store vendored packages under their canonical
+// import path, eg: repo/vendor/github.com/foo/bar as github.com/foo/bar,
+// so searching for the canonical path matches vendored copies too. The
+// vendored path each import resolved to is kept in EdgeAttrs.VendorPath.
+// It must be set before packages are added; copies of a package vendored
+// in several places are merged into one.
+func (g *DepGraph) NormalizeVendor(normalize bool) {
+	g.normalizeVendor = normalize
+}
+
+// canonicalPath strips the vendor directory from importPath.
+func canonicalPath(importPath string) string {
+	if i := strings.LastIndex(importPath, "/vendor/"); i >= 0 {
+		return importPath[i+len("/vendor/"):]
+	}
+	return strings.TrimPrefix(importPath, "vendor/")
+}
+
+// unvendor rewrites the paths of d to canonical ones and returns the
+// vendored path of each import it rewrote, by canonical path. The slices
+// of d are copied, not modified.
+func (d *DepInfo) unvendor() map[string]string {
+	origins := make(map[string]string)
+	d.ImportPath = canonicalPath(d.ImportPath)
+	imports := make([]string, 0, len(d.Imports))
+	for _, p := range d.Imports {
+		c := canonicalPath(p)
+		if c != p {
+			origins[c] = p
+		}
+		imports = append(imports, c)
+	}
+	d.Imports = imports
+	seen := make(map[string]bool, len(d.Deps))
+	deps := make([]string, 0, len(d.Deps))
+	for _, p := range d.Deps {
+		if c := canonicalPath(p); !seen[c] {
+			seen[c] = true
+			deps = append(deps, c)
+		}
+	}
+	d.Deps = deps
+	return origins
+}
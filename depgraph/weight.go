@@ -0,0 +1,118 @@
+package depgraph
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// SetWeight sets the cost of routing a chain through packageName, eg: its
+// file count, lines of code or binary size contribution. Packages without
+// an explicit weight cost 1. Negative weights, which CheapestChain can't
+// search with, are rejected.
+func (g *DepGraph) SetWeight(packageName string, weight float64) error {
+	if weight < 0 || math.IsNaN(weight) {
+		return fmt.Errorf("weight of %s is %v, want a non-negative number", packageName, weight)
+	}
+	if g.weights == nil {
+		g.weights = make(map[string]float64)
+	}
+	g.weights[packageName] = weight
+	return nil
+}
+
+// WeightBySize sets the weight of every loaded package to its own Size,
+// in kind: "files" or "lines". Lines must have been counted, see
+// CountLines.
+func (g *DepGraph) WeightBySize(kind string) error {
+	switch kind {
+	case "files":
+	case "lines":
+		if g.TotalSize().Lines == 0 {
+			return fmt.Errorf("no lines of code counted")
+		}
+	default:
+		return fmt.Errorf("unknown size kind %q, want files or lines", kind)
+	}
+	for id, loaded := range g.loaded {
+		if !loaded {
+			continue
+		}
+		size := g.sizes[nodeID(id)]
+		w := size.Files
+		if kind == "lines" {
+			w = size.Lines
+		}
+		g.SetWeight(g.names[id], float64(w))
+	}
+	return nil
+}
+
+func (g *DepGraph) Weight(packageName string) float64 {
+	if w, ok := g.weights[packageName]; ok {
+		return w
+	}
+	return 1
+}
+
+type weightedItem struct {
+	pkg  string
+	cost float64
+}
+
+type weightedQueue []weightedItem
+
+func (q weightedQueue) Len() int            { return len(q) }
+func (q weightedQueue) Less(i, j int) bool  { return q[i].cost < q[j].cost }
+func (q weightedQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *weightedQueue) Push(x interface{}) { *q = append(*q, x.(weightedItem)) }
+func (q *weightedQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// CheapestChain returns the import chain from start to target whose
+// intermediate packages have the lowest total weight, together with that
+// total. The chain is nil if target is not reachable from start. Like
+// Imports, it doesn't follow test-only imports.
+func (g *DepGraph) CheapestChain(start, target string) (chain []string, cost float64) {
+	if !g.Exists(start) {
+		return nil, 0
+	}
+	if start == target {
+		return []string{start}, 0
+	}
+	best := map[string]float64{start: 0}
+	parent := make(map[string]string)
+	done := make(map[string]bool)
+	q := &weightedQueue{{pkg: start}}
+	for q.Len() > 0 {
+		item := heap.Pop(q).(weightedItem)
+		if done[item.pkg] {
+			continue
+		}
+		done[item.pkg] = true
+		if item.pkg == target {
+			for p := target; p != start; p = parent[p] {
+				chain = append(chain, p)
+			}
+			chain = append(chain, start)
+			reverseSlice(chain)
+			return chain, item.cost
+		}
+		for _, p := range g.Imports(item.pkg) {
+			c := item.cost
+			if p != target {
+				c += g.Weight(p)
+			}
+			if old, ok := best[p]; !ok || c < old {
+				best[p] = c
+				parent[p] = item.pkg
+				heap.Push(q, weightedItem{pkg: p, cost: c})
+			}
+		}
+	}
+	return nil, 0
+}
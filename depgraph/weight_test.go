@@ -0,0 +1,65 @@
+package depgraph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheapestChain(t *testing.T) {
+	dg := &DepGraph{}
+	dg.AddEdge("a", "heavy")
+	dg.AddEdge("heavy", "z")
+	dg.AddEdge("a", "b")
+	dg.AddEdge("b", "c")
+	dg.AddEdge("c", "z")
+	chain, cost := dg.CheapestChain("a", "z")
+	if strings.Join(chain, " ") != "a heavy z" || cost != 1 {
+		t.Error(chain, cost)
+	}
+	dg.SetWeight("heavy", 10)
+	chain, cost = dg.CheapestChain("a", "z")
+	if strings.Join(chain, " ") != "a b c z" || cost != 2 {
+		t.Error(chain, cost)
+	}
+	if chain, _ := dg.CheapestChain("z", "a"); chain != nil {
+		t.Error("should not found", chain)
+	}
+	if err := dg.SetWeight("b", -5); err == nil || dg.Weight("b") != 1 {
+		t.Error("negative weight should be rejected", err)
+	}
+	dg.SetEdge("c", "z", EdgeAttrs{TestOnly: true})
+	chain, cost = dg.CheapestChain("a", "z")
+	if strings.Join(chain, " ") != "a heavy z" || cost != 10 {
+		t.Error("test-only edge should be skipped", chain, cost)
+	}
+}
+
+func TestWeightBySize(t *testing.T) {
+	dg := &DepGraph{}
+	dg.Add(DepInfo{ImportPath: "a", Name: "main", Imports: []string{"big", "b"}, Deps: []string{"b", "big", "c", "z"}})
+	dg.Add(DepInfo{ImportPath: "big", Imports: []string{"z"}, Deps: []string{"z"}, GoFiles: []string{"1.go"}, Lines: 1000})
+	dg.Add(DepInfo{ImportPath: "b", Imports: []string{"c"}, Deps: []string{"c", "z"}, GoFiles: []string{"b.go"}, Lines: 10})
+	dg.Add(DepInfo{ImportPath: "c", Imports: []string{"z"}, Deps: []string{"z"}, GoFiles: []string{"c.go"}, Lines: 10})
+	dg.Add(DepInfo{ImportPath: "z", GoFiles: []string{"z.go"}})
+
+	for _, c := range []struct {
+		kind, chain string
+		cost        float64
+	}{
+		{"files", "a big z", 1},
+		{"lines", "a b c z", 20},
+	} {
+		if err := dg.WeightBySize(c.kind); err != nil {
+			t.Fatal(c.kind, err)
+		}
+		if chain, cost := dg.CheapestChain("a", "z"); strings.Join(chain, " ") != c.chain || cost != c.cost {
+			t.Error(c.kind, chain, cost)
+		}
+	}
+	if err := dg.WeightBySize("bytes"); err == nil {
+		t.Error("unknown kind should be rejected")
+	}
+	if err := (&DepGraph{}).WeightBySize("lines"); err == nil {
+		t.Error("lines should need counted lines")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/depsdev"
+)
+
+// reportDepsDev prints one line per third-party module with its license,
+// latest version and scorecard from deps.dev and the main packages using
+// it. If packages is not empty only their modules are reported.
+func reportDepsDev(dg *depgraph.DepGraph, packages []string) {
+	var modules []depgraph.Module
+	if len(packages) == 0 {
+		modules = dg.Modules()
+	} else {
+		seen := make(map[string]bool)
+		for _, p := range packages {
+			m := dg.Module(p)
+			if m == nil {
+				log.Printf("%v has no module", p)
+				continue
+			}
+			if !seen[m.Path] {
+				seen[m.Path] = true
+				modules = append(modules, *m)
+			}
+		}
+	}
+	client := depsdev.NewClient()
+	for _, m := range modules {
+		if m.Main {
+			continue
+		}
+		names := moduleMains(dg, m.Path)
+		fields := []string{m.Path + "@" + m.Version}
+		info, err := client.Lookup(m.Path, m.Version)
+		if err != nil {
+			log.Printf("deps.dev lookup %v failed: %v", m.Path, err)
+		} else {
+			license := strings.Join(info.Licenses, ",")
+			if license == "" {
+				license = "unknown"
+			}
+			fields = append(fields, "license="+license)
+			if info.LatestVersion != "" && info.LatestVersion != m.Version {
+				fields = append(fields, "latest="+info.LatestVersion)
+			}
+			if info.Scorecard >= 0 {
+				fields = append(fields, fmt.Sprintf("scorecard=%.1f", info.Scorecard))
+			}
+		}
+		fields = append(fields, fmt.Sprintf("mains=%d", len(names)))
+		fmt.Println(strings.Join(fields, " "))
+		for _, main := range names {
+			fmt.Println("\tmain -> " + main)
+		}
+	}
+}
+
+// moduleMains returns the main packages depending on a package of module
+// path, sorted.
+func moduleMains(dg *depgraph.DepGraph, path string) []string {
+	mains := make(map[string]bool)
+	for _, p := range dg.PackagesOf(path) {
+		for _, main := range dg.SearchMain(p) {
+			mains[main] = true
+		}
+	}
+	names := make([]string, 0, len(mains))
+	for main := range mains {
+		names = append(names, main)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,108 @@
+// Package depsdev looks up license, release and OpenSSF scorecard data for
+// Go modules in the deps.dev API, see https://docs.deps.dev/api/v3/.
+package depsdev
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Info is what deps.dev knows about one module version.
+type Info struct {
+	Licenses      []string
+	LatestVersion string  // default (latest stable) version of the module
+	Project       string  // source repository, eg: github.com/foo/bar
+	Scorecard     float64 // OpenSSF scorecard overall score, -1 if unknown
+}
+
+type Client struct {
+	HTTP    *http.Client
+	BaseURL string
+}
+
+func NewClient() *Client {
+	return &Client{
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+		BaseURL: "https://api.deps.dev/v3",
+	}
+}
+
+func (c *Client) get(path string, v interface{}) error {
+	resp, err := c.HTTP.Get(c.BaseURL + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("deps.dev %s: %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Lookup fetches Info for version of module path. A missing project or
+// scorecard is not an error.
+func (c *Client) Lookup(path, version string) (*Info, error) {
+	info := &Info{Scorecard: -1}
+	pkg := "/systems/go/packages/" + url.PathEscape(path)
+
+	var p struct {
+		Versions []struct {
+			VersionKey struct {
+				Version string `json:"version"`
+			} `json:"versionKey"`
+			IsDefault bool `json:"isDefault"`
+		} `json:"versions"`
+	}
+	if err := c.get(pkg, &p); err != nil {
+		return nil, err
+	}
+	for _, v := range p.Versions {
+		if v.IsDefault {
+			info.LatestVersion = v.VersionKey.Version
+		}
+	}
+	if version == "" {
+		version = info.LatestVersion
+	}
+	if version == "" {
+		return info, nil
+	}
+
+	var v struct {
+		Licenses        []string `json:"licenses"`
+		RelatedProjects []struct {
+			ProjectKey struct {
+				ID string `json:"id"`
+			} `json:"projectKey"`
+			RelationType string `json:"relationType"`
+		} `json:"relatedProjects"`
+	}
+	if err := c.get(pkg+"/versions/"+url.PathEscape(version), &v); err != nil {
+		return nil, err
+	}
+	info.Licenses = v.Licenses
+	for _, rp := range v.RelatedProjects {
+		if rp.RelationType == "SOURCE_REPO" {
+			info.Project = rp.ProjectKey.ID
+		}
+	}
+	if info.Project == "" {
+		return info, nil
+	}
+
+	var proj struct {
+		Scorecard *struct {
+			OverallScore float64 `json:"overallScore"`
+		} `json:"scorecard"`
+	}
+	if err := c.get("/projects/"+url.PathEscape(info.Project), &proj); err != nil {
+		return info, nil
+	}
+	if proj.Scorecard != nil {
+		info.Scorecard = proj.Scorecard.OverallScore
+	}
+	return info, nil
+}
@@ -0,0 +1,40 @@
+package depsdev
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	responses := map[string]string{
+		"/systems/go/packages/golang.org%2Fx%2Ftext": `{"versions": [
+			{"versionKey": {"version": "v0.3.6"}},
+			{"versionKey": {"version": "v0.14.0"}, "isDefault": true}]}`,
+		"/systems/go/packages/golang.org%2Fx%2Ftext/versions/v0.3.6": `{"licenses": ["BSD-3-Clause"],
+			"relatedProjects": [{"projectKey": {"id": "github.com/golang/text"}, "relationType": "SOURCE_REPO"}]}`,
+		"/projects/github.com%2Fgolang%2Ftext": `{"scorecard": {"overallScore": 7.5}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	c := NewClient()
+	c.BaseURL = srv.URL
+	info, err := c.Lookup("golang.org/x/text", "v0.3.6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.LatestVersion != "v0.14.0" || len(info.Licenses) != 1 || info.Licenses[0] != "BSD-3-Clause" ||
+		info.Project != "github.com/golang/text" || info.Scorecard != 7.5 {
+		t.Error("result error", info)
+	}
+	if _, err := c.Lookup("example.com/missing", ""); err == nil {
+		t.Error("missing module should fail")
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportEmbeds prints, for every directory or file in args, the packages
+// embedding files below it with //go:embed, the files, and the chain from
+// each main package including them.
+func reportEmbeds(dg *depgraph.DepGraph, args []string) {
+	wd, _ := os.Getwd()
+	for _, arg := range args {
+		for _, e := range dg.Embedders(arg) {
+			files := make([]string, len(e.Files))
+			for i, f := range e.Files {
+				files[i] = f
+				if rel, err := filepath.Rel(wd, f); err == nil && !strings.HasPrefix(rel, "..") {
+					files[i] = rel
+				}
+			}
+			fmt.Printf("%s%s embeds %s\n", e.Package, ownerNote(e.Package), strings.Join(files, " "))
+			for _, chain := range dg.SearchChain(e.Package) {
+				fmt.Println("\t" + formatChain(dg, chain))
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// Exit statuses, for scripts to tell failure modes apart. When several
+// apply, the lowest wins.
+const (
+	exitViolation = 1 // a check failed: rule violations, -validate errors, outdated modules...
+	exitUsage     = 2 // bad flags or args, like the flag package
+	exitNotFound  = 3 // a package or module of args is not in the graph, or -count found nothing
+	exitParse     = 4 // the input or a file named by a flag can't be read or parsed
+	exitPartial   = 5 // the results come from incomplete input, eg: a chain through dangling packages
+	exitError     = 6 // any other error
+)
+
+var exitKinds = map[int]string{
+	exitViolation: "violation",
+	exitUsage:     "usage",
+	exitNotFound:  "not_found",
+	exitParse:     "parse",
+	exitPartial:   "partial",
+	exitError:     "error",
+}
+
+// Failure is an error or warning as -errors json writes it to stderr, one
+// JSON object per line.
+type Failure struct {
+	Code    int    `json:"code"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+	Fatal   bool   `json:"fatal"` // whether the command stopped there
+}
+
+// exitStatus is the status main exits with when done, see warn.
+var exitStatus int
+
+// reportFailure logs msg, or writes it as a Failure with -errors json.
+func reportFailure(code int, fatal bool, msg string) {
+	if *errorFormat != "json" {
+		log.Println(msg)
+		return
+	}
+	json.NewEncoder(os.Stderr).Encode(Failure{Code: code, Kind: exitKinds[code], Message: msg, Fatal: fatal})
+}
+
+// warn reports a failure the command goes on after, eg: one of several
+// args not found, and makes main exit with code unless a lower one is set.
+func warn(code int, format string, v ...interface{}) {
+	reportFailure(code, false, fmt.Sprintf(format, v...))
+	if exitStatus == 0 || code < exitStatus {
+		exitStatus = code
+	}
+}
+
+// fail reports a failure and exits with code.
+func fail(code int, format string, v ...interface{}) {
+	reportFailure(code, true, fmt.Sprintf(format, v...))
+	os.Exit(code)
+}
+
+// fatalf is log.Fatalf exiting with exitError.
+func fatalf(format string, v ...interface{}) {
+	fail(exitError, format, v...)
+}
+
+// fatalln is log.Fatalln exiting with exitError.
+func fatalln(v ...interface{}) {
+	fail(exitError, "%s", strings.TrimSuffix(fmt.Sprintln(v...), "\n"))
+}
+
+// exit prints the held results, see flushResults, and exits with the
+// status warn set, if any.
+func exit() {
+	flushResults()
+	if exitStatus != 0 {
+		os.Exit(exitStatus)
+	}
+}
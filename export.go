@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"os"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/export"
+)
+
+// exportGraph writes the whole graph to stdout in the format named by
+// *exportFormat, or with two args only the packages between them.
+func exportGraph(dg *depgraph.DepGraph) {
+	if flag.NArg() == 2 {
+		dg = dg.SubgraphBetween(flag.Arg(0), flag.Arg(1))
+		if dg.CountAll() == 0 {
+			fail(exitNotFound, "%v does not import %v", flag.Arg(0), flag.Arg(1))
+		}
+	}
+	if *anonymize {
+		dg = dg.Anonymize(anonymizeSalt())
+	}
+	e, err := export.Get(*exportFormat)
+	if err != nil {
+		fatalln(err)
+	}
+	if err := e.Export(os.Stdout, dg); err != nil {
+		fatalln("export failed", err)
+	}
+}
+
+// anonymizeSalt returns -salt, or a random salt so the names of the
+// export can't be checked against guessed paths.
+func anonymizeSalt() string {
+	if *salt != "" {
+		return *salt
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		fatalln("generate salt failed", err)
+	}
+	return hex.EncodeToString(b)
+}
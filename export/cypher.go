@@ -0,0 +1,43 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+var cypherEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+func cypherString(s string) string {
+	return "'" + cypherEscaper.Replace(s) + "'"
+}
+
+// Cypher writes g as Cypher statements loadable with cypher-shell: one
+// MERGE per (:Package) node, carrying its main/test/std flags, module and
+// depgraph.StableID, and one per [:IMPORTS] relationship.
+func Cypher(w io.Writer, g *depgraph.DepGraph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "CREATE CONSTRAINT IF NOT EXISTS FOR (p:Package) REQUIRE p.path IS UNIQUE;")
+	fmt.Fprintln(bw, "CREATE INDEX IF NOT EXISTS FOR (p:Package) ON (p.id);")
+	list := edges(g)
+	for _, p := range nodes(g, list) {
+		props := []string{
+			"p.main = " + fmt.Sprint(g.IsMainPackage(p)),
+			"p.test = " + fmt.Sprint(g.IsTestPackage(p)),
+			"p.std = " + fmt.Sprint(g.IsStandard(p)),
+		}
+		if m := g.Module(p); m != nil {
+			props = append(props, "p.module = "+cypherString(m.Path), "p.version = "+cypherString(m.Version))
+		}
+		props = append(props, "p.id = "+cypherString(g.StableID(p)))
+		fmt.Fprintf(bw, "MERGE (p:Package {path: %s}) SET %s;\n", cypherString(p), strings.Join(props, ", "))
+	}
+	for _, e := range list {
+		fmt.Fprintf(bw, "MATCH (a:Package {path: %s}), (b:Package {path: %s}) MERGE (a)-[:IMPORTS]->(b);\n",
+			cypherString(e.From), cypherString(e.To))
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,53 @@
+// Package export writes a dependency graph in formats other tools load:
+// Cypher statements for Neo4j, SQL scripts for SQLite and GEXF for Gephi. Other
+// formats plug in with Register.
+package export
+
+import (
+	"sort"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Edge is an import edge.
+type Edge struct {
+	From, To string
+}
+
+// edges returns every import edge of g, sorted.
+func edges(g *depgraph.DepGraph) (list []Edge) {
+	all := g.EdgesWhere(func(from, to string, attrs depgraph.EdgeAttrs) bool { return true })
+	for from, tos := range all {
+		for _, to := range tos {
+			list = append(list, Edge{from, to})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].From != list[j].From {
+			return list[i].From < list[j].From
+		}
+		return list[i].To < list[j].To
+	})
+	return
+}
+
+// nodes returns the loaded packages plus every import target, sorted.
+func nodes(g *depgraph.DepGraph, edges []Edge) []string {
+	seen := make(map[string]bool)
+	var list []string
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			list = append(list, p)
+		}
+	}
+	for _, p := range g.Packages() {
+		add(p)
+	}
+	for _, e := range edges {
+		add(e.From)
+		add(e.To)
+	}
+	sort.Strings(list)
+	return list
+}
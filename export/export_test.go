@@ -0,0 +1,137 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func testGraph() *depgraph.DepGraph {
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/cmd/a", Name: "main",
+		Imports: []string{"example.com/lib", "fmt"}, Deps: []string{"example.com/lib", "fmt"},
+		Module: &depgraph.Module{Path: "example.com", Main: true}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/lib", Name: "lib",
+		Imports: []string{"fmt"}, Deps: []string{"fmt"},
+		Module: &depgraph.Module{Path: "example.com", Main: true}})
+	dg.Add(depgraph.DepInfo{ImportPath: "fmt", Name: "fmt"})
+	return dg
+}
+
+func TestCypher(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Cypher(&buf, testGraph()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		"MERGE (p:Package {path: 'example.com/cmd/a'}) SET p.main = true, p.test = false, p.std = false, p.module = 'example.com', p.version = '', p.id = '" +
+			depgraph.StableID("example.com/cmd/a", "") + "';",
+		"MERGE (p:Package {path: 'fmt'}) SET p.main = false, p.test = false, p.std = true, p.id = '" + depgraph.StableID("fmt", "") + "';",
+		"MATCH (a:Package {path: 'example.com/lib'}), (b:Package {path: 'fmt'}) MERGE (a)-[:IMPORTS]->(b);",
+	} {
+		if !strings.Contains(out, s) {
+			t.Error("missing", s)
+		}
+	}
+	if strings.Count(out, "[:IMPORTS]") != 3 {
+		t.Error("expect 3 edges:\n" + out)
+	}
+	if cypherString(`a'b\c`) != `'a\'b\\c'` {
+		t.Error(cypherString(`a'b\c`))
+	}
+}
+
+func TestSQL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SQL(&buf, testGraph()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		"INSERT INTO modules VALUES (1, 'example.com', '', 1);",
+		"INSERT INTO packages VALUES (1, 'example.com/cmd/a', 1, 1, 0, 0, 1, '" + depgraph.StableID("example.com/cmd/a", "") + "');",
+		"INSERT INTO packages VALUES (3, 'fmt', 1, 0, 0, 1, NULL, '" + depgraph.StableID("fmt", "") + "');",
+		"INSERT INTO imports VALUES (2, 3, 0, 0);",
+		"INSERT INTO deps VALUES (1, 3);",
+	} {
+		if !strings.Contains(out, s) {
+			t.Error("missing", s)
+		}
+	}
+	if !strings.HasPrefix(out, "BEGIN;") || !strings.HasSuffix(out, "COMMIT;\n") {
+		t.Error("should be one transaction")
+	}
+	if sqlString("it's") != "'it''s'" {
+		t.Error(sqlString("it's"))
+	}
+}
+
+func TestGEXF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GEXF(&buf, testGraph()); err != nil {
+		t.Fatal(err)
+	}
+	var doc gexfDoc
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Graph.Nodes) != 3 || len(doc.Graph.Edges) != 3 {
+		t.Fatal("result error", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	fmtNode := doc.Graph.Nodes[2]
+	if fmtNode.ID != "fmt" || fmtNode.Values[1].Value != "false" || fmtNode.Values[2].Value != "true" ||
+		fmtNode.Values[3].Value != "2" || fmtNode.Values[4].Value != depgraph.StableID("fmt", "") {
+		t.Error("result error", fmtNode)
+	}
+	if doc.Graph.Nodes[0].Values[0].Value != "example.com" || doc.Graph.Nodes[0].Values[1].Value != "true" {
+		t.Error("result error", doc.Graph.Nodes[0])
+	}
+}
+
+func TestAnonymized(t *testing.T) {
+	// a dotless corporate path and no Standard fields, like a graph read
+	// back from GEXF
+	billing := &depgraph.Module{Path: "corp/billing", Main: true}
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "corp/billing/cmd/invoice", Name: "main", Module: billing,
+		Imports: []string{"corp/billing", "fmt"}, Deps: []string{"corp/billing", "fmt"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "corp/billing", Name: "billing", Module: billing})
+	dg.Add(depgraph.DepInfo{ImportPath: "fmt", Name: "fmt"})
+	a := dg.Anonymize("secret")
+	for name, e := range map[string]Func{"gexf": GEXF, "cypher": Cypher, "sql": SQL} {
+		var buf bytes.Buffer
+		if err := e(&buf, a); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), "corp") || strings.Contains(buf.String(), "billing") {
+			t.Error(name, "leaked a path", buf.String())
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("count", Func(func(w io.Writer, g *depgraph.DepGraph) error {
+		_, err := fmt.Fprintln(w, len(g.Packages()))
+		return err
+	}))
+	if got := strings.Join(Names(), " "); got != "count cypher gexf sql" {
+		t.Error(got)
+	}
+	e, err := Get("count")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := e.Export(&buf, testGraph()); err != nil || buf.String() != "3\n" {
+		t.Error(buf.String(), err)
+	}
+	if _, err := Get("spdx"); err == nil {
+		t.Error("expect error for unknown format")
+	}
+}
@@ -0,0 +1,100 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+type gexfAttr struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfNode struct {
+	ID     string      `xml:"id,attr"`
+	Label  string      `xml:"label,attr"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID     int    `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+}
+
+type gexfDoc struct {
+	XMLName xml.Name `xml:"gexf"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	Graph   struct {
+		DefaultEdgeType string `xml:"defaultedgetype,attr"`
+		Attributes      struct {
+			Class string     `xml:"class,attr"`
+			Attrs []gexfAttr `xml:"attribute"`
+		} `xml:"attributes"`
+		Nodes []gexfNode `xml:"nodes>node"`
+		Edges []gexfEdge `xml:"edges>edge"`
+	} `xml:"graph"`
+}
+
+// GEXF writes g as a GEXF 1.3 document for Gephi. Every node carries its
+// module, is_main, is_std, fan_in (number of importers) and stable_id, see
+// depgraph.StableID, attributes.
+func GEXF(w io.Writer, g *depgraph.DepGraph) error {
+	var doc gexfDoc
+	doc.XMLNS = "http://gexf.net/1.3"
+	doc.Version = "1.3"
+	doc.Graph.DefaultEdgeType = "directed"
+	doc.Graph.Attributes.Class = "node"
+	doc.Graph.Attributes.Attrs = []gexfAttr{
+		{ID: "module", Title: "module", Type: "string"},
+		{ID: "is_main", Title: "is_main", Type: "boolean"},
+		{ID: "is_std", Title: "is_std", Type: "boolean"},
+		{ID: "fan_in", Title: "fan_in", Type: "integer"},
+		{ID: "stable_id", Title: "stable_id", Type: "string"},
+	}
+	list := edges(g)
+	fanIn := make(map[string]int)
+	for _, e := range list {
+		fanIn[e.To]++
+	}
+	for _, p := range nodes(g, list) {
+		module := ""
+		if m := g.Module(p); m != nil {
+			module = m.Path
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{
+			ID:    p,
+			Label: p,
+			Values: []gexfValue{
+				{For: "module", Value: module},
+				{For: "is_main", Value: fmt.Sprint(g.IsMainPackage(p))},
+				{For: "is_std", Value: fmt.Sprint(g.IsStandard(p))},
+				{For: "fan_in", Value: fmt.Sprint(fanIn[p])},
+				{For: "stable_id", Value: g.StableID(p)},
+			},
+		})
+	}
+	for i, e := range list {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{ID: i, Source: e.From, Target: e.To})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
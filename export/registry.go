@@ -0,0 +1,73 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Exporter writes a graph in some output format. Packages writing formats
+// of their own, eg: for internal dashboards, register their Exporter from
+// an init function and are enabled by importing them.
+type Exporter interface {
+	Export(w io.Writer, g *depgraph.DepGraph) error
+}
+
+// Func adapts a function, like Cypher, to Exporter.
+type Func func(w io.Writer, g *depgraph.DepGraph) error
+
+// Export calls f.
+func (f Func) Export(w io.Writer, g *depgraph.DepGraph) error {
+	return f(w, g)
+}
+
+var (
+	mu        sync.RWMutex
+	exporters = make(map[string]Exporter)
+)
+
+func init() {
+	Register("cypher", Func(Cypher))
+	Register("sql", Func(SQL))
+	Register("gexf", Func(GEXF))
+}
+
+// Register makes e available under name. It panics if name is already
+// taken, like database/sql.Register.
+func Register(name string, e Exporter) {
+	mu.Lock()
+	defer mu.Unlock()
+	if e == nil {
+		panic("export: Register exporter is nil")
+	}
+	if _, dup := exporters[name]; dup {
+		panic("export: Register called twice for " + name)
+	}
+	exporters[name] = e
+}
+
+// Get returns the Exporter registered under name.
+func Get(name string) (Exporter, error) {
+	mu.RLock()
+	e, ok := exporters[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown export format %q, registered: %v", name, Names())
+	}
+	return e, nil
+}
+
+// Names returns the registered export formats, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
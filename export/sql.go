@@ -0,0 +1,106 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+const sqlSchema = `CREATE TABLE modules (
+	id      INTEGER PRIMARY KEY,
+	path    TEXT NOT NULL,
+	version TEXT NOT NULL,
+	main    INTEGER NOT NULL,
+	UNIQUE (path, version)
+);
+CREATE TABLE packages (
+	id        INTEGER PRIMARY KEY,
+	path      TEXT NOT NULL UNIQUE,
+	loaded    INTEGER NOT NULL, -- 0 for import targets missing from the dump
+	is_main   INTEGER NOT NULL,
+	is_test   INTEGER NOT NULL,
+	is_std    INTEGER NOT NULL, -- part of the standard library
+	module_id INTEGER REFERENCES modules (id),
+	stable_id TEXT NOT NULL -- same in every export, see depgraph.StableID
+);
+CREATE TABLE imports (
+	from_id   INTEGER NOT NULL REFERENCES packages (id),
+	to_id     INTEGER NOT NULL REFERENCES packages (id),
+	vendored  INTEGER NOT NULL,
+	test_only INTEGER NOT NULL,
+	PRIMARY KEY (from_id, to_id)
+) WITHOUT ROWID;
+CREATE TABLE deps (
+	package_id INTEGER NOT NULL REFERENCES packages (id),
+	dep_id     INTEGER NOT NULL REFERENCES packages (id),
+	PRIMARY KEY (package_id, dep_id)
+) WITHOUT ROWID;
+CREATE INDEX imports_to ON imports (to_id);
+CREATE INDEX deps_dep ON deps (dep_id);
+CREATE INDEX packages_module ON packages (module_id);
+CREATE INDEX packages_stable_id ON packages (stable_id);
+`
+
+func sqlString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+func sqlBool(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// SQL writes g as an SQLite script creating and filling the tables of
+// sqlSchema, to pipe into sqlite3, eg: go_dep_search -export sql | sqlite3
+// deps.db. Packages are linked to their module, imports are the direct edges and
+// deps the (recursive) Deps of every package, so most questions this tool
+// answers are a join away, eg. the mains depending on net/http:
+//
+//	SELECT p.path FROM deps d
+//	JOIN packages p ON p.id = d.package_id
+//	JOIN packages t ON t.id = d.dep_id
+//	WHERE t.path = 'net/http' AND p.is_main;
+func SQL(w io.Writer, g *depgraph.DepGraph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "BEGIN;")
+	fmt.Fprint(bw, sqlSchema)
+
+	moduleIDs := make(map[depgraph.Module]int)
+	for i, m := range g.Modules() {
+		moduleIDs[depgraph.Module{Path: m.Path, Version: m.Version}] = i + 1
+		fmt.Fprintf(bw, "INSERT INTO modules VALUES (%d, %s, %s, %d);\n",
+			i+1, sqlString(m.Path), sqlString(m.Version), sqlBool(m.Main))
+	}
+
+	list := edges(g)
+	packageIDs := make(map[string]int)
+	for i, p := range nodes(g, list) {
+		packageIDs[p] = i + 1
+		module := "NULL"
+		if m := g.Module(p); m != nil {
+			module = fmt.Sprint(moduleIDs[depgraph.Module{Path: m.Path, Version: m.Version}])
+		}
+		fmt.Fprintf(bw, "INSERT INTO packages VALUES (%d, %s, %d, %d, %d, %d, %s, %s);\n", i+1, sqlString(p),
+			sqlBool(g.Exists(p)), sqlBool(g.IsMainPackage(p)), sqlBool(g.IsTestPackage(p)),
+			sqlBool(g.IsStandard(p)), module, sqlString(g.StableID(p)))
+	}
+	for _, e := range list {
+		attrs := g.Edge(e.From, e.To)
+		fmt.Fprintf(bw, "INSERT INTO imports VALUES (%d, %d, %d, %d);\n",
+			packageIDs[e.From], packageIDs[e.To], sqlBool(attrs.Vendored), sqlBool(attrs.TestOnly))
+	}
+	for _, p := range g.Packages() {
+		for _, dep := range g.Deps(p) {
+			if id, ok := packageIDs[dep]; ok {
+				fmt.Fprintf(bw, "INSERT INTO deps VALUES (%d, %d);\n", packageIDs[p], id)
+			}
+		}
+	}
+	fmt.Fprintln(bw, "COMMIT;")
+	return bw.Flush()
+}
@@ -3,12 +3,17 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/loader"
 )
 
 const usage = `Usage:
@@ -20,89 +25,916 @@ Args:
 
 var (
 	onlyMain        = flag.Bool("main", false, "only show main package")
-	onlyTest        = flag.Bool("test", false, "only show test package")
+	onlyTest        = flag.Bool("test", false, "only show test package, with the chain from each test binary")
+	untested        = flag.Bool("untested", false, "list packages no test binary exercises, needs go list -test")
+	testBin         = flag.String("testbin", "suffix", "how main packages are told to be test binaries: suffix: named like p.test, fortest: built for a test, by the ForTest field or imports of test variants, regexp:RE: import path matching RE")
 	chain           = flag.Bool("chain", false, "show dep chain, eg: main->package_a->b->c->d")
+	from            = flag.String("from", "", "show the dep chain from these comma separated packages, or every package below prefix/..., instead of from the main packages, eg: for a library")
+	reverse         = flag.Bool("reverse", false, "show dep chain from every root package, including libraries nobody imports, with -graph the graph of every package importing the arg")
+	conflicts       = flag.Bool("conflicts", false, "list packages the input has more than once with different imports or deps, the last one is used")
+	validateFormat  = flag.String("validate", "", "check the input in one pass: dangling packages, Deps disagreeing with the imports, conflicting records and package name collisions, as errors and warnings, exit 1 on errors, format: text,json")
+	dangling        = flag.Bool("dangling", false, "list packages imported or depended on but missing from the input, and who references them")
+	firstParty      = flag.String("firstparty", "", "comma separated path prefixes of first-party code, eg: corp.example.com/**, default the main module")
+	thirdPartyOnly  = flag.Bool("thirdparty", false, "leave standard library and first-party packages out of the results")
+	via             = flag.String("via", "", "list per main package the packages it only reaches through this one, gone if it was dropped")
+	names           = flag.Bool("names", false, "list the package names several deps of the main packages in args, or of all, share, eg: forked utils packages")
+	external        = flag.Bool("external", false, "show which share of the packages of the main packages in args, or of all, is third-party")
+	noStd           = flag.Bool("nostd", false, "leave standard library packages out of the results")
 	unused          = flag.Bool("unused", false, "list unused packages")
-	graph           = flag.Bool("graph", false, "show dep graph: -graph <from_package> <to_package>")
+	graph           = flag.Bool("graph", false, "show dep graph: -graph <from_package> <to_package>, or -graph <to_package> from every main package or the -from packages, with -reverse from every package importing it")
+	storeDir        = flag.String("store", ".go_dep_search", "directory of the snapshots saved with -save")
+	save            = flag.String("save", "", "save the input as a snapshot of this name, eg: v1.42, in -store")
+	listSnapshots   = flag.Bool("snapshots", false, "list the snapshots in -store")
+	growth          = flag.String("growth", "", "show how the package count, the third-party module count and the deps of the main packages in args, or of all, grew across the snapshots in -store: text,csv,json")
+	at              = flag.String("at", "", "query the snapshot of this name instead of the input")
+	diffSnapshot    = flag.String("diff", "", "list the packages added and removed since the snapshot of this name, like -since")
+	writeIndexFile  = flag.String("writeindex", "", "save the graph to this index file, to be queried with -index")
+	indexFile       = flag.String("index", "", "answer plain and -main searches from this file saved by -writeindex instead of the input, memory mapped so memory stays bounded")
+	shardBy         = flag.String("shard", "", "load the input in shards, by module or by these comma separated path prefixes, to keep memory low on huge dumps; only plain and -main searches are supported")
+	multiGraphs     = flag.String("multi", "", "search the args in each of these comma separated name=file go list -json outputs, eg: api=api.json,web=web.json, and prefix the chains, or the -main results, with the graph name")
+	prune           = flag.String("prune", "", "leave these comma separated path prefixes out of the graph, and the standard library for std, eg: std,example.com/app/gen/...")
+	ignorePatterns  = flag.String("ignore", "", "leave the packages matching these comma separated patterns, where ** matches any number of path elements, out of the graph, eg: **/mocks/**,**/gen/**, so generated code and mocks don't dominate unused package reports and fan-in statistics")
+	ignoreAtQuery   = flag.Bool("ignorequery", false, "used with -ignore, keep the ignored packages in the graph, so chains through them are still found, and only leave them out of the results")
+	inputFormat     = flag.String("inputformat", "golist", "format of the input, golist for go list -json output, or a format registered by a linked in loader package")
+	lenient         = flag.Bool("lenient", false, "skip and report records of the input that fail to decode instead of giving up")
+	showPos         = flag.Bool("pos", false, "show under every chain the file and line of each of its imports, eg: pkg/auth/token.go:12 imports github.com/x/jwt, only works on the machine go list ran on")
+	loc             = flag.Bool("loc", false, "count lines of code of the GoFiles, only works on the machine go list ran on")
+	sortBy          = flag.String("sort", "", "sort results by: path, module, deps (most dependencies first) or chain (shortest chain to the searched package first)")
+	countOnly       = flag.Bool("count", false, "only print the number of results, per group with -groupby, and exit 3 if there is none")
+	errorFormat     = flag.String("errors", "", "write the errors and the warnings setting the exit status to stderr as JSON objects, one per line, with the exit code, its kind and the message, format: json")
+	resultsBy       = flag.String("groupby", "", "list results under their module, followed by their owners with -owners, under their owners, or under their value of a -labels label: module,owner,label:KEY, eg: label:tier")
+	labelsFile      = flag.String("labels", "", "label the main packages from a file of \"import_path_pattern key=value...\" lines, eg: example.com/app/cmd/billing service=billing tier=production, every matching line adds its labels, later lines override earlier ones")
+	labelSelector   = flag.String("label", "", "used with -labels, only show the results, the main packages of -main searches and chains, with these comma separated labels, key=value or key for any value, eg: tier=production")
+	ownersFile      = flag.String("owners", "", "annotate every package of results and chains with its owners, and list results under them with -groupby, from a CODEOWNERS-style file of \"import_path_pattern owner...\" lines, the last match wins, or from a .yaml/.yml mapping of path prefixes to owners, the longest prefix wins")
+	format          = flag.String("f", "", "print listed packages with this text/template, like go list -f, eg: '{{.ImportPath}} {{len .Importers}}'")
+	byModule        = flag.Bool("bymodule", false, "show consecutive packages of a module in chains as one entry, eg: github.com/org/infra (4 packages)")
+	explain         = flag.Bool("explain", false, "tell chains as sentences, eg: main package cmd/api imports pkg/auth, which imports ..., to paste in tickets")
+	showSize        = flag.Bool("size", false, "show in chains the source each package brings in, in lines with -loc, else in files")
+	cheapest        = flag.String("cheapest", "", "show dep chains like -chain, but per main package the one whose packages have the least source instead of the shortest, counted in: files, lines (needs -loc)")
+	unvendor        = flag.Bool("vendor", false, "strip vendor directories from import paths, so github.com/foo/bar also matches repo/vendor/github.com/foo/bar")
+	groupBy         = flag.String("group", "", "collapse packages into groups before anything else: module, or comma separated path prefixes, eg: team-a/**,team-b/**")
+	ignoreDeps      = flag.Bool("closure", false, "compute deps from imports instead of trusting the Deps field, for partial dumps")
+	vulnFile        = flag.String("vuln", "", "report main packages exposed to the vulnerabilities in this OSV or govulncheck -json file")
+	osv             = flag.Bool("osv", false, "report main packages exposed to vulnerabilities known to osv.dev (needs module mode)")
+	issueFormat     = flag.String("issues", "", "used with -rules, -internal, -vuln and -osv, write the findings as one issue per owning team from -owners instead, format: github,gitlab (API payloads, one per line) or markdown")
+	depsDev         = flag.Bool("depsdev", false, "annotate third-party modules with license, latest version and scorecard from deps.dev")
+	retracted       = flag.Bool("retracted", false, "report third-party modules whose version is retracted or which are deprecated, as the latest go.mod on the module proxy says, with the chain from each main package needing them, exit 1 if any")
+	freshness       = flag.String("freshness", "", "report third-party modules behind their latest version on the module proxy ($GOPROXY or proxy.golang.org) by more than these thresholds, with the main packages using them, exit 1 if any, eg: versions=3,months=12")
+	licenseFile     = flag.String("licenses", "", "show main packages including copyleft packages, licenses read from this go-licenses csv output")
+	load            = flag.String("load", "", "run go list -json -deps on these comma separated patterns, eg: ./cmd/...,std, instead of reading its output from stdin; also used by -since")
+	noDeps          = flag.Bool("nodeps", false, "used with -load and -since, only list the packages matching the patterns, not their deps")
+	tags            = flag.String("tags", "", "comma separated build tags the input was listed with, passed to the go list -since runs and shown in reports")
+	goos            = flag.String("goos", "", "GOOS the input was listed with, passed to the go list -since runs and shown in reports")
+	goarch          = flag.String("goarch", "", "GOARCH the input was listed with, passed to the go list -since runs and shown in reports")
+	goflags         = flag.String("goflags", "", "GOFLAGS the input was listed with, eg: -mod=vendor, passed to the go list -since runs and shown in reports")
+	goModFile       = flag.String("gomod", "", "apply the replace directives of this go.mod, so packages resolve under both paths, and mark the packages of its tool directives as tools, see -tools")
+	toolsMode       = flag.String("tools", "", "include (default), exclude: leave the packages only tools bring in out of the results, only: show only them. Tools are the tool directives of -gomod and the imports of tools.go packages, loaded with -tags tools")
+	goSumFile       = flag.String("gosum", "", "check the modules of the build against this go.sum and the go.mod next to it")
+	upgrade         = flag.String("upgrade", "", "show packages affected by bumping a module: -upgrade module[@new_version]")
+	cgo             = flag.Bool("cgo", false, "list main packages including cgo and the packages importing \"C\" they depend on")
+	unsafeUse       = flag.Bool("unsafe", false, "list main packages including packages outside the standard library that import \"C\" or \"unsafe\", with the chain to each, for memory-safety reviews")
+	canImport       = flag.Bool("canimport", false, "check that importing the second arg in the first one creates no import cycle, exit 1 and show the cycle otherwise: -canimport <from_package> <to_package>")
+	internal        = flag.Bool("internal", false, "check for imports of internal packages the go command would reject, eg: in graphs merged from several repositories, exit 1 on violations")
+	majors          = flag.Bool("majors", false, "list main packages depending on several major versions of a module, eg: foo and foo/v2 (needs module mode)")
+	unique          = flag.Bool("unique", false, "list per main package the packages no other main package depends on")
+	overlap         = flag.Bool("overlap", false, "show for every pair of main packages the Jaccard similarity of their deps, most similar first")
+	embeds          = flag.Bool("embeds", false, "list the packages embedding files below the directories or files in args with //go:embed, the files and the chain from each main package shipping them, needs Go 1.16 go list output")
+	adoption        = flag.Bool("adoption", false, "report for each library package in args the main packages reaching it, each with the packages of its closure importing the library directly, and each of those importers with the main packages it brings the library into, to plan breaking changes")
+	blastFile       = flag.String("blast", "", "report the main packages and test binaries broken by removing the packages matching the patterns of the file, one per line, eg: the sub-packages a refactoring deletes, with the shortest chain to each and the owners of the packages to fix")
+	impact          = flag.Bool("impact", false, "list the main packages and test binaries to rebuild when the packages or files in args change, needs go list -test for the tests")
+	top             = flag.Int("top", 0, "list the N first-party and N third-party packages with the most transitive dependents")
+	depths          = flag.String("depths", "", "show the histogram of the depths of the deps of the main packages in args, or of all, format: text,json")
+	binSizeFile     = flag.String("binsize", "", "attribute the size of the binary of the main package in args to its deps: per package the bytes gone without it, its own bytes and the chain bringing it in, largest first. The file is the binary, go tool nm -size output or a bloaty -d symbols --csv report")
+	coverageFiles   = flag.String("coverage", "", "report per main package in args, or every one that ran, the subtrees of its deps linked in but never executed, from these comma separated coverage profiles, go tool covdata percent outputs or lists of the packages that ran, eg: from the binaries built with -cover running in production")
+	heaviest        = flag.Bool("heaviest", false, "rank the direct third-party imports of the main packages in args, or of all, by the packages only they bring in")
+	whyModule       = flag.String("why", "", "show per main package the import chain requiring this module, like go mod why -m")
+	migrationsFile  = flag.String("migrations", "", "report per main package which side of each import path migration of this file it is on, old, new or both, with the chain to the old path, from \"old_path new_path\" lines, eg: github.com/dgrijalva/jwt-go github.com/golang-jwt/jwt")
+	since           = flag.String("since", "", "list the packages added and removed since this git revision, its graph is loaded from a temporary worktree with go list -json -deps on the -load patterns, by default ./... or every module of its go.work")
+	baselineFile    = flag.String("baseline", "", "mark search results and rule violations as NEW or EXISTING in the go list -json output in this file, only new violations fail")
+	rulesFile       = flag.String("rules", "", "check the import rules in this JSON file, exit 1 on violations")
+	reportFormat    = flag.String("report", "", "write an audit document: stats, third-party deps per main package, unused packages, -internal and -rules violations, supported format: markdown,html")
+	exportFormat    = flag.String("export", "", "write the whole graph to stdout, or with -export <format> <from_package> <to_package> only the packages between them, supported format: cypher,sql,gexf or a format registered by a linked in export package, sql is an SQLite script to pipe into sqlite3")
+	anonymize       = flag.Bool("anonymize", false, "used with -export, replace the non-standard import paths by keyed hashes, to share the graph without leaking package names")
+	salt            = flag.String("salt", "", "used with -anonymize, key of the hashes, the same salt gives the same names across exports, random by default")
+	attestKey       = flag.String("attest", "", "write a signed in-toto attestation of the dependency closure of the main packages in args, or of all, one DSSE envelope per line, signed with this PKCS#8 PEM private key: ed25519, ECDSA or RSA")
+	rpcFile         = flag.String("rpc", "", "serve JSON-RPC queries on stdin/stdout, deps read from this go list -json output file")
+	watchFile       = flag.String("watch", "", "reload this go list -json output file when it changes, report main packages starting or stopping to depend on the args")
+	webhook         = flag.String("webhook", "", "used with -watch and -daemon, post the changes to this Slack-compatible webhook url")
+	daemonInterval  = flag.Duration("daemon", 0, "run go list on the -load patterns at this interval, eg: 15m, save the graphs something changed in to the -store, and print JSON events, or post them to -webhook, when the main packages depending on the args, the -rules violations or the module versions change")
+	indexerAddr     = flag.String("indexer", "", "keep the graph of the packages below the current directory in sync with its files, listing again only the packages whose files changed, and serve the JSON-RPC queries of -rpc on this unix socket path or host:port, eg: for editor plugins and pre-commit hooks")
+	indexInterval   = flag.Duration("indexinterval", time.Second, "used with -indexer, poll the files at this interval; each poll stats every file below the current directory, raise it for large trees")
+	httpAddr        = flag.String("http", "", "serve a web view of the graph on this address, eg: localhost:8080, focused on the first arg")
+	watchlist       = flag.String("watchlist", "", "used with -http and -rpc, compute once at startup the chains to the packages matching these comma separated patterns, eg: crypto/...,golang.org/x/crypto/..., so the main packages exposed to them are answered instantly")
+	limits          = flag.String("limits", "", "reject input over these limits, eg: packages=100000,path=512,imports=1000,deps=100000,record=1048576, \"default\" for generous ones, -federate uses the default ones unless set")
+	federateAddr    = flag.String("federate", "", "serve on this address an org wide index of the graphs uploaded per repository, see README")
+	concurrency     = flag.Int("j", 0, "max goroutines used to scan the graph, 0 means GOMAXPROCS")
+	graphDepth      = flag.Int("depth", 0, "used with -graph, only show packages at most this many imports away from the first package")
+	graphMaxNodes   = flag.Int("maxnodes", 0, "used with -graph, show at most this many packages, the others are replaced by a \"...N more\" node")
+	graphCollapse   = flag.Bool("collapse", false, "used with -graph, merge runs of packages with one importer and one import into one node")
+	graphSummarize  = flag.Int("summarize", 0, "used with -graph, keep the N packages with the most imports and importers and merge the others into one node per module, eg: golang.org/x/text (12 packages), so graphs of thousands of packages stay legible")
 	graphResultFile = flag.String("o", "/tmp/dep.svg", "used with -graph, result file path, supported type: svg,png,jpg,dot")
 )
 
+// toolOnly holds the packages only tools bring in, see -tools.
+var toolOnly map[string]bool
+
+// ignored reports whether a package matches -ignore, nil without it.
+var ignored func(pkg string) bool
+
+// showPackage reports whether p belongs in the results, see -nostd and
+// -tools.
+func showPackage(dg *depgraph.DepGraph, p string) bool {
+	if ignored != nil && ignored(p) {
+		return false
+	}
+	switch *toolsMode {
+	case "exclude":
+		if toolOnly[p] {
+			return false
+		}
+	case "only":
+		if !toolOnly[p] {
+			return false
+		}
+	}
+	if *thirdPartyOnly {
+		return dg.IsThirdParty(p)
+	}
+	return !*noStd || !dg.IsStandard(p)
+}
+
+// formatChain joins chain with arrows, marking the packages whose module
+// is replaced and, with -size, the source each package brings in. With
+// -bymodule consecutive packages of a module are shown as one entry, and
+// with -explain the chain is told as a sentence. With -pos the line of
+// every import of the chain follows.
+func formatChain(dg *depgraph.DepGraph, chain []string) string {
+	if *showPos {
+		return formatChainOnly(dg, chain) + importLines(dg, chain)
+	}
+	return formatChainOnly(dg, chain)
+}
+
+// importLines returns, for -pos, one "\n\tfile:line imports package" line
+// per import of chain whose position is known, relative to the current
+// directory when below it.
+func importLines(dg *depgraph.DepGraph, chain []string) string {
+	wd, _ := os.Getwd()
+	var b strings.Builder
+	for i := 1; i < len(chain); i++ {
+		e := dg.Edge(chain[i-1], chain[i])
+		if e == nil {
+			continue
+		}
+		for _, pos := range e.Pos {
+			if rel, err := filepath.Rel(wd, pos); err == nil && !strings.HasPrefix(rel, "..") {
+				pos = rel
+			}
+			fmt.Fprintf(&b, "\n\t%s imports %s", pos, chain[i])
+		}
+	}
+	return b.String()
+}
+
+// formatChainOnly is formatChain without the -pos lines.
+func formatChainOnly(dg *depgraph.DepGraph, chain []string) string {
+	names := make([]string, len(chain))
+	for i, p := range chain {
+		names[i] = p
+		if r := dg.Replacement(p); r != "" {
+			names[i] += " (=> " + r + ")"
+		}
+		if s := dg.TransitiveSize(p); *showSize && s.Weight() > 0 {
+			names[i] += fmt.Sprintf(" [%d %s]", s.Weight(), s.Unit())
+		}
+		names[i] += ownerNote(p)
+	}
+	if *byModule {
+		chain, names = collapseModules(dg, chain, names)
+	}
+	if *explain {
+		return explainChain(dg, chain, names)
+	}
+	return strings.Join(names, " -> ")
+}
+
+// mainLine is the line of a -main search for dep listing the main
+// package p, eg: main -> cmd/app -> dep.
+func mainLine(p, dep string) string {
+	deps := []string{"main", p + ownerNote(p)}
+	if p != dep {
+		deps = append(deps, dep+ownerNote(dep))
+	}
+	return strings.Join(deps, " -> ")
+}
+
+// selfLine is the first line of a plain search for dep, found in the input.
+func selfLine(dep string) string {
+	return "[self] -> " + dep + ownerNote(dep)
+}
+
+// dependentLine is the line of a plain search for dep listing p, which
+// depends on it, eg: [main] -> cmd/app -> dep or http -> net/http -> dep.
+func dependentLine(p, dep string, isMain, isTest bool) string {
+	name, shown := path.Base(p), p
+	if isMain {
+		name = "[main]"
+	} else if isTest {
+		name = "[test]"
+		shown = strings.TrimSuffix(p, ".test")
+	}
+	return strings.Join([]string{name, shown + ownerNote(p), dep + ownerNote(dep)}, " -> ")
+}
+
+// chainRoots returns the loaded packages matching -from, packages or
+// prefix/... patterns, sorted, or nil without -from.
+func chainRoots(dg *depgraph.DepGraph) (roots []string) {
+	if *from == "" {
+		return nil
+	}
+	under := depgraph.ByPrefix(strings.Split(*from, ","))
+	for _, p := range dg.Packages() {
+		if under(p) != "" {
+			roots = append(roots, p)
+		}
+	}
+	if len(roots) == 0 {
+		fail(exitNotFound, "-from %v matches no package", *from)
+	}
+	return
+}
+
+// cheapestChains streams, for -cheapest, the chain main -> p -> ... -> dep
+// of every main package p depending on dep whose packages weigh the least,
+// or the chain of SearchChain when no import leads to dep.
+func cheapestChains(dg *depgraph.DepGraph, dep string) <-chan []string {
+	ch := make(chan []string)
+	go func() {
+		defer close(ch)
+		for p := range dg.SearchMainStream(dep, nil) {
+			chain, _ := dg.CheapestChain(p, dep)
+			if chain == nil {
+				chain = dg.SearchChainFrom([]string{p}, dep)[0]
+			}
+			ch <- append([]string{"main"}, chain...)
+		}
+	}()
+	return ch
+}
+
+// logGaps tells which packages of the input to regenerate for the "..."
+// chain from main to dep, and makes main exit with exitPartial since a
+// printed result goes through packages missing from the input.
+func logGaps(dg *depgraph.DepGraph, main, dep string) {
+	var where []string
+	for _, gap := range dg.ChainGaps(main, dep) {
+		switch {
+		case !gap.Loaded:
+			where = append(where, gap.Package+" (no record)")
+		case len(gap.Missing) > 0:
+			where = append(where, fmt.Sprintf("%s (imports without a record: %s)", gap.Package, strings.Join(gap.Missing, " ")))
+		default:
+			where = append(where, gap.Package+" (lists it in Deps but no import leads to it)")
+		}
+	}
+	warn(exitPartial, "no import chain from %v to %v in the input, check: %s", main, dep, strings.Join(where, ", "))
+}
+
+// collapseModules merges the runs of packages of chain, labeled names,
+// belonging to the same module, or to the standard library, into one
+// "module (N packages)" entry. The ends of the chain stay on their own. It
+// returns the first package and the label of every entry.
+func collapseModules(dg *depgraph.DepGraph, chain, names []string) (firsts, labels []string) {
+	first := 0
+	if len(chain) > 0 && chain[0] == "main" {
+		first = 1
+	}
+	moduleOf := func(i int) string {
+		if i <= first || i == len(chain)-1 {
+			return ""
+		}
+		if m := dg.Module(chain[i]); m != nil {
+			return m.Path
+		}
+		if chain[i] != "..." && dg.IsStandard(chain[i]) {
+			return "std"
+		}
+		return ""
+	}
+	for i := 0; i < len(chain); {
+		j := i + 1
+		if m := moduleOf(i); m != "" {
+			for j < len(chain) && moduleOf(j) == m {
+				j++
+			}
+		}
+		firsts = append(firsts, chain[i])
+		if j-i == 1 {
+			labels = append(labels, names[i])
+		} else {
+			labels = append(labels, fmt.Sprintf("%s (%d packages)", moduleOf(i), j-i))
+		}
+		i = j
+	}
+	return
+}
+
+// explainChain tells chain, whose packages are labeled names, as a
+// sentence to paste in a ticket, eg: "main package cmd/api imports
+// pkg/auth, which imports github.com/x/jwt (transitive, 2 imports away)."
+func explainChain(dg *depgraph.DepGraph, chain, names []string) string {
+	if len(chain) > 1 && chain[0] == "main" {
+		chain, names = chain[1:], names[1:]
+	}
+	for i, p := range chain {
+		if p == "..." {
+			names[i] = "packages missing from the input"
+		}
+	}
+	switch {
+	case dg.IsMainPackage(chain[0]):
+		names[0] = "main package " + names[0]
+	case dg.IsTestPackage(chain[0]):
+		names[0] = "test binary " + names[0]
+	}
+	if len(chain) == 1 {
+		return names[0] + " is the package itself."
+	}
+	s := names[0] + " imports " + names[1]
+	for _, name := range names[2:] {
+		s += ", which imports " + name
+	}
+	if len(chain) == 2 {
+		return s + " directly."
+	}
+	return fmt.Sprintf("%s (transitive, %d imports away).", s, len(chain)-1)
+}
+
+// standaloneReport reports whether a flag asks for a report that runs
+// without package names.
+func standaloneReport() bool {
+	return *save != "" || *listSnapshots || *growth != "" || *diffSnapshot != "" || *unused || *untested || *since != "" || *conflicts || *validateFormat != "" || *dangling || *majors || *heaviest || *binSizeFile != "" || *coverageFiles != "" || *via != "" || *names || *external || *depths != "" || *top > 0 || *unique || *overlap || *cgo || *unsafeUse || *blastFile != "" || *internal || *vulnFile != "" || *osv || *depsDev || *freshness != "" || *retracted || *licenseFile != "" ||
+		*goSumFile != "" || *upgrade != "" || *whyModule != "" || *migrationsFile != "" || *rulesFile != "" ||
+		*exportFormat != "" || *attestKey != "" || *writeIndexFile != "" || *reportFormat != "" || *rpcFile != "" || *httpAddr != "" || *federateAddr != "" || *daemonInterval > 0 || *indexerAddr != ""
+}
+
+func loadGraph() (*depgraph.DepGraph, error) {
+	var input io.Reader
+	format, build := *inputFormat, buildProfile()
+	switch {
+	case *at != "":
+		r, meta, err := openSnapshot(*at)
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		input, format, build = r, meta.Format, meta.Build
+		log.Printf("loading snapshot %v of %v", meta.Name, meta.Time.Format(time.RFC3339))
+	case *load != "":
+		out, err := listPackages(".", build)
+		if err != nil {
+			return nil, fmt.Errorf("go list %v: %v", *load, err)
+		}
+		input = strings.NewReader(out)
+	default:
+		f := openInput()
+		defer f.Close()
+		input = f
+	}
+	if *save != "" {
+		tee, err := teeSnapshot(input)
+		if err != nil {
+			return nil, err
+		}
+		input = tee
+	}
+	return readGraph(input, format, build)
+}
+
+// readGraph loads the input, in format, listed with build, and prepares
+// the graph.
+func readGraph(input io.Reader, format string, build depgraph.BuildProfile) (*depgraph.DepGraph, error) {
+	if format == "" {
+		format = "golist"
+	}
+	dg, err := newGraph(build)
+	if err != nil {
+		return nil, err
+	}
+	if *lenient && format == "golist" {
+		skipped, err := dg.LoadLenient(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range skipped {
+			log.Printf("skipped record at %v", e)
+		}
+	} else if l, err := loader.Get(format); err != nil {
+		return nil, err
+	} else if err := l.Load(input, dg); err != nil {
+		return nil, err
+	}
+	if *goModFile != "" {
+		if err := applyGoMod(dg); err != nil {
+			return nil, err
+		}
+	}
+	dg.DetectTools()
+	return prepareGraph(dg), nil
+}
+
+// newGraph returns an empty graph loading packages as the input flags say.
+func newGraph(build depgraph.BuildProfile) (*depgraph.DepGraph, error) {
+	dg := &depgraph.DepGraph{}
+	dg.NormalizeVendor(*unvendor)
+	dg.CountLines(*loc)
+	dg.RecordImportPos(*showPos)
+	classify, err := depgraph.ParseTestClassifier(*testBin)
+	if err != nil {
+		return nil, err
+	}
+	dg.SetTestClassifier(classify)
+	dg.SetBuild(build)
+	dg.SetLimits(loadLimits())
+	return dg, nil
+}
+
+// prepareGraph applies the query flags to a freshly loaded graph.
+func prepareGraph(dg *depgraph.DepGraph) *depgraph.DepGraph {
+	if *prune != "" {
+		dg.IgnoreDeps(*ignoreDeps)
+		dg = dg.Prune(pruned(dg))
+	}
+	if ignored != nil && !*ignoreAtQuery {
+		dg.IgnoreDeps(*ignoreDeps)
+		dg = dg.Prune(ignored)
+	}
+	if *groupBy != "" {
+		dg.IgnoreDeps(*ignoreDeps)
+		dg = dg.Group(grouper(dg))
+	}
+	dg.SetConcurrency(*concurrency)
+	dg.IgnoreDeps(*ignoreDeps)
+	if *firstParty != "" {
+		dg.FirstParty(strings.Split(*firstParty, ","))
+	}
+	if *cheapest != "" {
+		if err := dg.WeightBySize(*cheapest); err != nil {
+			fail(exitUsage, "-cheapest: %v", err)
+		}
+	}
+	return dg.Freeze()
+}
+
+// pruned returns whether a package is one of the comma separated -prune
+// list: "std" for the standard library, or a path prefix.
+func pruned(dg *depgraph.DepGraph) func(pkg string) bool {
+	var prefixes []string
+	std := false
+	for _, p := range strings.Split(*prune, ",") {
+		if p == "std" {
+			std = true
+		} else {
+			prefixes = append(prefixes, p)
+		}
+	}
+	under := depgraph.ByPrefix(prefixes)
+	return func(pkg string) bool {
+		return std && dg.IsStandard(pkg) || under(pkg) != ""
+	}
+}
+
+// loadLimits parses -limits: "default" for depgraph.DefaultLimits, or a
+// comma separated list of packages=N, path=N, imports=N, deps=N and
+// record=N, the max bytes of a record.
+func loadLimits() (l depgraph.Limits) {
+	if *limits == "" {
+		return
+	}
+	for _, kv := range strings.Split(*limits, ",") {
+		if kv == "default" {
+			l = depgraph.DefaultLimits
+			continue
+		}
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			fail(exitUsage, "-limits: %q is not name=N", kv)
+		}
+		n, err := strconv.ParseInt(kv[i+1:], 10, 64)
+		if err != nil || n < 0 {
+			fail(exitUsage, "-limits: bad value in %q", kv)
+		}
+		switch kv[:i] {
+		case "packages":
+			l.MaxPackages = int(n)
+		case "path":
+			l.MaxPathLen = int(n)
+		case "imports":
+			l.MaxImports = int(n)
+		case "deps":
+			l.MaxDeps = int(n)
+		case "record":
+			l.MaxRecordSize = n
+		default:
+			fail(exitUsage, "-limits: unknown limit %q, supported: packages,path,imports,deps,record", kv[:i])
+		}
+	}
+	return
+}
+
+// grouper returns the Grouper *groupBy names: "module" or a comma
+// separated list of path prefixes.
+func grouper(dg *depgraph.DepGraph) depgraph.Grouper {
+	if *groupBy == "module" {
+		return dg.ByModule()
+	}
+	return depgraph.ByPrefix(strings.Split(*groupBy, ","))
+}
+
 func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, usage, os.Args[0])
 		flag.PrintDefaults()
 	}
 	flag.Parse()
-	if flag.NArg() == 0 && !*unused {
+	parseFormat()
+	if *ignorePatterns != "" {
+		ignored = depgraph.Ignored(strings.Split(*ignorePatterns, ","))
+	}
+	loadOwners()
+	loadLabels()
+	parseSort()
+	switch *toolsMode {
+	case "", "include", "exclude", "only":
+	default:
+		fail(exitUsage, "unknown -tools %q, supported: include,exclude,only", *toolsMode)
+	}
+	switch *errorFormat {
+	case "", "json":
+	default:
+		fail(exitUsage, "unknown -errors format %q, supported: json", *errorFormat)
+	}
+	defer exit()
+	if flag.NArg() == 0 && !standaloneReport() {
 		flag.Usage()
-		return
+		os.Exit(exitUsage)
 	}
 	if *chain {
 		*onlyMain = true
 	}
-	dg, err := depgraph.LoadDeps(os.Stdin)
+	if *federateAddr != "" {
+		serveFederation()
+		return
+	}
+	if *daemonInterval > 0 {
+		runDaemon()
+		return
+	}
+	if *indexerAddr != "" {
+		runIndexer()
+		return
+	}
+	if *listSnapshots {
+		printSnapshots()
+		return
+	}
+	if *growth != "" {
+		reportGrowth()
+		return
+	}
+	if *multiGraphs != "" {
+		searchMulti()
+		return
+	}
+	if *shardBy != "" {
+		searchSharded()
+		return
+	}
+	if *indexFile != "" {
+		searchIndexFile()
+		return
+	}
+	dg, err := loadGraph()
 	if err != nil {
-		log.Fatalln("LoadDeps failed", err)
+		fail(exitParse, "LoadDeps failed %v", err)
+	}
+	log.Printf("successfully load %d packages (%d main packages, %d test packages, %d standard packages)",
+		dg.CountAll(), dg.CountMain(), dg.CountTest(), dg.CountStandard())
+	if b := dg.Build().String(); b != "" {
+		log.Printf("build profile: %s", b)
+	}
+	if s := dg.TotalSize(); s.Lines > 0 {
+		log.Printf("source size: %d files, %d lines", s.Files, s.Lines)
+	} else if s.Files > 0 {
+		log.Printf("source size: %d files", s.Files)
+	}
+	toolOnly = make(map[string]bool)
+	for _, p := range dg.ToolOnly() {
+		toolOnly[p] = true
+	}
+	if n := len(toolOnly); n > 0 && (*toolsMode == "" || *toolsMode == "include") {
+		log.Printf("%d packages only tools bring in, see -tools", n)
+	}
+	if n := len(dg.Conflicts()); n > 0 && !*conflicts {
+		log.Printf("%d packages listed more than once with different imports or deps, see -conflicts", n)
+	}
+	if n := len(dg.Dangling()); n > 0 && !*dangling {
+		log.Printf("%d packages referenced but missing from the input, chains through them show \"...\", see -dangling", n)
+	}
+	if *baselineFile != "" {
+		loadBaseline()
+	}
+	if *save != "" {
+		saveSnapshot(dg)
+		return
+	}
+	if *since != "" {
+		reportSince(dg)
+		return
+	}
+	if *diffSnapshot != "" {
+		reportSnapshotDiff(dg)
+		return
+	}
+	if *conflicts {
+		reportConflicts(dg)
+		return
+	}
+	if *dangling {
+		reportDangling(dg)
+		return
+	}
+	if *validateFormat != "" {
+		reportValidate(dg)
+		return
+	}
+	if *watchFile != "" {
+		watch(dg)
+		return
+	}
+	if *httpAddr != "" {
+		serveWeb(dg)
+		return
+	}
+	if *rpcFile != "" {
+		serveRPC(dg)
+		return
 	}
-	log.Printf("successfully load %d packages (%d main packages, %d test packages)",
-		dg.CountAll(), dg.CountMain(), dg.CountTest())
 	if *unused {
 		log.Println("unused packages:")
-		fmt.Println(strings.Join(dg.ListUnUsed(), "\n"))
+		for _, p := range dg.ListUnUsed() {
+			if showPackage(dg, p) {
+				printPackage(dg, p, nil, p+ownerNote(p))
+			}
+		}
+		return
+	}
+	if *untested {
+		reportUntested(dg)
+		return
+	}
+	if *cgo {
+		reportCgo(dg)
+		return
+	}
+	if *unsafeUse {
+		reportUnsafe(dg)
+		return
+	}
+	if *exportFormat != "" {
+		exportGraph(dg)
+		return
+	}
+	if *attestKey != "" {
+		writeAttestations(dg)
+		return
+	}
+	if *writeIndexFile != "" {
+		writeIndex(dg)
+		return
+	}
+	if *reportFormat != "" {
+		writeReport(dg)
+		return
+	}
+	if *rulesFile != "" {
+		checkRules(dg)
+		return
+	}
+	if *internal {
+		checkInternal(dg)
+		return
+	}
+	if *canImport {
+		checkCanImport(dg)
+		return
+	}
+	if *whyModule != "" {
+		explainModule(dg)
+		return
+	}
+	if *migrationsFile != "" {
+		reportMigrations(dg)
+		return
+	}
+	if *majors {
+		reportMajorConflicts(dg)
+		return
+	}
+	if *unique {
+		reportUniqueDeps(dg)
+		return
+	}
+	if *overlap {
+		reportOverlaps(dg)
+		return
+	}
+	if *heaviest {
+		reportHeaviest(dg, flag.Args())
+		return
+	}
+	if *binSizeFile != "" {
+		reportBinSize(dg)
+		return
+	}
+	if *coverageFiles != "" {
+		reportCoverage(dg)
+		return
+	}
+	if *embeds {
+		reportEmbeds(dg, flag.Args())
+		return
+	}
+	if *adoption {
+		reportAdoption(dg, flag.Args())
+		return
+	}
+	if *blastFile != "" {
+		reportBlast(dg)
+		return
+	}
+	if *impact {
+		reportImpact(dg, flag.Args())
+		return
+	}
+	if *via != "" {
+		reportOnlyVia(dg)
+		return
+	}
+	if *names {
+		reportNameCollisions(dg, flag.Args())
+		return
+	}
+	if *external {
+		reportExternal(dg, flag.Args())
+		return
+	}
+	if *depths != "" {
+		reportDepths(dg, flag.Args())
+		return
+	}
+	if *top > 0 {
+		reportTop(dg)
+		return
+	}
+	if *upgrade != "" {
+		reportUpgrade(dg)
 		return
 	}
+	if *goSumFile != "" {
+		checkGoSum(dg)
+		return
+	}
+	if *licenseFile != "" {
+		reportLicenses(dg)
+		return
+	}
+	if *depsDev {
+		reportDepsDev(dg, flag.Args())
+		return
+	}
+	if *freshness != "" {
+		reportFreshness(dg)
+		return
+	}
+	if *retracted {
+		reportRetracted(dg)
+		return
+	}
+	if *vulnFile != "" || *osv {
+		reportVulns(dg)
+		return
+	}
+	roots := chainRoots(dg)
 	if *graph {
-		result := dg.SearchGraph(flag.Arg(0), flag.Arg(1))
-		resultToSvg(result)
+		opts := depgraph.PruneOptions{
+			MaxDepth:  *graphDepth,
+			MaxNodes:  *graphMaxNodes,
+			Collapse:  *graphCollapse,
+			Summarize: *graphSummarize,
+			GroupBy:   dg.ByModule(),
+		}
+		if *reverse {
+			result := dg.ReverseGraph(flag.Arg(0))
+			resultToSvg(depgraph.PruneGraphFrom(result, depgraph.GraphRoots(result), flag.Arg(0), opts))
+			return
+		}
+		if flag.NArg() == 2 {
+			result := dg.SearchGraph(flag.Arg(0), flag.Arg(1))
+			resultToSvg(depgraph.PruneGraph(result, flag.Arg(0), flag.Arg(1), opts))
+			return
+		}
+		starts := mainsOrAll(dg, roots)
+		result, _ := dg.SearchGraphFrom(starts, flag.Arg(0))
+		resultToSvg(depgraph.PruneGraphFrom(result, starts, flag.Arg(0), opts))
 		return
 	}
-	for _, dep := range flag.Args() {
-		if *chain {
-			chains := dg.SearchChain(dep)
+	for i, dep := range flag.Args() {
+		searchTarget, searchIndex = dep, i
+		if r := dg.Replacement(dep); r != "" {
+			log.Printf("%v is replaced by %v", dep, r)
+		}
+		if *reverse {
+			chains := dg.ReverseChains(dep)
 			if len(chains) == 0 {
-				log.Printf("%v not found", dep)
+				warn(exitNotFound, "%v not found", dep)
 			}
 			for _, chain := range chains {
-				fmt.Println(strings.Join(chain, " -> "))
+				if showPackage(dg, chain[0]) {
+					printPackage(dg, chain[0], chain, mark(chain[0], dep)+formatChain(dg, chain))
+				}
+			}
+		} else if roots != nil {
+			chains := dg.SearchChainFrom(roots, dep)
+			if len(chains) == 0 {
+				warn(exitNotFound, "%v not found", dep)
+			}
+			for _, chain := range chains {
+				if showPackage(dg, chain[0]) {
+					printPackage(dg, chain[0], chain, mark(chain[0], dep)+formatChain(dg, chain))
+					if len(chain) > 1 && chain[len(chain)-2] == "..." {
+						logGaps(dg, chain[0], dep)
+					}
+				}
+			}
+		} else if *chain || *cheapest != "" {
+			chains := dg.SearchChainStream(dep, nil)
+			if *cheapest != "" {
+				chains = cheapestChains(dg, dep)
+			}
+			found := false
+			for chain := range chains {
+				found = true
+				if showPackage(dg, chain[1]) {
+					printPackage(dg, chain[1], chain, mark(chain[1], dep)+formatChain(dg, chain))
+					if len(chain) > 2 && chain[len(chain)-2] == "..." {
+						logGaps(dg, chain[1], dep)
+					}
+				}
+			}
+			if !found {
+				warn(exitNotFound, "%v not found", dep)
 			}
 		} else if *onlyMain {
-			packages := dg.SearchMain(dep)
-			if len(packages) == 0 {
-				log.Printf("%v not found", dep)
-			}
-			for _, p := range packages {
-				deps := []string{"main", p}
-				if p != dep {
-					deps = append(deps, dep)
+			found := false
+			for p := range dg.SearchMainStream(dep, nil) {
+				found = true
+				if !showPackage(dg, p) {
+					continue
 				}
-				fmt.Println(strings.Join(deps, " -> "))
+				printPackage(dg, p, nil, mark(p, dep)+mainLine(p, dep))
+			}
+			if !found {
+				warn(exitNotFound, "%v not found", dep)
 			}
 		} else if *onlyTest {
-			packages := dg.SearchTest(dep)
-			if len(packages) == 0 {
-				log.Printf("%v not found", dep)
+			chains := dg.TestChains(dep)
+			if len(chains) == 0 {
+				warn(exitNotFound, "%v not found", dep)
 			}
-			for _, p := range packages {
-				p = strings.TrimSuffix(p, ".test")
-				fmt.Println(strings.Join([]string{"test", p, dep}, " -> "))
+			for _, chain := range chains {
+				if showPackage(dg, chain[0]) {
+					line := mark(chain[0], dep) + "test -> " + formatChain(dg, chain[1:])
+					if *explain {
+						line = mark(chain[0], dep) + formatChain(dg, chain)
+					}
+					printPackage(dg, chain[0], chain, line)
+				}
 			}
 		} else {
 			if dg.Exists(dep) {
-				fmt.Println(strings.Join([]string{"[self]", dep}, " -> "))
-			}
-			packages := dg.SearchAll(dep)
-			if len(packages) == 0 && !dg.Exists(dep) {
-				log.Printf("%v not found", dep)
-			}
-			for _, p := range packages {
-				name := path.Base(p)
-				if dg.IsMainPackage(p) {
-					name = "[main]"
-				} else if dg.IsTestPackage(p) {
-					name = "[test]"
-					p = strings.TrimSuffix(p, ".test")
+				printPackage(dg, dep, nil, mark(dep, dep)+selfLine(dep))
+			}
+			found := dg.Exists(dep)
+			for p := range dg.SearchAllStream(dep, nil) {
+				found = true
+				if !showPackage(dg, p) {
+					continue
 				}
-				fmt.Println(strings.Join([]string{name, p, dep}, " -> "))
+				printPackage(dg, p, nil, mark(p, dep)+dependentLine(p, dep, dg.IsMainPackage(p), dg.IsTestPackage(p)))
+			}
+			if !found {
+				warn(exitNotFound, "%v not found", dep)
 			}
 		}
 	}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// run runs name with args in dir, with env added to the environment, and
+// returns its trimmed output.
+func run(dir string, env []string, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	if env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// buildProfile returns the build profile set by -goos, -goarch, -tags
+// and -goflags.
+func buildProfile() depgraph.BuildProfile {
+	p := depgraph.BuildProfile{GOOS: *goos, GOARCH: *goarch, GOFLAGS: *goflags}
+	if *tags != "" {
+		p.Tags = strings.Split(*tags, ",")
+	}
+	return p
+}
+
+// listPackages runs go list -json -deps in dir with build profile p, on
+// the -load patterns, ./... by default, and without -deps with -nodeps.
+// In a go.work workspace the default is every module of the workspace, so
+// all of them land in one graph and imports between them resolve to the
+// workspace copies, which go list marks as main modules.
+func listPackages(dir string, p depgraph.BuildProfile) (string, error) {
+	env := buildEnv(p)
+	patterns := []string{"./..."}
+	if *load != "" {
+		patterns = strings.Split(*load, ",")
+	} else if work, err := run(dir, env, "go", "env", "GOWORK"); err != nil {
+		return "", err
+	} else if work != "" && work != "off" {
+		modules, err := run(dir, env, "go", "list", "-m", "-f", "{{.Path}}/...")
+		if err != nil {
+			return "", err
+		}
+		patterns = strings.Fields(modules)
+	}
+	args := []string{"list", "-json"}
+	if !*noDeps {
+		args = append(args, "-deps")
+	}
+	if len(p.Tags) > 0 {
+		args = append(args, "-tags="+strings.Join(p.Tags, ","))
+	}
+	return run(dir, env, "go", append(args, patterns...)...)
+}
+
+// buildEnv returns the environment of the go commands listing packages
+// with build profile p.
+func buildEnv(p depgraph.BuildProfile) []string {
+	var env []string
+	if p.GOOS != "" {
+		env = append(env, "GOOS="+p.GOOS)
+	}
+	if p.GOARCH != "" {
+		env = append(env, "GOARCH="+p.GOARCH)
+	}
+	if p.GOFLAGS != "" {
+		env = append(env, "GOFLAGS="+p.GOFLAGS)
+	}
+	return env
+}
+
+// listDirs runs go list -e -json -deps in dir with build profile p on the
+// packages of dirs, eg: ./pkg/auth. With -e directories without a package
+// left, eg: all its files excluded by build tags, don't fail the run.
+func listDirs(dir string, p depgraph.BuildProfile, dirs []string) (string, error) {
+	args := []string{"list", "-e", "-json", "-deps"}
+	if len(p.Tags) > 0 {
+		args = append(args, "-tags="+strings.Join(p.Tags, ","))
+	}
+	return run(dir, buildEnv(p), "go", append(args, dirs...)...)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/gosum"
+)
+
+// applyGoMod registers the replace directives of *goModFile in dg and
+// marks the packages of its tool directives as tools.
+func applyGoMod(dg *depgraph.DepGraph) error {
+	b, err := ioutil.ReadFile(*goModFile)
+	if err != nil {
+		return err
+	}
+	replaces, err := gosum.ParseReplaces(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	for old, new := range replaces {
+		dg.AddReplace(old, new)
+	}
+	tools, err := gosum.ParseTools(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	for _, t := range tools {
+		dg.AddTool(t)
+	}
+	return nil
+}
+
+// checkGoSum compares the build against the go.sum at *goSumFile and the
+// go.mod next to it, if any, and exits with status 1 on disagreement.
+func checkGoSum(dg *depgraph.DepGraph) {
+	f, err := os.Open(*goSumFile)
+	if err != nil {
+		fail(exitParse, "open go.sum failed %v", err)
+	}
+	sum, err := gosum.ParseSum(f)
+	f.Close()
+	if err != nil {
+		fail(exitParse, "parse go.sum failed %v", err)
+	}
+	var requires map[string]string
+	if f, err := os.Open(filepath.Join(filepath.Dir(*goSumFile), "go.mod")); err == nil {
+		requires, err = gosum.ParseRequires(f)
+		f.Close()
+		if err != nil {
+			fail(exitParse, "parse go.mod failed %v", err)
+		}
+	}
+	issues := gosum.Check(dg, sum, requires)
+	if len(issues) == 0 {
+		log.Println("build matches go.sum")
+		return
+	}
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	fail(exitViolation, "%d go.sum issues", len(issues))
+}
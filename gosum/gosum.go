@@ -0,0 +1,202 @@
+// Package gosum cross-checks the modules a loaded package graph was built
+// from against the go.sum and go.mod files that are supposed to describe
+// them.
+package gosum
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/semver"
+)
+
+// Version identifies a module version.
+type Version struct {
+	Path    string
+	Version string
+}
+
+func (v Version) String() string {
+	return v.Path + "@" + v.Version
+}
+
+// Sum holds the module versions go.sum has a content hash for; entries
+// only hashing a go.mod file are kept apart.
+type Sum struct {
+	Modules map[Version]bool
+	GoMods  map[Version]bool
+}
+
+// ParseSum reads a go.sum file.
+func ParseSum(r io.Reader) (*Sum, error) {
+	s := &Sum{Modules: make(map[Version]bool), GoMods: make(map[Version]bool)}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("go.sum:%d: malformed line", n)
+		}
+		if v := strings.TrimSuffix(fields[1], "/go.mod"); v != fields[1] {
+			s.GoMods[Version{fields[0], v}] = true
+		} else {
+			s.Modules[Version{fields[0], v}] = true
+		}
+	}
+	return s, sc.Err()
+}
+
+// ParseRequires returns the module versions required by a go.mod file,
+// keyed by module path.
+func ParseRequires(r io.Reader) (map[string]string, error) {
+	requires := make(map[string]string)
+	err := parseDirective(r, "require", func(fields []string) {
+		if len(fields) >= 2 {
+			requires[strings.Trim(fields[0], `"`)] = fields[1]
+		}
+	})
+	return requires, err
+}
+
+// ParseReplaces returns the replace directives of a go.mod file as a map
+// from replaced module path to replacement, which is a module path or a
+// local directory. Versions are ignored.
+func ParseReplaces(r io.Reader) (map[string]string, error) {
+	replaces := make(map[string]string)
+	err := parseDirective(r, "replace", func(fields []string) {
+		for i, f := range fields {
+			if f == "=>" && i > 0 && i+1 < len(fields) {
+				replaces[strings.Trim(fields[0], `"`)] = strings.Trim(fields[i+1], `"`)
+			}
+		}
+	})
+	return replaces, err
+}
+
+// ParseTools returns the packages of the tool directives of a go.mod
+// file, added by go get -tool.
+func ParseTools(r io.Reader) (tools []string, err error) {
+	err = parseDirective(r, "tool", func(fields []string) {
+		tools = append(tools, strings.Trim(fields[0], `"`))
+	})
+	return
+}
+
+// parseDirective calls fn with the fields following directive in a go.mod
+// file, for single line directives and for each line of a block.
+func parseDirective(r io.Reader, directive string, fn func(fields []string)) error {
+	sc := bufio.NewScanner(r)
+	inBlock := false
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			continue
+		case !inBlock && fields[0] == directive:
+			if len(fields) == 2 && fields[1] == "(" {
+				inBlock = true
+				continue
+			}
+			fields = fields[1:]
+		case !inBlock:
+			continue
+		}
+		fn(fields)
+	}
+	return sc.Err()
+}
+
+// Issue is a disagreement between the build and the module metadata.
+type Issue struct {
+	Module  Version
+	Problem string
+}
+
+func (i Issue) String() string {
+	return i.Module.String() + ": " + i.Problem
+}
+
+// canonical drops the +incompatible suffix of v, so v2.0.0+incompatible
+// and v2.0.0, the same release of a module without go.mod, match.
+func canonical(v Version) Version {
+	return Version{v.Path, strings.TrimSuffix(v.Version, "+incompatible")}
+}
+
+func canonicalSet(versions map[Version]bool) map[Version]bool {
+	set := make(map[Version]bool, len(versions))
+	for v := range versions {
+		set[canonical(v)] = true
+	}
+	return set
+}
+
+// Check compares the non-main modules of g against sum and, if not nil,
+// the go.mod requires. It flags modules in the build that go.sum has no
+// hash for, or only the hash of the go.mod of, go.sum hashes for modules
+// neither the build uses nor go.mod requires, and required versions
+// differing from the built ones. Versions are matched by path and version
+// whatever the kind of their go.sum lines, +incompatible or not.
+func Check(g *depgraph.DepGraph, sum *Sum, requires map[string]string) (issues []Issue) {
+	modules, goMods := canonicalSet(sum.Modules), canonicalSet(sum.GoMods)
+	builtPaths := make(map[string]bool)
+	for _, m := range g.Modules() {
+		if m.Main {
+			continue
+		}
+		required := Version{m.Path, m.Version}
+		used := required
+		if m.Replace != nil {
+			used = Version{m.Replace.Path, m.Replace.Version}
+		}
+		builtPaths[m.Path] = true
+		if used.Version == "" {
+			continue // replaced by a directory, nothing to hash
+		}
+		builtPaths[used.Path] = true
+		switch {
+		case goMods[canonical(used)] && !modules[canonical(used)]:
+			issues = append(issues, Issue{used, "used by the build but go.sum only hashes its go.mod"})
+		case !modules[canonical(used)]:
+			issues = append(issues, Issue{used, "used by the build but missing from go.sum"})
+		}
+		if v, ok := requires[m.Path]; ok && semver.Compare(v, required.Version) != 0 {
+			issues = append(issues, Issue{required, "go.mod requires " + v})
+		}
+	}
+	for v := range sum.Modules {
+		// go mod tidy keeps the hashes of other versions of the modules
+		// of the build, and of the modules go.mod requires, for the
+		// module graph and older go commands
+		if _, required := requires[v.Path]; !builtPaths[v.Path] && !required {
+			issues = append(issues, Issue{v, "in go.sum but not used by the build"})
+		}
+	}
+	for path, v := range requires {
+		if !builtPaths[path] {
+			issues = append(issues, Issue{Version{path, v}, "required by go.mod but not used by the build"})
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Module.Path != issues[j].Module.Path {
+			return issues[i].Module.Path < issues[j].Module.Path
+		}
+		if issues[i].Module.Version != issues[j].Module.Version {
+			return issues[i].Module.Version < issues[j].Module.Version
+		}
+		return issues[i].Problem < issues[j].Problem
+	})
+	return
+}
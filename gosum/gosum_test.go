@@ -0,0 +1,197 @@
+package gosum
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+const goSum = `example.com/a v1.0.0 h1:aaa=
+example.com/a v1.0.0/go.mod h1:bbb=
+example.com/stale v0.1.0 h1:ccc=
+example.com/old v1.0.0/go.mod h1:ddd=
+`
+
+const goMod = `module example.com/app
+
+go 1.16
+
+require example.com/a v1.0.0
+
+require (
+	example.com/b v1.2.0 // indirect
+	example.com/gone v0.0.1
+)
+`
+
+func TestCheck(t *testing.T) {
+	sum, err := ParseSum(strings.NewReader(goSum))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sum.Modules) != 2 || len(sum.GoMods) != 2 {
+		t.Error("parse error", sum)
+	}
+	requires, err := ParseRequires(strings.NewReader(goMod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requires) != 3 || requires["example.com/b"] != "v1.2.0" {
+		t.Error("parse error", requires)
+	}
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/app", Name: "main",
+		Module: &depgraph.Module{Path: "example.com/app", Main: true}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/a", Name: "a",
+		Module: &depgraph.Module{Path: "example.com/a", Version: "v1.0.0"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/b/x", Name: "x",
+		Module: &depgraph.Module{Path: "example.com/b", Version: "v1.3.0"}})
+	var got []string
+	for _, issue := range Check(dg, sum, requires) {
+		got = append(got, issue.String())
+	}
+	expect := []string{
+		"example.com/b@v1.3.0: go.mod requires v1.2.0",
+		"example.com/b@v1.3.0: used by the build but missing from go.sum",
+		"example.com/gone@v0.0.1: required by go.mod but not used by the build",
+		"example.com/stale@v0.1.0: in go.sum but not used by the build",
+	}
+	if strings.Join(got, "\n") != strings.Join(expect, "\n") {
+		t.Error("result error:\n" + strings.Join(got, "\n"))
+	}
+	if _, err := ParseSum(strings.NewReader("bad line\n")); err == nil {
+		t.Error("malformed go.sum should fail")
+	}
+}
+
+func TestCheckRealSum(t *testing.T) {
+	// the go.sum of this module, with the go-graphviz build of -tags graphviz
+	f, err := os.Open("testdata/go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sum, err := ParseSum(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a module without go.mod past v1 is listed +incompatible
+	sum.Modules[Version{"example.com/legacy", "v2.3.0+incompatible"}] = true
+	sum.GoMods[Version{"example.com/legacy", "v2.3.0+incompatible"}] = true
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "github.com/ma6174/go_dep_search", Name: "main",
+		Module: &depgraph.Module{Path: "github.com/ma6174/go_dep_search", Main: true}})
+	for _, m := range []depgraph.Module{
+		{Path: "github.com/corona10/goimagehash", Version: "v1.0.2"},
+		{Path: "github.com/fogleman/gg", Version: "v1.3.0"},
+		{Path: "github.com/goccy/go-graphviz", Version: "v0.0.9"},
+		{Path: "github.com/golang/freetype", Version: "v0.0.0-20170609003504-e2365dfdc4a0"},
+		{Path: "github.com/nfnt/resize", Version: "v0.0.0-20160724205520-891127d8d1b5"},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1"},
+		{Path: "golang.org/x/image", Version: "v0.0.0-20200119044424-58c23975cae1"},
+		// only in the module graph, go.sum just hashes its go.mod
+		{Path: "golang.org/x/text", Version: "v0.3.0"},
+		{Path: "example.com/legacy", Version: "v2.3.0+incompatible"},
+	} {
+		m := m
+		dg.Add(depgraph.DepInfo{ImportPath: m.Path, Name: "p", Module: &m})
+	}
+	requires := map[string]string{
+		"github.com/goccy/go-graphviz": "v0.0.9",
+		"example.com/legacy":           "v2.3.0",
+	}
+	var got []string
+	for _, issue := range Check(dg, sum, requires) {
+		got = append(got, issue.String())
+	}
+	expect := "golang.org/x/text@v0.3.0: used by the build but go.sum only hashes its go.mod"
+	if strings.Join(got, "\n") != expect {
+		t.Error("result error:\n" + strings.Join(got, "\n"))
+	}
+}
+
+func TestCheckPruned(t *testing.T) {
+	// go.sum of a go 1.17 module tidied with -compat=1.16: go.mod hashes of
+	// the whole module graph, and hashes of versions the build doesn't use
+	f, err := os.Open("testdata/pruned/go.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	sum, err := ParseSum(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := os.Open("testdata/pruned/go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	requires, err := ParseRequires(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/app", Name: "main", Imports: []string{"example.com/a"},
+		Module: &depgraph.Module{Path: "example.com/app", Main: true}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/a", Name: "a", Imports: []string{"example.com/b"},
+		Module: &depgraph.Module{Path: "example.com/a", Version: "v1.2.0"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/b", Name: "b",
+		Module: &depgraph.Module{Path: "example.com/b", Version: "v1.0.0"}})
+	var got []string
+	for _, issue := range Check(dg, sum, requires) {
+		got = append(got, issue.String())
+	}
+	expect := "example.com/stale@v0.1.0: in go.sum but not used by the build"
+	if strings.Join(got, "\n") != expect {
+		t.Error("result error:\n" + strings.Join(got, "\n"))
+	}
+}
+
+func TestParseReplaces(t *testing.T) {
+	replaces, err := ParseReplaces(strings.NewReader(goMod + `
+replace example.com/a => github.com/fork/a v1.0.1
+
+replace (
+	example.com/b v1.2.0 => ../b
+	"example.com/c" => "github.com/fork/c" v0.3.0 // pinned
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"example.com/a": "github.com/fork/a",
+		"example.com/b": "../b",
+		"example.com/c": "github.com/fork/c",
+	}
+	if len(replaces) != len(expect) {
+		t.Error(replaces)
+	}
+	for k, v := range expect {
+		if replaces[k] != v {
+			t.Error(k, replaces[k])
+		}
+	}
+}
+
+func TestParseTools(t *testing.T) {
+	tools, err := ParseTools(strings.NewReader(goMod + `
+tool golang.org/x/tools/cmd/stringer
+
+tool (
+	github.com/golangci/golangci-lint/cmd/golangci-lint
+	"example.com/gen" // code generator
+)
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"golang.org/x/tools/cmd/stringer", "github.com/golangci/golangci-lint/cmd/golangci-lint", "example.com/gen"}
+	if !reflect.DeepEqual(tools, expect) {
+		t.Error(tools)
+	}
+}
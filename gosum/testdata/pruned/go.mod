@@ -0,0 +1,7 @@
+module example.com/app
+
+go 1.17
+
+require example.com/a v1.2.0
+
+require example.com/b v1.0.0 // indirect
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// growthPoint is what -growth tells about one snapshot.
+type growthPoint struct {
+	Snapshot string
+	Time     time.Time
+	Packages int
+	Modules  int            // third-party modules
+	Binaries map[string]int // main package -> number of deps
+}
+
+// growthPoints loads every snapshot of the -store, oldest first. Only the
+// main packages in args are kept, if any.
+func growthPoints() (points []growthPoint) {
+	store := openStore()
+	snapshots, err := store.List()
+	if err != nil {
+		fatalln("list snapshots failed", err)
+	}
+	for _, s := range snapshots {
+		r, meta, err := openSnapshot(s.Name)
+		if err != nil {
+			fatalln(err)
+		}
+		dg, err := readGraph(r, meta.Format, meta.Build)
+		r.Close()
+		if err != nil {
+			fail(exitParse, "load snapshot %v failed %v", s.Name, err)
+		}
+		p := growthPoint{Snapshot: s.Name, Time: s.Time, Packages: len(dg.Packages()), Binaries: make(map[string]int)}
+		for _, m := range dg.Modules() {
+			if !m.Main {
+				p.Modules++
+			}
+		}
+		for _, m := range mainsOrAll(dg, flag.Args()) {
+			if dg.IsMainPackage(m) {
+				p.Binaries[m] = len(dg.Deps(m))
+			}
+		}
+		points = append(points, p)
+	}
+	return
+}
+
+// reportGrowth prints the package, third-party module and per main
+// package dep counts of every snapshot, as text bars, CSV or JSON
+// depending on *growth.
+func reportGrowth() {
+	points := growthPoints()
+	var binaries []string
+	for _, p := range points {
+		for b := range p.Binaries {
+			binaries = append(binaries, b)
+		}
+	}
+	binaries = uniqueSorted(binaries)
+	switch *growth {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(points); err != nil {
+			fatalln("encode growth failed", err)
+		}
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.Write(append([]string{"snapshot", "time", "packages", "modules"}, binaries...))
+		for _, p := range points {
+			row := []string{p.Snapshot, p.Time.Format(time.RFC3339), strconv.Itoa(p.Packages), strconv.Itoa(p.Modules)}
+			for _, b := range binaries {
+				n, ok := p.Binaries[b]
+				if !ok {
+					row = append(row, "")
+					continue
+				}
+				row = append(row, strconv.Itoa(n))
+			}
+			w.Write(row)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			fatalln("write growth failed", err)
+		}
+	case "text":
+		series := func(title string, value func(p growthPoint) (int, bool)) {
+			fmt.Println(title + ":")
+			widest, prev := 1, -1
+			for _, p := range points {
+				if n, ok := value(p); ok && n > widest {
+					widest = n
+				}
+			}
+			for _, p := range points {
+				n, ok := value(p)
+				if !ok {
+					continue
+				}
+				change := ""
+				if prev >= 0 && n != prev {
+					change = fmt.Sprintf(" %+d", n-prev)
+				}
+				prev = n
+				fmt.Printf("\t%-12s %s | %5d %s%s\n", p.Snapshot, p.Time.Format("2006-01-02"), n,
+					strings.Repeat("#", (n*50+widest-1)/widest), change)
+			}
+		}
+		series("packages", func(p growthPoint) (int, bool) { return p.Packages, true })
+		series("third-party modules", func(p growthPoint) (int, bool) { return p.Modules, true })
+		for _, b := range binaries {
+			series(b+" deps", func(p growthPoint) (int, bool) {
+				n, ok := p.Binaries[b]
+				return n, ok
+			})
+		}
+	default:
+		fail(exitUsage, "unknown growth format %v, supported: text,csv,json", *growth)
+	}
+}
+
+func uniqueSorted(s []string) []string {
+	sort.Strings(s)
+	unique := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
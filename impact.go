@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportImpact prints the main packages, then the test binaries, to
+// rebuild and retest when the packages or files given as args change.
+func reportImpact(dg *depgraph.DepGraph, args []string) {
+	var changed []string
+	for _, arg := range args {
+		if dg.Exists(arg) {
+			changed = append(changed, arg)
+		} else if p, ok := dg.PackageAt(arg); ok {
+			changed = append(changed, p)
+		} else {
+			log.Printf("%v is neither a package nor in the directory of one", arg)
+		}
+	}
+	mains, tests := dg.Impacted(changed)
+	for _, p := range append(mains, tests...) {
+		printPackage(dg, p, nil, p+ownerNote(p))
+	}
+}
+
+// reportUntested prints the packages no test binary exercises.
+func reportUntested(dg *depgraph.DepGraph) {
+	if dg.CountTest() == 0 {
+		fail(exitNotFound, "no test binaries in the input, generate it with go list -test")
+	}
+	for _, p := range dg.Untested() {
+		if showPackage(dg, p) {
+			printPackage(dg, p, nil, "untested by any suite: "+p+ownerNote(p))
+		}
+	}
+}
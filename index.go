@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// writeIndex saves dg to *writeIndexFile, to be queried with -index.
+func writeIndex(dg *depgraph.DepGraph) {
+	f, err := os.Create(*writeIndexFile)
+	if err != nil {
+		fatalln("create index failed", err)
+	}
+	if err := dg.WriteIndex(f); err != nil {
+		f.Close()
+		fatalln("write index failed", err)
+	}
+	if err := f.Close(); err != nil {
+		fatalln("write index failed", err)
+	}
+	log.Printf("index written to %v", *writeIndexFile)
+}
+
+// searchIndexFile answers the plain and -main searches of the args from the
+// index file *indexFile, printed like searches of the input. The file is memory mapped and queried in place,
+// so memory stays bounded however large the graph is.
+func searchIndexFile() {
+	ix, err := depgraph.OpenIndex(*indexFile)
+	if err == depgraph.ErrBadIndex {
+		fail(exitParse, "%v is not a valid index, write it again with -writeindex", *indexFile)
+	}
+	if err != nil {
+		fail(exitParse, "open index failed %v", err)
+	}
+	defer ix.Close()
+	if b := ix.Build().String(); b != "" {
+		log.Printf("build profile: %s", b)
+	}
+	for _, dep := range flag.Args() {
+		if !ix.Has(dep) {
+			warn(exitNotFound, "%v not found", dep)
+			continue
+		}
+		if *onlyMain {
+			for _, p := range ix.SearchMain(dep) {
+				fmt.Println(mainLine(p, dep))
+			}
+			continue
+		}
+		fmt.Println(selfLine(dep))
+		for _, p := range ix.SearchAll(dep) {
+			fmt.Println(dependentLine(p, dep, ix.IsMainPackage(p), ix.IsTestPackage(p)))
+		}
+	}
+}
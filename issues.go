@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ma6174/go_dep_search/issues"
+	"github.com/ma6174/go_dep_search/rules"
+	"github.com/ma6174/go_dep_search/vuln"
+)
+
+// writeIssues writes items of kind to stdout as issues per owning team,
+// in the -issues format.
+func writeIssues(kind string, items []issues.Item) {
+	if err := issues.Write(os.Stdout, *issueFormat, issues.Build(kind, items)); err != nil {
+		fatalln("write issues failed", err)
+	}
+}
+
+// violationItem is v to track in the issue of the owners of the package
+// breaking the rule.
+func violationItem(v rules.Violation) issues.Item {
+	return issues.Item{
+		Title:  v.Rule.Name + ": " + v.Package + " imports " + v.Dep,
+		Chains: [][]string{v.Chain},
+		Owners: codeOwners.Of(v.Package),
+	}
+}
+
+// findingItems splits f between the owners of the main packages exposed,
+// one item each with their chains.
+func findingItems(f *vuln.Finding) (items []issues.Item) {
+	title := f.Entry.ID + " " + f.Package
+	detail := f.String()
+	if len(f.Chains) == 0 {
+		return []issues.Item{{Title: title, Detail: detail + ", not used by any main package"}}
+	}
+	index := make(map[string]int)
+	for _, chain := range f.Chains {
+		owners := codeOwners.Of(chain[1])
+		key := ownerNote(chain[1])
+		i, ok := index[key]
+		if !ok {
+			i = len(items)
+			index[key] = i
+			items = append(items, issues.Item{Title: title, Detail: detail, Owners: owners})
+		}
+		items[i].Chains = append(items[i].Chains, chain)
+	}
+	return
+}
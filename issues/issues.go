@@ -0,0 +1,153 @@
+// Package issues renders findings, eg: rule violations or vulnerable
+// dependencies, as issues to open per owning team, in the payload format
+// of the GitHub and GitLab issue APIs or as markdown.
+package issues
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Item is one finding to track.
+type Item struct {
+	Title  string     // one line, eg: "GO-2021-0113 golang.org/x/text/language"
+	Detail string     // markdown paragraph, may be empty
+	Chains [][]string // import chains exposing the finding
+	Owners []string   // owners of the finding, the issue it goes to
+}
+
+// Issue collects the items of one kind owned by the same owners.
+type Issue struct {
+	Owners []string
+	Title  string
+	Body   string // markdown
+	Labels []string
+}
+
+// Build groups items by owners into one issue each, sorted by owners,
+// items without owners last. kind names the findings in titles, eg:
+// "dependency rule violation".
+func Build(kind string, items []Item) []Issue {
+	byOwners := make(map[string][]Item)
+	for _, it := range items {
+		key := strings.Join(it.Owners, " ")
+		byOwners[key] = append(byOwners[key], it)
+	}
+	keys := make([]string, 0, len(byOwners))
+	for k := range byOwners {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "") != (keys[j] == "") {
+			return keys[j] == ""
+		}
+		return keys[i] < keys[j]
+	})
+	issues := make([]Issue, 0, len(keys))
+	for _, k := range keys {
+		items := byOwners[k]
+		owners := items[0].Owners
+		title := fmt.Sprintf("%d %s", len(items), kind)
+		if len(items) > 1 {
+			title += "s"
+		}
+		labels := []string{"dependencies"}
+		if len(owners) > 0 {
+			title += " for " + k
+			for _, o := range owners {
+				labels = append(labels, "owner:"+o)
+			}
+		}
+		issues = append(issues, Issue{Owners: owners, Title: title, Body: body(kind, owners, items), Labels: labels})
+	}
+	return issues
+}
+
+func body(kind string, owners []string, items []Item) string {
+	var b strings.Builder
+	if len(owners) > 0 {
+		fmt.Fprintf(&b, "%s: please fix the following %ss.\n", strings.Join(owners, " "), kind)
+	} else {
+		fmt.Fprintf(&b, "Nobody owns the following %ss.\n", kind)
+	}
+	for _, it := range items {
+		fmt.Fprintf(&b, "\n### %s\n", it.Title)
+		if it.Detail != "" {
+			fmt.Fprintf(&b, "\n%s\n", it.Detail)
+		}
+		if len(it.Chains) > 0 {
+			b.WriteString("\n```\n")
+			for _, chain := range it.Chains {
+				b.WriteString(strings.Join(chain, " -> ") + "\n")
+			}
+			b.WriteString("```\n")
+		}
+	}
+	b.WriteString("\nReported by go_dep_search.\n")
+	return b.String()
+}
+
+// WriteGitHub writes one POST /repos/{owner}/{repo}/issues payload per
+// line.
+func WriteGitHub(w io.Writer, issues []Issue) error {
+	enc := json.NewEncoder(w)
+	for _, is := range issues {
+		err := enc.Encode(struct {
+			Title  string   `json:"title"`
+			Body   string   `json:"body"`
+			Labels []string `json:"labels"`
+		}{is.Title, is.Body, is.Labels})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteGitLab writes one POST /projects/:id/issues payload per line.
+func WriteGitLab(w io.Writer, issues []Issue) error {
+	enc := json.NewEncoder(w)
+	for _, is := range issues {
+		err := enc.Encode(struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Labels      string `json:"labels"`
+		}{is.Title, is.Body, strings.Join(is.Labels, ",")})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown writes the issues as one markdown document, a section per
+// issue.
+func WriteMarkdown(w io.Writer, issues []Issue) error {
+	for i, is := range issues {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "## %s\n\n%s", is.Title, is.Body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write writes the issues in format: github, gitlab or markdown.
+func Write(w io.Writer, format string, issues []Issue) error {
+	switch format {
+	case "github":
+		return WriteGitHub(w, issues)
+	case "gitlab":
+		return WriteGitLab(w, issues)
+	case "markdown":
+		return WriteMarkdown(w, issues)
+	}
+	return fmt.Errorf("unknown issue format %q, supported: github,gitlab,markdown", format)
+}
@@ -0,0 +1,55 @@
+package issues
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	items := []Item{
+		{Title: "no-legacy: a imports legacy", Chains: [][]string{{"a", "legacy"}}, Owners: []string{"@org/backend"}},
+		{Title: "no-legacy: b imports legacy", Chains: [][]string{{"b", "c", "legacy"}}},
+		{Title: "no-legacy: d imports legacy", Detail: "since v2", Chains: [][]string{{"d", "legacy"}}, Owners: []string{"@org/backend"}},
+	}
+	issues := Build("dependency rule violation", items)
+	if len(issues) != 2 {
+		t.Fatal(issues)
+	}
+	if issues[0].Title != "2 dependency rule violations for @org/backend" || issues[1].Title != "1 dependency rule violation" ||
+		strings.Join(issues[0].Labels, ",") != "dependencies,owner:@org/backend" {
+		t.Error(issues[0].Title, issues[1].Title, issues[0].Labels)
+	}
+	for _, s := range []string{"@org/backend: please fix", "### no-legacy: d imports legacy\n\nsince v2\n", "```\na -> legacy\n```"} {
+		if !strings.Contains(issues[0].Body, s) {
+			t.Errorf("body misses %q: %s", s, issues[0].Body)
+		}
+	}
+	if !strings.Contains(issues[1].Body, "b -> c -> legacy") {
+		t.Error(issues[1].Body)
+	}
+
+	var b bytes.Buffer
+	if err := Write(&b, "gitlab", issues); err != nil {
+		t.Fatal(err)
+	}
+	var payload map[string]interface{}
+	if err := json.NewDecoder(&b).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["labels"] != "dependencies,owner:@org/backend" || payload["description"] != issues[0].Body {
+		t.Error(payload)
+	}
+	b.Reset()
+	if err := Write(&b, "github", issues); err != nil || strings.Count(b.String(), "\n") != 2 || !strings.Contains(b.String(), `"labels":["dependencies"]`) {
+		t.Error(b.String(), err)
+	}
+	b.Reset()
+	if err := Write(&b, "markdown", issues); err != nil || !strings.HasPrefix(b.String(), "## 2 dependency rule violations") {
+		t.Error(b.String(), err)
+	}
+	if err := Write(&b, "jira", issues); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
@@ -0,0 +1,135 @@
+// Package labels attaches labels, eg: service=billing tier=production, to
+// main packages from a mapping file, so searches can be filtered or
+// grouped by deployment target.
+package labels
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+// Entry gives Labels to the packages matching Pattern.
+type Entry struct {
+	Pattern string // import path pattern, see rules.Match
+	Labels  map[string]string
+}
+
+// Labels is an ordered list of entries. The labels of a package are those
+// of every entry matching it, later entries overriding the values of
+// earlier ones.
+type Labels []Entry
+
+// Load reads one "pattern key=value..." entry per line. Blank lines and
+// lines starting with # are ignored, eg:
+//
+//	example.com/app/cmd/...      tier=staging
+//	example.com/app/cmd/billing  service=billing tier=production
+func Load(r io.Reader) (Labels, error) {
+	var l Labels
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		e := Entry{Pattern: fields[0], Labels: make(map[string]string)}
+		if strings.Contains(e.Pattern, "=") {
+			return nil, fmt.Errorf("line %d: missing pattern before %v", line, e.Pattern)
+		}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				return nil, fmt.Errorf("line %d: %q is not key=value", line, f)
+			}
+			e.Labels[kv[0]] = kv[1]
+		}
+		l = append(l, e)
+	}
+	return l, s.Err()
+}
+
+// LoadFile reads the labels file name, see Load.
+func LoadFile(name string) (Labels, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Of returns the labels of importPath, empty if it has none.
+func (l Labels) Of(importPath string) map[string]string {
+	labels := make(map[string]string)
+	for _, e := range l {
+		if rules.Match(e.Pattern, importPath) {
+			for k, v := range e.Labels {
+				labels[k] = v
+			}
+		}
+	}
+	return labels
+}
+
+// String returns labels as sorted "key=value" pairs separated by spaces.
+func String(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
+
+// Selector selects labeled packages: every key must have one of its
+// values, or any value if none is listed.
+type Selector map[string][]string
+
+// ParseSelector parses comma separated key=value or key terms, eg:
+// "tier=production,service". Terms of the same key are alternatives:
+// "tier=production,tier=staging" selects both tiers.
+func ParseSelector(s string) (Selector, error) {
+	sel := make(Selector)
+	for _, term := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(term), "=", 2)
+		if kv[0] == "" {
+			return nil, fmt.Errorf("labels: bad selector term %q", term)
+		}
+		if len(kv) == 1 {
+			if _, ok := sel[kv[0]]; !ok {
+				sel[kv[0]] = nil
+			}
+			continue
+		}
+		sel[kv[0]] = append(sel[kv[0]], kv[1])
+	}
+	return sel, nil
+}
+
+// Matches reports whether labels are selected by s.
+func (s Selector) Matches(labels map[string]string) bool {
+	for k, values := range s {
+		v, ok := labels[k]
+		if !ok {
+			return false
+		}
+		if len(values) == 0 {
+			continue
+		}
+		found := false
+		for _, want := range values {
+			found = found || v == want
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
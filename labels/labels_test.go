@@ -0,0 +1,54 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabels(t *testing.T) {
+	l, err := Load(strings.NewReader(`# deploy targets
+example.com/app/cmd/...      tier=staging team=backend
+example.com/app/cmd/billing  service=billing tier=production
+
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, expect := range map[string]string{
+		"example.com/app/cmd/api":     "team=backend tier=staging",
+		"example.com/app/cmd/billing": "service=billing team=backend tier=production",
+		"github.com/pkg/errors":       "",
+	} {
+		if got := String(l.Of(p)); got != expect {
+			t.Error(p, got)
+		}
+	}
+	for _, bad := range []string{"tier=production example.com/...\n", "example.com/... production\n"} {
+		if _, err := Load(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q should fail", bad)
+		}
+	}
+}
+
+func TestSelector(t *testing.T) {
+	labels := map[string]string{"tier": "production", "service": "billing"}
+	for s, expect := range map[string]bool{
+		"tier=production":                 true,
+		"tier=staging":                    false,
+		"tier=staging,tier=production":    true,
+		"service":                         true,
+		"tier=production,team":            false,
+		"tier=production,service=billing": true,
+	} {
+		sel, err := ParseSelector(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sel.Matches(labels) != expect {
+			t.Error(s, !expect)
+		}
+	}
+	if _, err := ParseSelector("=x"); err == nil {
+		t.Error("expect error for a term without key")
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/license"
+)
+
+func reportLicenses(dg *depgraph.DepGraph) {
+	f, err := os.Open(*licenseFile)
+	if err != nil {
+		fail(exitParse, "open license file failed %v", err)
+	}
+	defer f.Close()
+	licenses, err := license.LoadCSV(f)
+	if err != nil {
+		fail(exitParse, "load license file failed %v", err)
+	}
+	findings := license.Report(dg, licenses)
+	if len(findings) == 0 {
+		log.Println("no copyleft packages used by main packages")
+		return
+	}
+	for _, f := range findings {
+		fmt.Printf("%s [%s]\n", formatChain(dg, f.Chain), f.License)
+	}
+}
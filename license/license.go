@@ -0,0 +1,101 @@
+// Package license finds the main packages that transitively include
+// copyleft-licensed packages, and the chain bringing each one in.
+package license
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Map maps import paths (packages or module roots) to license names.
+type Map map[string]string
+
+// LoadCSV reads the output of `go-licenses csv`: one
+// "import path,license url,license name" record per line.
+func LoadCSV(r io.Reader) (Map, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	m := make(Map)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) < 2 {
+			continue
+		}
+		m[record[0]] = record[len(record)-1]
+	}
+}
+
+// Lookup returns the license of packageName, taken from its own entry or
+// from the nearest enclosing path that has one.
+func (m Map) Lookup(packageName string) (string, bool) {
+	for p := packageName; ; {
+		if l, ok := m[p]; ok {
+			return l, true
+		}
+		i := strings.LastIndex(p, "/")
+		if i < 0 {
+			return "", false
+		}
+		p = p[:i]
+	}
+}
+
+var copyleft = []string{
+	"AGPL", "GPL", "LGPL", "MPL", "EPL", "EUPL", "CDDL", "OSL", "CC-BY-SA", "SSPL",
+}
+
+// IsCopyleft reports whether license is one of the well-known copyleft
+// licenses, matched by SPDX identifier prefix, eg: GPL-3.0, LGPL-2.1.
+func IsCopyleft(license string) bool {
+	l := strings.ToUpper(strings.TrimSpace(license))
+	for _, c := range copyleft {
+		if strings.HasPrefix(l, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Finding is a copyleft package included by a main package.
+type Finding struct {
+	Main    string
+	Package string
+	License string
+	Chain   []string // main -> Main -> ... -> Package
+}
+
+// Report returns a finding for every (main, copyleft package) pair in g,
+// sorted by main and package.
+func Report(g *depgraph.DepGraph, licenses Map) (findings []Finding) {
+	for _, p := range g.Packages() {
+		l, ok := licenses.Lookup(p)
+		if !ok || !IsCopyleft(l) {
+			continue
+		}
+		for _, chain := range g.SearchChain(p) {
+			findings = append(findings, Finding{
+				Main:    chain[1],
+				Package: p,
+				License: l,
+				Chain:   chain,
+			})
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Main != findings[j].Main {
+			return findings[i].Main < findings[j].Main
+		}
+		return findings[i].Package < findings[j].Package
+	})
+	return
+}
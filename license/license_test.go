@@ -0,0 +1,47 @@
+package license
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+const csvOutput = `example.com/cmd/a,https://example.com/LICENSE,MIT
+example.com/gpl,https://example.com/gpl/LICENSE,GPL-3.0
+example.com/mpl/sub,https://example.com/mpl/LICENSE,MPL-2.0
+`
+
+func TestReport(t *testing.T) {
+	m, err := LoadCSV(strings.NewReader(csvOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, ok := m.Lookup("example.com/gpl/inner"); !ok || l != "GPL-3.0" {
+		t.Error("lookup error", l)
+	}
+	if _, ok := m.Lookup("example.com/mpl"); ok {
+		t.Error("example.com/mpl has no license")
+	}
+	if !IsCopyleft("LGPL-2.1-only") || IsCopyleft("MIT") || IsCopyleft("Apache-2.0") {
+		t.Error("IsCopyleft error")
+	}
+
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/cmd/a", Name: "main",
+		Imports: []string{"example.com/lib"},
+		Deps:    []string{"example.com/lib", "example.com/gpl/inner"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/lib", Name: "lib",
+		Imports: []string{"example.com/gpl/inner"},
+		Deps:    []string{"example.com/gpl/inner"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/gpl/inner", Name: "inner"})
+	findings := Report(dg, m)
+	if len(findings) != 1 {
+		t.Fatal("result error", findings)
+	}
+	f := findings[0]
+	if f.Main != "example.com/cmd/a" || f.License != "GPL-3.0" ||
+		strings.Join(f.Chain, " -> ") != "main -> example.com/cmd/a -> example.com/lib -> example.com/gpl/inner" {
+		t.Error("result error", f)
+	}
+}
@@ -0,0 +1,76 @@
+// Package loader is the registry of the input formats a dependency graph
+// can be loaded from. The go list -json output is built in; packages
+// reading other build systems, eg: Buck or Pants metadata, register their
+// Loader from an init function and are enabled by importing them.
+package loader
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Loader adds the packages described by an input to a graph, with
+// DepGraph.Add.
+type Loader interface {
+	Load(r io.Reader, g *depgraph.DepGraph) error
+}
+
+// Func adapts a function to Loader.
+type Func func(r io.Reader, g *depgraph.DepGraph) error
+
+// Load calls f.
+func (f Func) Load(r io.Reader, g *depgraph.DepGraph) error {
+	return f(r, g)
+}
+
+var (
+	mu      sync.RWMutex
+	loaders = make(map[string]Loader)
+)
+
+func init() {
+	Register("golist", Func(func(r io.Reader, g *depgraph.DepGraph) error {
+		return g.Load(r)
+	}))
+}
+
+// Register makes l available under name. It panics if name is already
+// taken, like database/sql.Register.
+func Register(name string, l Loader) {
+	mu.Lock()
+	defer mu.Unlock()
+	if l == nil {
+		panic("loader: Register loader is nil")
+	}
+	if _, dup := loaders[name]; dup {
+		panic("loader: Register called twice for " + name)
+	}
+	loaders[name] = l
+}
+
+// Get returns the Loader registered under name.
+func Get(name string) (Loader, error) {
+	mu.RLock()
+	l, ok := loaders[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown input format %q, registered: %v", name, Names())
+	}
+	return l, nil
+}
+
+// Names returns the registered input formats, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(loaders))
+	for name := range loaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
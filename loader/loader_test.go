@@ -0,0 +1,64 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func TestGoList(t *testing.T) {
+	l, err := Get("golist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg := &depgraph.DepGraph{}
+	input := `{"ImportPath": "cmd/a", "Name": "main", "Imports": ["fmt"], "Deps": ["fmt"]}
+{"ImportPath": "fmt", "Standard": true}`
+	if err := l.Load(strings.NewReader(input), dg); err != nil {
+		t.Fatal(err)
+	}
+	if !dg.IsMainPackage("cmd/a") || !dg.PathExists("cmd/a", "fmt") {
+		t.Error("load error")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	// one "target: dep dep..." line per package
+	Register("targets", Func(func(r io.Reader, g *depgraph.DepGraph) error {
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			fields := strings.Fields(strings.Replace(s.Text(), ":", " ", 1))
+			if len(fields) > 0 {
+				g.Add(depgraph.DepInfo{ImportPath: fields[0], Imports: fields[1:]})
+			}
+		}
+		return s.Err()
+	}))
+	if got := strings.Join(Names(), " "); got != "golist targets" {
+		t.Error(got)
+	}
+	l, err := Get("targets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg := &depgraph.DepGraph{}
+	dg.IgnoreDeps(true)
+	if err := l.Load(strings.NewReader("//app: //lib\n//lib: //base\n//base:\n"), dg); err != nil {
+		t.Fatal(err)
+	}
+	if !dg.PathExists("//app", "//base") {
+		t.Error("load error")
+	}
+	if _, err := Get("buck"); err == nil {
+		t.Error("expect error for unknown format")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expect panic registering a format twice")
+		}
+	}()
+	Register("golist", Func(nil))
+}
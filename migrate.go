@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// loadMigrations reads the *migrationsFile: one "old_path new_path" or
+// "old_path => new_path" line per migration. Empty lines and lines
+// starting with # are skipped.
+func loadMigrations() []depgraph.Migration {
+	f, err := os.Open(*migrationsFile)
+	if err != nil {
+		fail(exitParse, "open migrations file failed %v", err)
+	}
+	defer f.Close()
+	var migrations []depgraph.Migration
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(strings.Replace(line, "=>", " ", 1))
+		if len(fields) != 2 {
+			fail(exitParse, "%v:%d: expected old_path new_path, found %q", *migrationsFile, n, line)
+		}
+		migrations = append(migrations, depgraph.Migration{Old: fields[0], New: fields[1]})
+	}
+	if err := scanner.Err(); err != nil {
+		fail(exitParse, "read migrations file failed %v", err)
+	}
+	return migrations
+}
+
+// reportMigrations prints, for every migration of the *migrationsFile, how
+// many main packages are on the old path, the new one or both, then each
+// main package with its side and, while not done, the chain to the old
+// path.
+func reportMigrations(dg *depgraph.DepGraph) {
+	for _, m := range loadMigrations() {
+		var statuses []depgraph.MigrationStatus
+		count := make(map[string]int)
+		for _, s := range dg.MigrationStatuses(m) {
+			if showPackage(dg, s.Main) {
+				statuses = append(statuses, s)
+				count[s.State()]++
+			}
+		}
+		fmt.Printf("%s => %s: %d migrated, %d pending, %d both\n", m.Old, m.New, count["new"], count["old"], count["both"])
+		for _, s := range statuses {
+			fmt.Printf("\t%s%s: %s\n", s.Main, ownerNote(s.Main), s.State())
+			if s.OldChain != nil {
+				fmt.Println("\t\t" + formatChain(dg, s.OldChain))
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/modproxy"
+)
+
+// freshnessLimits are the thresholds of -freshness, 0 for none.
+type freshnessLimits struct {
+	versions, months int
+}
+
+// loadFreshness parses -freshness: a comma separated list of versions=N
+// and months=N.
+func loadFreshness() (l freshnessLimits) {
+	for _, kv := range strings.Split(*freshness, ",") {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			fail(exitUsage, "-freshness: %q is not name=N", kv)
+		}
+		n, err := strconv.Atoi(kv[i+1:])
+		if err != nil || n < 0 {
+			fail(exitUsage, "-freshness: bad value in %q", kv)
+		}
+		switch kv[:i] {
+		case "versions":
+			l.versions = n
+		case "months":
+			l.months = n
+		default:
+			fail(exitUsage, "-freshness: unknown threshold %q, supported: versions,months", kv[:i])
+		}
+	}
+	return
+}
+
+// reportFreshness prints the third-party modules more than the -freshness
+// thresholds behind their latest version on the module proxy, with the
+// main packages using them, and exits 1 if there is any.
+func reportFreshness(dg *depgraph.DepGraph) {
+	limits := loadFreshness()
+	client := modproxy.NewClient()
+	stale := 0
+	for _, m := range dg.Modules() {
+		path, version, ok := proxyModule(m)
+		if !ok {
+			continue
+		}
+		f, err := client.Check(path, version)
+		if err != nil {
+			log.Printf("proxy lookup %v failed: %v", path, err)
+			continue
+		}
+		months := f.MonthsBehind()
+		if !(limits.versions > 0 && f.Behind > limits.versions || limits.months > 0 && months > limits.months) {
+			continue
+		}
+		stale++
+		names := moduleMains(dg, m.Path)
+		fmt.Printf("%s@%s latest=%s behind=%d months=%d mains=%d\n", path, version, f.Latest, f.Behind, months, len(names))
+		for _, main := range names {
+			fmt.Println("\tmain -> " + main)
+		}
+	}
+	if stale > 0 {
+		fail(exitViolation, "%d modules behind their latest version", stale)
+	}
+}
+
+// reportRetracted prints the third-party modules whose latest go.mod on
+// the module proxy retracts the version in use or deprecates the module,
+// with the chain from each main package needing them, and exits 1 if
+// there is any.
+func reportRetracted(dg *depgraph.DepGraph) {
+	client := modproxy.NewClient()
+	flagged := 0
+	for _, m := range dg.Modules() {
+		path, version, ok := proxyModule(m)
+		if !ok {
+			continue
+		}
+		s, err := client.Status(path, version)
+		if err != nil {
+			log.Printf("proxy lookup %v failed: %v", path, err)
+			continue
+		}
+		var notes []string
+		if s.Retracted != nil {
+			notes = append(notes, fmt.Sprintf("RETRACTED %s@%s: %s", path, version, orNone(s.Retracted.Rationale)))
+		}
+		if s.Deprecated != "" {
+			notes = append(notes, fmt.Sprintf("DEPRECATED %s: %s", path, s.Deprecated))
+		}
+		if len(notes) == 0 {
+			continue
+		}
+		flagged++
+		for _, note := range notes {
+			fmt.Println(note)
+		}
+		for _, chain := range dg.WhyModule(m.Path) {
+			fmt.Println("\t" + formatChain(dg, chain))
+		}
+	}
+	if flagged > 0 {
+		fail(exitViolation, "%d modules retracted or deprecated", flagged)
+	}
+}
+
+// orNone returns s, or "no rationale given" if it's empty.
+func orNone(s string) string {
+	if s == "" {
+		return "no rationale given"
+	}
+	return s
+}
+
+// proxyModule returns the module version of the proxy m is built from,
+// its replacement if any, and false for the main module and local
+// replacements.
+func proxyModule(m depgraph.Module) (path, version string, ok bool) {
+	switch {
+	case m.Main:
+		return "", "", false
+	case m.Replace == nil:
+		return m.Path, m.Version, true
+	case m.Replace.Version == "": // local directory
+		return "", "", false
+	}
+	return m.Replace.Path, m.Replace.Version, true
+}
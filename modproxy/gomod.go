@@ -0,0 +1,127 @@
+package modproxy
+
+import (
+	"strings"
+
+	"github.com/ma6174/go_dep_search/semver"
+)
+
+// Retraction is a retract directive of a go.mod file: the versions from
+// Low to High, both included, should not be used.
+type Retraction struct {
+	Low, High string
+	Rationale string // comment of the directive
+}
+
+// Covers reports whether version is retracted by r.
+func (r Retraction) Covers(version string) bool {
+	return semver.Compare(version, r.Low) >= 0 && semver.Compare(version, r.High) <= 0
+}
+
+// comment returns the text of the // comment of line, and line without it.
+func comment(line string) (code, text string) {
+	i := strings.Index(line, "//")
+	if i < 0 {
+		return line, ""
+	}
+	return line[:i], strings.TrimSpace(line[i+2:])
+}
+
+// Deprecation returns the deprecation notice of the go.mod file gomod:
+// the paragraph starting with "Deprecated:" of the comments above or
+// after its module directive, without the prefix. It is empty for
+// modules not deprecated.
+func Deprecation(gomod []byte) string {
+	var comments []string
+	for _, line := range strings.Split(string(gomod), "\n") {
+		code, text := comment(line)
+		fields := strings.Fields(code)
+		switch {
+		case len(fields) == 0 && strings.HasPrefix(strings.TrimSpace(line), "//"):
+			comments = append(comments, text)
+			continue
+		case len(fields) > 0 && fields[0] == "module":
+			if text != "" {
+				comments = append(comments, text)
+			}
+			return deprecatedParagraph(comments)
+		}
+		comments = nil
+	}
+	return ""
+}
+
+func deprecatedParagraph(comments []string) string {
+	var notice []string
+	for _, c := range comments {
+		switch {
+		case notice == nil && strings.HasPrefix(c, "Deprecated:"):
+			notice = append(notice, strings.TrimSpace(strings.TrimPrefix(c, "Deprecated:")))
+		case notice != nil && c == "":
+			return strings.Join(notice, " ")
+		case notice != nil:
+			notice = append(notice, c)
+		}
+	}
+	return strings.Join(notice, " ")
+}
+
+// Retractions returns the retract directives of the go.mod file gomod. The
+// rationale is the comment after a directive, or the comments above it.
+func Retractions(gomod []byte) (retractions []Retraction) {
+	var above []string
+	inBlock := false
+	for _, line := range strings.Split(string(gomod), "\n") {
+		code, text := comment(line)
+		fields := strings.Fields(code)
+		switch {
+		case len(fields) == 0:
+			if text != "" {
+				above = append(above, text)
+			} else {
+				above = nil
+			}
+			continue
+		case inBlock && fields[0] == ")":
+			inBlock = false
+			above = nil
+			continue
+		case !inBlock && fields[0] == "retract":
+			if len(fields) == 2 && fields[1] == "(" {
+				inBlock = true
+				above = nil
+				continue
+			}
+			fields = fields[1:]
+		case !inBlock:
+			above = nil
+			continue
+		}
+		r, ok := parseRetract(strings.Join(fields, " "))
+		if ok {
+			r.Rationale = text
+			if text == "" {
+				r.Rationale = strings.Join(above, " ")
+			}
+			retractions = append(retractions, r)
+		}
+		above = nil
+	}
+	return
+}
+
+// parseRetract parses the version or [low, high] interval of a retract
+// directive.
+func parseRetract(s string) (Retraction, bool) {
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		bounds := strings.Split(s[1:len(s)-1], ",")
+		if len(bounds) != 2 {
+			return Retraction{}, false
+		}
+		return Retraction{Low: strings.TrimSpace(bounds[0]), High: strings.TrimSpace(bounds[1])}, true
+	}
+	if s == "" || strings.ContainsAny(s, " [],") {
+		return Retraction{}, false
+	}
+	return Retraction{Low: s, High: s}, true
+}
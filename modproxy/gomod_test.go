@@ -0,0 +1,77 @@
+package modproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+const retractingGoMod = `// Deprecated: use example.com/retry/v2,
+// which supports contexts.
+//
+// Package retry retries.
+module example.com/retry
+
+go 1.16
+
+retract v1.0.1 // panics on zero backoff
+
+retract (
+	// published by mistake
+	[v1.2.0, v1.2.3]
+	v1.3.0
+)
+`
+
+func TestGoMod(t *testing.T) {
+	if got := Deprecation([]byte(retractingGoMod)); got != "use example.com/retry/v2, which supports contexts." {
+		t.Error(got)
+	}
+	if got := Deprecation([]byte("// Package retry retries.\n\nmodule example.com/retry // Deprecated: frozen\n")); got != "frozen" {
+		t.Error(got)
+	}
+	if got := Deprecation([]byte("// Deprecated: not about the module\n\nmodule example.com/retry\n")); got != "" {
+		t.Error(got)
+	}
+	expect := []Retraction{
+		{Low: "v1.0.1", High: "v1.0.1", Rationale: "panics on zero backoff"},
+		{Low: "v1.2.0", High: "v1.2.3", Rationale: "published by mistake"},
+		{Low: "v1.3.0", High: "v1.3.0"},
+	}
+	retractions := Retractions([]byte(retractingGoMod))
+	if !reflect.DeepEqual(retractions, expect) {
+		t.Error(retractions)
+	}
+	if !retractions[1].Covers("v1.2.2") || retractions[1].Covers("v1.2.4") || retractions[1].Covers("v1.1.9") {
+		t.Error("interval check error")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	responses := map[string]string{
+		"/example.com/retry/@latest":       `{"Version": "v1.4.0"}`,
+		"/example.com/retry/@v/v1.4.0.mod": retractingGoMod,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	c := NewClient()
+	c.BaseURL = srv.URL
+	s, err := c.Status("example.com/retry", "v1.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Latest != "v1.4.0" || s.Retracted == nil || s.Retracted.Rationale != "published by mistake" || s.Deprecated == "" {
+		t.Error("result error", s)
+	}
+	if s, err := c.Status("example.com/retry", "v1.1.0"); err != nil || s.Retracted != nil {
+		t.Error("v1.1.0 is not retracted", s, err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package modproxy asks a Go module proxy how far behind the latest
+// release module versions are, see https://go.dev/ref/mod#goproxy-protocol.
+package modproxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ma6174/go_dep_search/semver"
+)
+
+// VersionInfo is the .info of a module version.
+type VersionInfo struct {
+	Version string
+	Time    time.Time
+}
+
+// Freshness tells how far version of module Path is behind Latest.
+type Freshness struct {
+	Path        string
+	Version     string
+	VersionTime time.Time // zero if the proxy doesn't know version, eg: a local replacement
+	Latest      string
+	LatestTime  time.Time
+	Behind      int // releases after Version up to Latest
+}
+
+// MonthsBehind returns the whole months between Version and Latest, 0 if
+// the time of Version is unknown.
+func (f *Freshness) MonthsBehind() int {
+	if f.VersionTime.IsZero() || !f.LatestTime.After(f.VersionTime) {
+		return 0
+	}
+	a, b := f.VersionTime.UTC(), f.LatestTime.UTC()
+	months := (b.Year()-a.Year())*12 + int(b.Month()-a.Month())
+	if b.Day() < a.Day() {
+		months--
+	}
+	return months
+}
+
+type Client struct {
+	HTTP    *http.Client
+	BaseURL string
+}
+
+// NewClient returns a client of the first proxy of $GOPROXY, or of
+// proxy.golang.org.
+func NewClient() *Client {
+	base := "https://proxy.golang.org"
+	for _, p := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if p != "direct" && p != "off" {
+			base = strings.TrimSuffix(p, "/")
+			break
+		}
+	}
+	return &Client{
+		HTTP:    &http.Client{Timeout: 30 * time.Second},
+		BaseURL: base,
+	}
+}
+
+// escapePath escapes the upper case letters of a module path the way
+// proxies expect: "!" followed by the lower case letter.
+func escapePath(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (c *Client) get(path, suffix string) ([]byte, error) {
+	resp, err := c.HTTP.Get(c.BaseURL + "/" + escapePath(path) + suffix)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy %s%s: %s", path, suffix, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Versions returns the released versions of module path, oldest first.
+func (c *Client) Versions(path string) ([]string, error) {
+	body, err := c.get(path, "/@v/list")
+	if err != nil {
+		return nil, err
+	}
+	versions := strings.Fields(string(body))
+	sort.Slice(versions, func(i, j int) bool { return semver.Compare(versions[i], versions[j]) < 0 })
+	return versions, nil
+}
+
+// Info returns the .info of version of module path.
+func (c *Client) Info(path, version string) (*VersionInfo, error) {
+	return c.info(path, "/@v/"+escapePath(version)+".info")
+}
+
+// Latest returns the .info of the latest version of module path.
+func (c *Client) Latest(path string) (*VersionInfo, error) {
+	return c.info(path, "/@latest")
+}
+
+func (c *Client) info(path, suffix string) (*VersionInfo, error) {
+	body, err := c.get(path, suffix)
+	if err != nil {
+		return nil, err
+	}
+	var info VersionInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("proxy %s%s: %v", path, suffix, err)
+	}
+	return &info, nil
+}
+
+// Check compares version of module path with its latest version. A
+// version the proxy doesn't know is not an error, its time stays zero.
+func (c *Client) Check(path, version string) (*Freshness, error) {
+	latest, err := c.Latest(path)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := c.Versions(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &Freshness{Path: path, Version: version, Latest: latest.Version, LatestTime: latest.Time}
+	for _, v := range versions {
+		if !semver.IsPrerelease(v) && semver.Compare(v, version) > 0 && semver.Compare(v, latest.Version) <= 0 {
+			f.Behind++
+		}
+	}
+	if info, err := c.Info(path, version); err == nil {
+		f.VersionTime = info.Time
+	}
+	return f, nil
+}
+
+// GoMod returns the go.mod file of version of module path.
+func (c *Client) GoMod(path, version string) ([]byte, error) {
+	return c.get(path, "/@v/"+escapePath(version)+".mod")
+}
+
+// Status is what the go.mod of the latest version of a module says about
+// an older version.
+type Status struct {
+	Path       string
+	Version    string
+	Latest     string
+	Deprecated string      // deprecation notice of the module, see Deprecation
+	Retracted  *Retraction // retraction covering Version, nil if none
+}
+
+// Status reads the deprecation notice and retractions of the latest
+// go.mod of module path, like go list -m -u -retracted does, and checks
+// version against them.
+func (c *Client) Status(path, version string) (*Status, error) {
+	latest, err := c.Latest(path)
+	if err != nil {
+		return nil, err
+	}
+	gomod, err := c.GoMod(path, latest.Version)
+	if err != nil {
+		return nil, err
+	}
+	s := &Status{Path: path, Version: version, Latest: latest.Version, Deprecated: Deprecation(gomod)}
+	for _, r := range Retractions(gomod) {
+		if r.Covers(version) {
+			r := r
+			s.Retracted = &r
+			break
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,42 @@
+package modproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	responses := map[string]string{
+		"/github.com/!burnt!sushi/toml/@latest":        `{"Version": "v1.3.2", "Time": "2023-06-08T06:13:22Z"}`,
+		"/github.com/!burnt!sushi/toml/@v/list":        "v1.3.0\nv0.4.1\nv1.2.1\nv1.3.2\nv1.4.0-rc.1\nv1.1.0\n",
+		"/github.com/!burnt!sushi/toml/@v/v1.1.0.info": `{"Version": "v1.1.0", "Time": "2022-04-01T10:00:00Z"}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	c := NewClient()
+	c.BaseURL = srv.URL
+	f, err := c.Check("github.com/BurntSushi/toml", "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Latest != "v1.3.2" || f.Behind != 3 || !f.VersionTime.Equal(time.Date(2022, 4, 1, 10, 0, 0, 0, time.UTC)) ||
+		f.MonthsBehind() != 14 {
+		t.Error("result error", f, f.MonthsBehind())
+	}
+	f, err = c.Check("github.com/BurntSushi/toml", "v0.0.0-20200101000000-abcdefabcdef")
+	if err != nil || f.Behind != 5 || !f.VersionTime.IsZero() || f.MonthsBehind() != 0 {
+		t.Error("unknown version", f, err)
+	}
+	if _, err := c.Check("example.com/missing", "v1.0.0"); err == nil {
+		t.Error("missing module should fail")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// loadMulti loads the name=file graphs of -multi.
+func loadMulti() *depgraph.MultiGraph {
+	m := depgraph.NewMultiGraph()
+	for _, spec := range strings.Split(*multiGraphs, ",") {
+		kv := strings.SplitN(spec, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			fail(exitUsage, "bad -multi entry %q, expected name=file", spec)
+		}
+		f, err := os.Open(kv[1])
+		if err != nil {
+			fail(exitParse, "open deps file failed %v", err)
+		}
+		dg, err := readGraph(f, *inputFormat, buildProfile())
+		f.Close()
+		if err != nil {
+			fail(exitParse, "LoadDeps %v failed: %v", kv[1], err)
+		}
+		log.Printf("successfully load %d packages of %v (%d main packages)", dg.CountAll(), kv[0], dg.CountMain())
+		m.Add(kv[0], dg)
+	}
+	return m
+}
+
+// searchMulti answers the chain and -main searches of the args in every
+// graph of -multi, each result prefixed by the name of its graph.
+func searchMulti() {
+	m := loadMulti()
+	for _, dep := range flag.Args() {
+		found := false
+		if *onlyMain && !*chain {
+			for _, t := range m.SearchMain(dep) {
+				found = true
+				for _, p := range t.Packages {
+					deps := []string{"main", p + ownerNote(p)}
+					if p != dep {
+						deps = append(deps, dep+ownerNote(dep))
+					}
+					fmt.Printf("%s: %s\n", t.Graph, strings.Join(deps, " -> "))
+				}
+			}
+		} else {
+			for _, t := range m.SearchChain(dep) {
+				found = true
+				dg := m.Graph(t.Graph)
+				for _, chain := range t.Chains {
+					fmt.Printf("%s: %s\n", t.Graph, formatChain(dg, chain))
+				}
+			}
+		}
+		if !found {
+			warn(exitNotFound, "%v not found", dep)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Package owners maps import paths to the teams owning them, from a
+// CODEOWNERS-style or YAML file, so search results tell who to contact.
+package owners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+// Entry gives the packages matching Pattern to Owners.
+type Entry struct {
+	Pattern string // import path pattern, see rules.Match
+	Owners  []string
+}
+
+// Owners is an ordered list of entries; like in CODEOWNERS, the last
+// matching entry wins.
+type Owners []Entry
+
+// Load reads one "pattern owner..." entry per line. Blank lines and lines
+// starting with # are ignored; an entry without owners unsets the owners
+// of the packages it matches, eg:
+//
+//	example.com/app/...          @org/backend
+//	example.com/app/cmd/billing  @org/payments alice@example.com
+func Load(r io.Reader) (Owners, error) {
+	var o Owners
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if strings.HasPrefix(fields[0], "@") {
+			return nil, fmt.Errorf("line %d: missing pattern before %v", line, fields[0])
+		}
+		o = append(o, Entry{Pattern: fields[0], Owners: fields[1:]})
+	}
+	return o, s.Err()
+}
+
+// Of returns the owners of importPath, nil if nobody owns it.
+func (o Owners) Of(importPath string) []string {
+	for i := len(o) - 1; i >= 0; i-- {
+		if rules.Match(o[i].Pattern, importPath) {
+			return o[i].Owners
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package owners
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOwners(t *testing.T) {
+	o, err := Load(strings.NewReader(`# teams
+example.com/app/...          @org/backend
+example.com/app/cmd/billing  @org/payments alice@example.com
+
+example.com/app/internal/gen
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, expect := range map[string]string{
+		"example.com/app/db":           "@org/backend",
+		"example.com/app/cmd/billing":  "@org/payments alice@example.com",
+		"example.com/app/internal/gen": "",
+		"github.com/pkg/errors":        "",
+	} {
+		if got := strings.Join(o.Of(p), " "); got != expect {
+			t.Error(p, got)
+		}
+	}
+	if _, err := Load(strings.NewReader("@org/backend example.com/...\n")); err == nil {
+		t.Error("entry without pattern should fail")
+	}
+}
+
+func TestLoadYAML(t *testing.T) {
+	o, err := LoadYAML(strings.NewReader(`# teams
+example.com/app/cmd/billing: ["@org/payments", alice@example.com]
+example.com/app: "@org/backend" # everything else
+example.com/app/internal/gen:
+  - '@org/platform'
+  - bob@example.com
+example.com/*/tools/*: "@org/devex"
+example.com/app/legacy:
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, expect := range map[string]string{
+		"example.com/app":                     "@org/backend",
+		"example.com/app/db":                  "@org/backend",
+		"example.com/app/cmd/billing/invoice": "@org/payments alice@example.com",
+		"example.com/app/internal/gen":        "@org/platform bob@example.com",
+		"example.com/app/tools/lint":          "@org/devex",
+		"example.com/app/legacy":              "",
+		"example.com/application":             "",
+	} {
+		if got := strings.Join(o.Of(p), " "); got != expect {
+			t.Error(p, got)
+		}
+	}
+	for _, bad := range []string{"  - @org/backend\n", "example.com/app\n", "a: b\n  c: d\n"} {
+		if _, err := LoadYAML(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q should fail", bad)
+		}
+	}
+}
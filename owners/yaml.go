@@ -0,0 +1,90 @@
+package owners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadYAML reads a YAML mapping of import path prefixes to an owner or a
+// list of owners, eg:
+//
+//	example.com/app: "@org/backend"
+//	example.com/app/cmd/billing: ["@org/payments", alice@example.com]
+//	example.com/app/internal/gen:
+//	  - "@org/platform"
+//
+// A prefix covers the packages below it; the longest matching prefix
+// wins, whatever the order of the file. Keys may also be patterns, see
+// rules.Match, weighing as much as their length. Only this subset of YAML
+// is supported.
+func LoadYAML(r io.Reader) (Owners, error) {
+	var o Owners
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text := s.Text()
+		if i := strings.Index(text, " #"); i >= 0 {
+			text = text[:i]
+		}
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---":
+			continue
+		case strings.HasPrefix(trimmed, "- "):
+			if len(o) == 0 || text == trimmed {
+				return nil, fmt.Errorf("line %d: list item outside of an entry", line)
+			}
+			o[len(o)-1].Owners = append(o[len(o)-1].Owners, unquote(strings.TrimSpace(trimmed[2:])))
+			continue
+		case text != trimmed:
+			return nil, fmt.Errorf("line %d: unexpected indentation", line)
+		}
+		i := strings.Index(text, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("line %d: %q is not prefix: owners", line, text)
+		}
+		e := Entry{Pattern: unquote(strings.TrimSpace(text[:i]))}
+		if !strings.ContainsAny(e.Pattern, "*?[") && !strings.HasSuffix(e.Pattern, "...") {
+			e.Pattern += "/..."
+		}
+		value := strings.TrimSpace(text[i+1:])
+		if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+			for _, v := range strings.Split(value[1:len(value)-1], ",") {
+				if v = unquote(strings.TrimSpace(v)); v != "" {
+					e.Owners = append(e.Owners, v)
+				}
+			}
+		} else if value != "" {
+			e.Owners = []string{unquote(value)}
+		}
+		o = append(o, e)
+	}
+	sort.SliceStable(o, func(i, j int) bool { return len(o[i].Pattern) < len(o[j].Pattern) })
+	return o, s.Err()
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// LoadFile reads the owners file name: YAML for .yaml and .yml files, see
+// LoadYAML, CODEOWNERS-style otherwise, see Load.
+func LoadFile(name string) (Owners, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml":
+		return LoadYAML(f)
+	}
+	return Load(f)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/report"
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+// writeReport writes the audit document of the graph to stdout in the
+// format named by *reportFormat. Its policy violations are those of the
+// internal package check and of *rulesFile, if set.
+func writeReport(dg *depgraph.DepGraph) {
+	violations := rules.CheckInternal(dg)
+	if *rulesFile != "" {
+		violations = append(violations, rules.Evaluate(dg, loadRules())...)
+	}
+	a := report.Collect(dg, violations)
+	var err error
+	switch *reportFormat {
+	case "markdown":
+		err = report.Markdown(os.Stdout, a)
+	case "html":
+		err = report.HTML(os.Stdout, a)
+	default:
+		fail(exitUsage, "unknown report format %v, supported: markdown,html", *reportFormat)
+	}
+	if err != nil {
+		fatalln("report failed", err)
+	}
+}
@@ -0,0 +1,85 @@
+package report
+
+import (
+	"html/template"
+	"io"
+)
+
+// HTML writes a as a single HTML file, without external assets, to share
+// with people who won't run the CLI: every binary has a collapsible import
+// tree, and a search box opens the trees on the matching packages.
+func HTML(w io.Writer, a *Audit) error {
+	return htmlTemplate.Execute(w, a)
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`{{define "node"}}<li data-pkg="{{.Package}}">
+{{- if .Imports}}<details><summary>{{.Package}}</summary><ul>{{range .Imports}}{{template "node" .}}{{end}}</ul></details>
+{{- else}}{{.Package}}{{if .Seen}} <i>(see above)</i>{{end}}{{end}}</li>
+{{end}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Dependency audit - go_dep_search</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+table { border-collapse: collapse; }
+td { border: 1px solid #ccc; padding: 2px 8px; }
+td.n { text-align: right; }
+ul { list-style: none; padding-left: 1.2em; }
+summary { cursor: pointer; }
+li.match, li.match > details > summary { background: #fe8; }
+</style>
+</head>
+<body>
+<h1>Dependency audit</h1>
+<h2>Summary</h2>
+{{if .Build}}<p>Build profile: <code>{{.Build}}</code></p>{{end}}
+<table>
+<tr><td>total</td><td class="n">{{.Packages}}</td></tr>
+<tr><td>main</td><td class="n">{{.Mains}}</td></tr>
+<tr><td>test</td><td class="n">{{.Tests}}</td></tr>
+<tr><td>standard library</td><td class="n">{{.Standard}}</td></tr>
+<tr><td>third-party</td><td class="n">{{.ThirdParty}}</td></tr>
+<tr><td>unused</td><td class="n">{{len .Unused}}</td></tr>
+<tr><td>policy violations</td><td class="n">{{len .Violations}}</td></tr>
+</table>
+<h2>Binaries</h2>
+<input id="search" size="60" placeholder="search packages">
+<ul id="binaries">
+{{range .Binaries}}<li data-pkg="{{.Main}}"><details><summary>{{.Main}} ({{len .Deps}} deps, {{len .ThirdParty}} third-party)</summary>
+{{if .ThirdParty}}<p>Third-party:</p><ul>{{range .ThirdParty}}<li data-pkg="{{.}}">{{.}}</li>{{end}}</ul>{{end}}
+<p>Imports:</p><ul>{{range .Tree.Imports}}{{template "node" .}}{{end}}</ul>
+</details></li>
+{{end}}</ul>
+<h2>Unused packages</h2>
+{{if .Unused}}<ul>{{range .Unused}}<li>{{.}}</li>{{end}}</ul>{{else}}<p>None.</p>{{end}}
+<h2>Policy violations</h2>
+{{if .Violations}}<ul>{{range .Violations}}<li><b>{{.Rule.Name}}</b>: {{range $i, $p := .Chain}}{{if $i}} &rarr; {{end}}{{$p}}{{end}}</li>{{end}}</ul>{{else}}<p>None.</p>{{end}}
+<script>
+document.getElementById("search").oninput = function() {
+	var q = this.value;
+	document.querySelectorAll("#binaries li").forEach(function(li) {
+		li.hidden = q !== "";
+		li.classList.remove("match");
+	});
+	if (q === "") {
+		return;
+	}
+	document.querySelectorAll("#binaries li").forEach(function(li) {
+		if (li.dataset.pkg.indexOf(q) < 0) {
+			return;
+		}
+		li.classList.add("match");
+		for (var e = li; e; e = e.parentElement) {
+			if (e.tagName === "LI") {
+				e.hidden = false;
+			} else if (e.tagName === "DETAILS" && e !== li.firstElementChild) {
+				e.open = true;
+			}
+		}
+	});
+};
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// mdCode quotes s as inline code.
+func mdCode(s string) string {
+	return "`" + strings.Replace(s, "`", "'", -1) + "`"
+}
+
+// Markdown writes a as a Markdown document.
+func Markdown(w io.Writer, a *Audit) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "# Dependency audit\n\n## Summary\n\n")
+	if a.Build != "" {
+		fmt.Fprintf(bw, "Build profile: %s\n\n", mdCode(a.Build))
+	}
+	fmt.Fprintln(bw, "| | packages |\n|---|---:|")
+	for _, row := range []struct {
+		name string
+		n    int
+	}{
+		{"total", a.Packages},
+		{"main", a.Mains},
+		{"test", a.Tests},
+		{"standard library", a.Standard},
+		{"third-party", a.ThirdParty},
+		{"unused", len(a.Unused)},
+		{"policy violations", len(a.Violations)},
+	} {
+		fmt.Fprintf(bw, "| %s | %d |\n", row.name, row.n)
+	}
+
+	fmt.Fprint(bw, "\n## External dependencies\n")
+	for _, b := range a.Binaries {
+		fmt.Fprintf(bw, "\n### %s\n\n%d of %d deps are third-party", mdCode(b.Main), len(b.ThirdParty), len(b.Deps))
+		if len(b.ThirdParty) == 0 {
+			fmt.Fprint(bw, ".\n")
+			continue
+		}
+		fmt.Fprint(bw, ":\n\n")
+		for _, p := range b.ThirdParty {
+			fmt.Fprintf(bw, "- %s\n", mdCode(p))
+		}
+	}
+
+	fmt.Fprint(bw, "\n## Unused packages\n\n")
+	if len(a.Unused) == 0 {
+		fmt.Fprintln(bw, "None.")
+	}
+	for _, p := range a.Unused {
+		fmt.Fprintf(bw, "- %s\n", mdCode(p))
+	}
+
+	fmt.Fprint(bw, "\n## Policy violations\n\n")
+	if len(a.Violations) == 0 {
+		fmt.Fprintln(bw, "None.")
+	}
+	for _, v := range a.Violations {
+		fmt.Fprintf(bw, "- **%s**: %s\n", v.Rule.Name, mdCode(strings.Join(v.Chain, " -> ")))
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,85 @@
+// Package report writes audit documents of a dependency graph, in Markdown
+// or HTML, to attach to release or compliance reviews: summary stats, the
+// third-party deps of every main package, unused packages and policy
+// violations.
+package report
+
+import (
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+// Audit is what a report tells about a graph.
+type Audit struct {
+	Packages   int // loaded packages
+	Mains      int
+	Tests      int
+	Standard   int
+	ThirdParty int
+	Build      string   // build profile of the graph, see depgraph.BuildProfile
+	Binaries   []Binary // sorted by main package
+	Unused     []string
+	Violations []rules.Violation
+}
+
+// Binary is the audit of one main package.
+type Binary struct {
+	Main       string
+	Deps       []string // sorted
+	ThirdParty []string // sorted, see depgraph.DepGraph.IsThirdParty
+	Tree       *Node    // import tree rooted at Main
+}
+
+// Node is a package of an import tree. Every package is expanded once,
+// where the tree reaches it first in depth-first order; its later
+// occurrences are leaves with Seen set.
+type Node struct {
+	Package string
+	Imports []*Node
+	Seen    bool
+}
+
+// importTree returns the tree of the build imports of pkg.
+func importTree(g *depgraph.DepGraph, pkg string, expanded map[string]bool) *Node {
+	n := &Node{Package: pkg, Seen: expanded[pkg]}
+	if n.Seen {
+		return n
+	}
+	expanded[pkg] = true
+	for _, p := range g.Imports(pkg) {
+		if e := g.Edge(pkg, p); e != nil && !e.TestOnly {
+			n.Imports = append(n.Imports, importTree(g, p, expanded))
+		}
+	}
+	return n
+}
+
+// Collect audits g, with the violations found by the policy checks run
+// on it.
+func Collect(g *depgraph.DepGraph, violations []rules.Violation) *Audit {
+	a := &Audit{
+		Mains:      g.CountMain(),
+		Tests:      g.CountTest(),
+		Standard:   g.CountStandard(),
+		Unused:     g.ListUnUsed(),
+		Build:      g.Build().String(),
+		Violations: violations,
+	}
+	for _, p := range g.Packages() {
+		a.Packages++
+		if g.IsThirdParty(p) {
+			a.ThirdParty++
+		}
+		if !g.IsMainPackage(p) {
+			continue
+		}
+		b := Binary{Main: p, Deps: g.Deps(p), Tree: importTree(g, p, make(map[string]bool))}
+		for _, dep := range b.Deps {
+			if g.IsThirdParty(dep) {
+				b.ThirdParty = append(b.ThirdParty, dep)
+			}
+		}
+		a.Binaries = append(a.Binaries, b)
+	}
+	return a
+}
@@ -0,0 +1,102 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+func testAudit() *Audit {
+	dg := &depgraph.DepGraph{}
+	app := &depgraph.Module{Path: "example.com/app", Main: true}
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/app/cmd/api", Name: "main", Module: app,
+		Imports: []string{"example.com/app/db", "fmt"}, Deps: []string{"example.com/app/db", "fmt", "github.com/lib/pq"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/app/db", Module: app,
+		Imports: []string{"github.com/lib/pq"}, Deps: []string{"github.com/lib/pq"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "github.com/lib/pq", Module: &depgraph.Module{Path: "github.com/lib/pq"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/app/old", Module: app})
+	dg.Add(depgraph.DepInfo{ImportPath: "fmt", Standard: true})
+	dg.SetBuild(depgraph.BuildProfile{GOOS: "linux", Tags: []string{"netgo"}})
+	rule := &rules.Rule{Name: "no-sql"}
+	violations := []rules.Violation{{Rule: rule, Package: "example.com/app/cmd/api", Dep: "github.com/lib/pq",
+		Chain: []string{"example.com/app/cmd/api", "example.com/app/db", "github.com/lib/pq"}}}
+	return Collect(dg, violations)
+}
+
+func TestCollect(t *testing.T) {
+	a := testAudit()
+	if a.Packages != 5 || a.Mains != 1 || a.Standard != 1 || a.ThirdParty != 1 {
+		t.Error(a)
+	}
+	if len(a.Binaries) != 1 || strings.Join(a.Binaries[0].ThirdParty, " ") != "github.com/lib/pq" ||
+		len(a.Binaries[0].Deps) != 3 {
+		t.Error(a.Binaries)
+	}
+	if strings.Join(a.Unused, " ") != "example.com/app/old" {
+		t.Error(a.Unused)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Markdown(&buf, testAudit()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		"Build profile: `GOOS=linux -tags=netgo`\n",
+		"| third-party | 1 |",
+		"### `example.com/app/cmd/api`\n\n1 of 3 deps are third-party:\n\n- `github.com/lib/pq`\n",
+		"## Unused packages\n\n- `example.com/app/old`\n",
+		"- **no-sql**: `example.com/app/cmd/api -> example.com/app/db -> github.com/lib/pq`\n",
+	} {
+		if !strings.Contains(out, s) {
+			t.Error("missing", s, "in\n"+out)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	a := testAudit()
+	if tree := a.Binaries[0].Tree; len(tree.Imports) != 2 || tree.Imports[0].Package != "example.com/app/db" ||
+		tree.Imports[0].Imports[0].Package != "github.com/lib/pq" {
+		t.Error("tree error", tree)
+	}
+	var buf bytes.Buffer
+	if err := HTML(&buf, a); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		`<li data-pkg="example.com/app/db"><details><summary>example.com/app/db</summary><ul><li data-pkg="github.com/lib/pq">github.com/lib/pq</li>`,
+		"<b>no-sql</b>: example.com/app/cmd/api &rarr; example.com/app/db &rarr; github.com/lib/pq",
+		`<script>`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Error("missing", s, "in\n"+out)
+		}
+	}
+	if strings.Contains(out, "http://") || strings.Contains(out, "https://") {
+		t.Error("report should be self-contained")
+	}
+}
+
+func TestImportTree(t *testing.T) {
+	dg := &depgraph.DepGraph{}
+	dg.AddEdge("main", "a")
+	dg.AddEdge("main", "b")
+	dg.AddEdge("a", "c")
+	dg.AddEdge("b", "c")
+	dg.AddEdge("c", "d")
+	tree := importTree(dg, "main", make(map[string]bool))
+	a, b := tree.Imports[0], tree.Imports[1]
+	if len(a.Imports) != 1 || a.Imports[0].Seen || len(a.Imports[0].Imports) != 1 {
+		t.Error("c should be expanded under a", a.Imports[0])
+	}
+	if len(b.Imports) != 1 || !b.Imports[0].Seen || b.Imports[0].Imports != nil {
+		t.Error("c should be a leaf under b", b.Imports[0])
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/labels"
+	"github.com/ma6174/go_dep_search/owners"
+)
+
+// heldResult is a result printPackage holds for -groupby or -sort.
+type heldResult struct {
+	depgraph.Result
+	dg     *depgraph.DepGraph
+	target string // package searched for
+	search int    // index of target in the args
+	group  string
+	line   string
+}
+
+var (
+	codeOwners owners.Owners
+	mainLabels labels.Labels
+	selector   labels.Selector // from -label, nil without it
+	held       []heldResult
+	matches    int
+	// the package the results being printed were found for
+	searchTarget string
+	searchIndex  int
+)
+
+// loadOwners reads *ownersFile, if set.
+func loadOwners() {
+	if *ownersFile == "" {
+		return
+	}
+	var err error
+	if codeOwners, err = owners.LoadFile(*ownersFile); err != nil {
+		fail(exitParse, "load owners file failed %v", err)
+	}
+}
+
+// loadLabels reads *labelsFile and parses the *labelSelector, if set.
+func loadLabels() {
+	if *labelsFile != "" {
+		var err error
+		if mainLabels, err = labels.LoadFile(*labelsFile); err != nil {
+			fail(exitParse, "load labels file failed %v", err)
+		}
+	}
+	if *labelSelector != "" {
+		if *labelsFile == "" {
+			fail(exitUsage, "-label needs -labels")
+		}
+		var err error
+		if selector, err = labels.ParseSelector(*labelSelector); err != nil {
+			fail(exitUsage, "-label: %v", err)
+		}
+	}
+}
+
+// ownerNote returns " (owner...)" naming the owners of p from -owners, or
+// "" if nobody owns it.
+func ownerNote(p string) string {
+	if o := codeOwners.Of(p); len(o) > 0 {
+		return " (" + strings.Join(o, " ") + ")"
+	}
+	return ""
+}
+
+// parseSort checks the -sort order, if any.
+func parseSort() depgraph.SortOrder {
+	if *sortBy == "" {
+		return ""
+	}
+	by, err := depgraph.ParseSortOrder(*sortBy)
+	if err != nil {
+		fail(exitUsage, "-sort: %v", err)
+	}
+	return by
+}
+
+// resultGroup returns the heading p is listed under with -groupby: its
+// module, its owners from -owners, or its value of a label from -labels.
+func resultGroup(dg *depgraph.DepGraph, p string) string {
+	if key := strings.TrimPrefix(*resultsBy, "label:"); key != *resultsBy {
+		if v, ok := mainLabels.Of(p)[key]; ok {
+			return key + "=" + v
+		}
+		return "(no " + key + ")"
+	}
+	switch *resultsBy {
+	case "module":
+		module := "(no module)"
+		if m := dg.Module(p); m != nil {
+			module = m.Path
+		} else if dg.IsStandard(p) {
+			module = "std"
+		}
+		if o := codeOwners.Of(p); len(o) > 0 {
+			module += " " + strings.Join(o, " ")
+		}
+		return module
+	case "owner":
+		if o := codeOwners.Of(p); len(o) > 0 {
+			return strings.Join(o, " ")
+		}
+		return "(no owner)"
+	}
+	fail(exitUsage, "unknown -groupby %v, supported: module,owner,label:KEY", *resultsBy)
+	return ""
+}
+
+// sortHeld sorts the held results of each search by -sort, keeping the
+// searches in args order.
+func sortHeld(by depgraph.SortOrder) {
+	if by == "" || len(held) == 0 {
+		return
+	}
+	less := make(map[int]func(a, b depgraph.Result) bool)
+	for _, r := range held {
+		if less[r.search] == nil {
+			less[r.search] = r.dg.ResultLess(r.target, by)
+		}
+	}
+	sort.SliceStable(held, func(i, j int) bool {
+		if held[i].search != held[j].search {
+			return held[i].search < held[j].search
+		}
+		return less[held[i].search](held[i].Result, held[j].Result)
+	})
+}
+
+// flushResults prints the results held by printPackage, sorted with -sort
+// and under their -groupby heading, sorted by group. With -count it prints
+// the number of results, per group with -groupby, and exits with
+// exitNotFound when there is none.
+func flushResults() {
+	sortHeld(parseSort())
+	if *resultsBy == "" {
+		for _, r := range held {
+			fmt.Println(r.line)
+		}
+	} else {
+		groups := make(map[string][]string)
+		var keys []string
+		for _, r := range held {
+			if _, ok := groups[r.group]; !ok {
+				keys = append(keys, r.group)
+			}
+			groups[r.group] = append(groups[r.group], r.line)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if *countOnly {
+				fmt.Printf("%s: %d\n", key, len(groups[key]))
+				continue
+			}
+			fmt.Println(key + ":")
+			for _, line := range groups[key] {
+				fmt.Println("\t" + strings.TrimPrefix(line, "\t"))
+			}
+		}
+	}
+	held = nil
+	if !*countOnly {
+		return
+	}
+	if *resultsBy == "" {
+		fmt.Println(matches)
+	}
+	if matches == 0 {
+		fail(exitNotFound, "no results")
+	}
+}
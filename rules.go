@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/issues"
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+// checkRules prints the violations of the ruleset in *rulesFile and exits
+// with status 1 if there are any.
+func checkRules(dg *depgraph.DepGraph) {
+	rs := loadRules()
+	printViolations(dg, func(g *depgraph.DepGraph) []rules.Violation { return rules.Evaluate(g, rs) })
+}
+
+func loadRules() *rules.Ruleset {
+	f, err := os.Open(*rulesFile)
+	if err != nil {
+		fail(exitParse, "open rules file failed %v", err)
+	}
+	defer f.Close()
+	rs, err := rules.Load(f)
+	if err != nil {
+		fail(exitParse, "load rules failed %v", err)
+	}
+	return rs
+}
+
+// checkInternal prints the imports crossing internal/ boundaries and
+// exits with status 1 if there are any.
+func checkInternal(dg *depgraph.DepGraph) {
+	printViolations(dg, rules.CheckInternal)
+}
+
+// printViolations prints the violations check finds in dg and exits with
+// status 1 if there are any. With -baseline they are marked NEW or
+// EXISTING, and only new ones make it fail. With -issues the violations
+// making it fail are written as issues instead.
+func printViolations(dg *depgraph.DepGraph, check func(*depgraph.DepGraph) []rules.Violation) {
+	violations := check(dg)
+	if len(violations) == 0 {
+		log.Println("no rule violations")
+		return
+	}
+	type key struct{ rule, pkg, dep string }
+	var old map[key]bool
+	if baseline.dg != nil {
+		old = make(map[key]bool)
+		for _, v := range check(baseline.dg) {
+			old[key{v.Rule.Name, v.Package, v.Dep}] = true
+		}
+	}
+	failed := false
+	var items []issues.Item
+	for _, v := range violations {
+		prefix := ""
+		if old != nil {
+			prefix = "NEW "
+			if old[key{v.Rule.Name, v.Package, v.Dep}] {
+				prefix = "EXISTING "
+			}
+		}
+		failed = failed || prefix != "EXISTING "
+		if *issueFormat == "" {
+			fmt.Println(prefix + v.String())
+		} else if prefix != "EXISTING " {
+			items = append(items, violationItem(v))
+		}
+	}
+	if items != nil {
+		writeIssues("dependency rule violation", items)
+	}
+	if failed {
+		fail(exitViolation, "dependency rules violated")
+	}
+}
+
+// checkCanImport tells whether the first arg may import the second one,
+// printing the import cycle and exiting with exitViolation if it can't.
+func checkCanImport(dg *depgraph.DepGraph) {
+	if flag.NArg() != 2 {
+		fail(exitUsage, "usage: -canimport <from_package> <to_package>")
+	}
+	if ok, cycle := dg.WouldCreateCycle(flag.Arg(0), flag.Arg(1)); ok {
+		fmt.Println("import cycle:", strings.Join(cycle, " -> "))
+		fail(exitViolation, "%v can't import %v", flag.Arg(0), flag.Arg(1))
+	}
+	log.Printf("%v can import %v", flag.Arg(0), flag.Arg(1))
+}
@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// InternalRule is the Rule of the violations CheckInternal returns.
+var InternalRule = &Rule{Name: "internal"}
+
+// internalParent returns the path whose tree may import the internal
+// package importPath, like the go command: "a/b/internal/c" may only be
+// imported from a/b and below it. ok is false for non-internal packages.
+// An empty parent means the standard library.
+func internalParent(importPath string) (parent string, ok bool) {
+	switch {
+	case strings.HasSuffix(importPath, "/internal"):
+		return strings.TrimSuffix(importPath, "/internal"), true
+	case strings.Contains(importPath, "/internal/"):
+		return importPath[:strings.LastIndex(importPath, "/internal/")], true
+	case importPath == "internal", strings.HasPrefix(importPath, "internal/"):
+		return "", true
+	}
+	return "", false
+}
+
+// mayImport reports whether importer may import dep as far as internal
+// packages are concerned, g telling the standard library packages.
+func mayImport(g *depgraph.DepGraph, importer, dep string) bool {
+	parent, ok := internalParent(dep)
+	if !ok {
+		return true
+	}
+	if parent == "" {
+		return g.IsStandard(importer)
+	}
+	return importer == parent || strings.HasPrefix(importer, parent+"/")
+}
+
+// CheckInternal returns the imports of g the go command would reject
+// because they cross an internal/ boundary. They can't occur in one build
+// but do in graphs merged from several repositories, where the compiler
+// never saw the packages together. The chain of each violation starts at
+// a main package depending on the importer, if any, and ends with the
+// illegal import. Violations are sorted by package and dependency. Test
+// binaries of go list -test are generated by the go command, which lets
+// them import testing/internal/testdeps, so they are not checked.
+func CheckInternal(g *depgraph.DepGraph) (violations []Violation) {
+	for _, p := range g.Packages() {
+		if g.IsTestPackage(p) {
+			continue
+		}
+		for _, dep := range g.Imports(p) {
+			if mayImport(g, p, dep) {
+				continue
+			}
+			chain := []string{p}
+			if mains := g.SearchMain(p); len(mains) > 0 {
+				sort.Strings(mains)
+				chain = depgraph.FindChain(g, mains[0], p)
+			}
+			violations = append(violations, Violation{
+				Rule:    InternalRule,
+				Package: p,
+				Dep:     dep,
+				Chain:   append(chain, dep),
+			})
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func TestCheckInternal(t *testing.T) {
+	for _, c := range []struct {
+		importer, dep string
+		ok            bool
+	}{
+		{"a/b", "a/b/internal/c", true},
+		{"a/b/x/y", "a/b/internal/c/d", true},
+		{"a/bc", "a/b/internal/c", false},
+		{"a/x", "a/b/internal", false},
+		{"net/http", "internal/poll", true},
+		{"example.com/x", "internal/poll", false},
+		{"a/internal/x", "a/internal/y/internal/z", false},
+		{"a/internal/y/w", "a/internal/y/internal/z", true},
+	} {
+		if mayImport(&depgraph.DepGraph{}, c.importer, c.dep) != c.ok {
+			t.Error(c)
+		}
+	}
+	// with the Standard fields a dotless path is not taken for the
+	// standard library
+	std := &depgraph.DepGraph{}
+	std.Add(depgraph.DepInfo{ImportPath: "corp/billing", Imports: []string{"internal/poll"}})
+	std.Add(depgraph.DepInfo{ImportPath: "net/http", Standard: true, Imports: []string{"internal/poll"}})
+	std.Add(depgraph.DepInfo{ImportPath: "internal/poll", Standard: true})
+	if mayImport(std, "corp/billing", "internal/poll") || !mayImport(std, "net/http", "internal/poll") {
+		t.Error("standard library internal packages error")
+	}
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/app/cmd/a", Name: "main",
+		Imports: []string{"example.com/app/lib"},
+		Deps:    []string{"example.com/app/lib", "example.com/other/internal/db"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/app/lib", Name: "lib",
+		Imports: []string{"example.com/other/internal/db"},
+		Deps:    []string{"example.com/other/internal/db"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/other/cmd/b", Name: "main",
+		Imports: []string{"example.com/other/internal/db"},
+		Deps:    []string{"example.com/other/internal/db"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/other/internal/db", Name: "db"})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/app/lib.test", Name: "main",
+		Imports: []string{"example.com/app/lib [example.com/app/lib.test]", "testing/internal/testdeps"}})
+	var got []string
+	for _, v := range CheckInternal(dg) {
+		got = append(got, v.String())
+	}
+	expect := "internal: example.com/app/cmd/a -> example.com/app/lib -> example.com/other/internal/db"
+	if strings.Join(got, "\n") != expect {
+		t.Error(got)
+	}
+}
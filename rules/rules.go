@@ -0,0 +1,135 @@
+// Package rules evaluates import policies, such as "nothing under cmd/
+// may depend on internal/legacy/...", against a dependency graph. The CLI
+// uses it for -rules; other linters can embed the same checks.
+package rules
+
+import (
+	"encoding/json"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Rule denies the packages matching From to depend on packages matching
+// Deny. If Only is set, they may moreover only depend on packages
+// matching it, where "std" stands for the standard library, eg: packages
+// under pkg/domain may only import std and pkg/domain/**. Packages matching
+// Allow are exempt from both. Patterns are import paths where a trailing "/..." or
+// "/**" matches the path and everything below it, "..." alone matches
+// everything, and other wildcards follow path.Match, eg:
+// "example.com/cmd/...", "*/internal/*".
+type Rule struct {
+	Name   string   `json:"name"`
+	From   []string `json:"from"`
+	Deny   []string `json:"deny,omitempty"`
+	Allow  []string `json:"allow,omitempty"`
+	Only   []string `json:"only,omitempty"`
+	Direct bool     `json:"direct,omitempty"` // only check direct imports
+}
+
+type Ruleset struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Load reads a JSON encoded Ruleset.
+func Load(r io.Reader) (*Ruleset, error) {
+	var rs Ruleset
+	if err := json.NewDecoder(r).Decode(&rs); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+// Violation is a dependency a rule denies.
+type Violation struct {
+	Rule    *Rule
+	Package string
+	Dep     string
+	Chain   []string // Package -> ... -> Dep
+}
+
+func (v Violation) String() string {
+	return v.Rule.Name + ": " + strings.Join(v.Chain, " -> ")
+}
+
+// Match reports whether importPath matches pattern.
+func Match(pattern, importPath string) bool {
+	if pattern == "..." {
+		return true
+	}
+	prefix := strings.TrimSuffix(pattern, "/...")
+	if prefix == pattern {
+		prefix = strings.TrimSuffix(pattern, "/**")
+	}
+	if prefix != pattern {
+		return importPath == prefix || strings.HasPrefix(importPath, prefix+"/")
+	}
+	ok, _ := path.Match(pattern, importPath)
+	return ok
+}
+
+func matchAny(patterns []string, importPath string) bool {
+	for _, p := range patterns {
+		if Match(p, importPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate checks every loaded package of g against rs and returns the
+// violations sorted by rule, package and dependency.
+func Evaluate(g *depgraph.DepGraph, rs *Ruleset) (violations []Violation) {
+	packages := g.Packages()
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		for _, p := range packages {
+			if !matchAny(r.From, p) {
+				continue
+			}
+			var deps []string
+			if r.Direct {
+				deps = g.Imports(p)
+			} else {
+				for dep := range g.Descendants(p) {
+					deps = append(deps, dep)
+				}
+				sort.Strings(deps)
+			}
+			for _, dep := range deps {
+				if !denied(g, r, dep) {
+					continue
+				}
+				violations = append(violations, Violation{
+					Rule:    r,
+					Package: p,
+					Dep:     dep,
+					Chain:   depgraph.FindChain(g, p, dep),
+				})
+			}
+		}
+	}
+	return
+}
+
+// denied reports whether r forbids depending on dep.
+func denied(g *depgraph.DepGraph, r *Rule, dep string) bool {
+	if matchAny(r.Allow, dep) {
+		return false
+	}
+	if matchAny(r.Deny, dep) {
+		return true
+	}
+	if len(r.Only) == 0 || matchAny(r.Only, dep) {
+		return false
+	}
+	for _, p := range r.Only {
+		if p == "std" && g.IsStandard(dep) {
+			return false
+		}
+	}
+	return true
+}
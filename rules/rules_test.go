@@ -0,0 +1,72 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+const ruleset = `{"rules": [
+	{"name": "no-legacy", "from": ["example.com/cmd/..."], "deny": ["example.com/legacy/..."],
+	 "allow": ["example.com/legacy/compat"]},
+	{"name": "direct-unsafe", "from": ["..."], "deny": ["unsafe"], "direct": true}
+]}`
+
+func TestEvaluate(t *testing.T) {
+	if !Match("a/b/...", "a/b") || !Match("a/b/...", "a/b/c/d") || Match("a/b/...", "a/bc") {
+		t.Error("... pattern error")
+	}
+	if !Match("*/internal/*", "x/internal/y") || Match("*/internal/*", "x/internal/y/z") {
+		t.Error("wildcard pattern error")
+	}
+	rs, err := Load(strings.NewReader(ruleset))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg := &depgraph.DepGraph{}
+	dg.AddEdge("example.com/cmd/a", "example.com/lib")
+	dg.AddEdge("example.com/lib", "example.com/legacy/db")
+	dg.AddEdge("example.com/lib", "example.com/legacy/compat")
+	dg.AddEdge("example.com/legacy/db", "unsafe")
+	var got []string
+	for _, v := range Evaluate(dg, rs) {
+		got = append(got, v.String())
+	}
+	expect := []string{
+		"no-legacy: example.com/cmd/a -> example.com/lib -> example.com/legacy/db",
+		"direct-unsafe: example.com/legacy/db -> unsafe",
+	}
+	if strings.Join(got, "\n") != strings.Join(expect, "\n") {
+		t.Error("result error:\n" + strings.Join(got, "\n"))
+	}
+}
+
+func TestEvaluateOnly(t *testing.T) {
+	if !Match("a/b/**", "a/b") || !Match("a/b/**", "a/b/c/d") || Match("a/b/**", "a/bc") {
+		t.Error("** pattern error")
+	}
+	rs, err := Load(strings.NewReader(`{"rules": [{"name": "hexagonal", "from": ["example.com/pkg/domain/**"],
+		"only": ["std", "example.com/pkg/domain/**"], "allow": ["github.com/google/uuid"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dg := &depgraph.DepGraph{}
+	dg.AddEdge("example.com/pkg/domain/order", "example.com/pkg/domain/money")
+	dg.AddEdge("example.com/pkg/domain/order", "github.com/google/uuid")
+	dg.AddEdge("example.com/pkg/domain/money", "math/big")
+	dg.AddEdge("example.com/pkg/domain/money", "example.com/pkg/db")
+	dg.AddEdge("example.com/pkg/db", "database/sql")
+	dg.AddEdge("example.com/cmd/api", "example.com/pkg/db")
+	var got []string
+	for _, v := range Evaluate(dg, rs) {
+		got = append(got, v.String())
+	}
+	expect := []string{
+		"hexagonal: example.com/pkg/domain/money -> example.com/pkg/db",
+		"hexagonal: example.com/pkg/domain/order -> example.com/pkg/domain/money -> example.com/pkg/db",
+	}
+	if strings.Join(got, "\n") != strings.Join(expect, "\n") {
+		t.Error("result error:\n" + strings.Join(got, "\n"))
+	}
+}
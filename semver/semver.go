@@ -0,0 +1,94 @@
+// Package semver compares the module versions found in go.mod files,
+// module proxies and vulnerability databases. It knows just enough of
+// semantic versioning for them: numeric major.minor.patch, missing parts
+// counting as 0, prereleases, pseudo-versions included, before their
+// release and ordered identifier by identifier, and build metadata like
+// +incompatible ignored. The leading "v" is optional, as OSV omits it.
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Compare compares the versions a and b, returning -1, 0 or 1.
+func Compare(a, b string) int {
+	aCore, aPre := split(a)
+	bCore, bPre := split(b)
+	if c := compareDotted(aCore, bCore, 3); c != 0 {
+		return c
+	}
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+	return compareDotted(aPre, bPre, 0)
+}
+
+// IsPrerelease reports whether v is a prerelease or a pseudo-version.
+func IsPrerelease(v string) bool {
+	_, pre := split(v)
+	return pre != ""
+}
+
+// split returns the major.minor.patch and the prerelease of v, without
+// its leading "v" and build metadata.
+func split(v string) (core, pre string) {
+	v = strings.SplitN(strings.TrimPrefix(v, "v"), "+", 2)[0]
+	if i := strings.Index(v, "-"); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
+
+// compareDotted compares dot separated identifiers, numerically where both
+// are numbers. At least min identifiers are compared, missing ones count
+// as 0.
+func compareDotted(a, b string, min int) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	n := len(as)
+	if len(bs) > n {
+		n = len(bs)
+	}
+	if min > n {
+		n = min
+	}
+	for i := 0; i < n; i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		} else if min == 0 {
+			return -1
+		}
+		if i < len(bs) {
+			y = bs[i]
+		} else if min == 0 {
+			return 1
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case xerr == nil:
+			return -1
+		case yerr == nil:
+			return 1
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
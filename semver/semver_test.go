@@ -0,0 +1,42 @@
+package semver
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"v1.2.3", "v1.10.0", -1},
+		{"v0.3.7", "v0.3.6", 1},
+		{"v2", "v2.0.0", 0},
+		{"v2.0.0+incompatible", "v1.9.9", 1},
+		{"v2.0.0+incompatible", "v2.0.0", 0},
+		{"v2.1.0-rc.1+incompatible", "v2.1.0+incompatible", -1},
+		{"v1.0.0-rc.1", "v1.0.0", -1},
+		{"v1.0.0-rc.2", "v1.0.0-rc.10", -1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-1", "v1.0.0-alpha", -1},
+		{"v0.0.0-20210101000000-abcdef", "v0.0.0-20200101000000-abcdef", 1},
+		{"v0.0.0-20200101000000-abcdefabcdef", "v0.1.0", -1},
+	} {
+		if got := Compare(c.a, c.b); got != c.want {
+			t.Errorf("Compare(%v, %v) = %v, want %v", c.a, c.b, got, c.want)
+		}
+		if got := Compare(c.b, c.a); got != -c.want {
+			t.Errorf("Compare(%v, %v) = %v, want %v", c.b, c.a, got, -c.want)
+		}
+	}
+	for v, want := range map[string]bool{
+		"v1.0.0":                             false,
+		"v2.0.0+incompatible":                false,
+		"v1.0.0-rc.1":                        true,
+		"v0.0.0-20200101000000-abcdefabcdef": true,
+	} {
+		if IsPrerelease(v) != want {
+			t.Error(v, want)
+		}
+	}
+}
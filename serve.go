@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/rules"
+	"github.com/ma6174/go_dep_search/server"
+	"github.com/ma6174/go_dep_search/snapshot"
+)
+
+// openInput returns where the go list output is read from: stdin, unless
+// stdin carries the JSON-RPC requests or the output is watched.
+func openInput() *os.File {
+	file := *rpcFile
+	if *watchFile != "" {
+		file = *watchFile
+	}
+	if file == "" {
+		return os.Stdin
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		fail(exitParse, "open deps file failed %v", err)
+	}
+	return f
+}
+
+// serveRPC answers JSON-RPC requests on stdin until it is closed.
+func serveRPC(dg *depgraph.DepGraph) {
+	s := server.NewService(dg)
+	s.Watchlist = loadWatchlist(dg)
+	log.Println("serving JSON-RPC on stdin/stdout")
+	if err := server.ServeJSONRPC(s, os.Stdin, os.Stdout); err != nil {
+		fatalln("serve failed", err)
+	}
+}
+
+// serveWeb serves the web view on *httpAddr, focused on the first arg.
+func serveWeb(dg *depgraph.DepGraph) {
+	web := server.NewWeb(dg, flag.Arg(0))
+	web.Watchlist = loadWatchlist(dg)
+	log.Printf("serving web view on http://%s/", *httpAddr)
+	fatalln(http.ListenAndServe(*httpAddr, web))
+}
+
+// loadWatchlist computes the chains to the packages of -watchlist, nil if
+// it is not set.
+func loadWatchlist(dg *depgraph.DepGraph) *server.Watchlist {
+	if *watchlist == "" {
+		return nil
+	}
+	start := time.Now()
+	w := server.NewWatchlist(dg, strings.Split(*watchlist, ","), *concurrency)
+	log.Printf("computed the chains to %d watched packages in %v", len(w.Packages()), time.Since(start).Round(time.Millisecond))
+	return w
+}
+
+// serveFederation serves the graphs uploaded per repository on
+// *federateAddr.
+func serveFederation() {
+	log.Printf("serving federation on http://%s/", *federateAddr)
+	f := server.NewFederation()
+	if *limits != "" {
+		f.Limits = loadLimits()
+	}
+	fatalln(http.ListenAndServe(*federateAddr, f.Handler(prepareGraph)))
+}
+
+// runDaemon re-runs go list on the -load patterns every *daemonInterval,
+// saves the graphs standing queries changed on in the -store and reports
+// the changes as JSON events, one per line, to stdout or to *webhook. The
+// standing queries are the main packages depending on each arg, the
+// -rules violations and the module versions.
+func runDaemon() {
+	if *load == "" {
+		fail(exitUsage, "-daemon needs -load")
+	}
+	queries := []server.Query{server.ModulesQuery()}
+	for _, p := range flag.Args() {
+		queries = append(queries, server.UsageQuery(p))
+	}
+	if *rulesFile != "" {
+		rs := loadRules()
+		queries = append(queries, server.Query{Kind: "rules", Name: *rulesFile, Answer: func(g *depgraph.DepGraph) (violations []string) {
+			for _, v := range rules.Evaluate(g, rs) {
+				violations = append(violations, v.String())
+			}
+			return
+		}})
+	}
+	store := openStore()
+	var lastOutput string
+	d := &server.Daemon{
+		Interval: *daemonInterval,
+		Queries:  queries,
+		Load: func() (*depgraph.DepGraph, error) {
+			build := buildProfile()
+			out, err := listPackages(".", build)
+			if err != nil {
+				return nil, fmt.Errorf("go list %v: %v", *load, err)
+			}
+			lastOutput = out
+			return readGraph(strings.NewReader(out), "golist", build)
+		},
+		Save: func(g *depgraph.DepGraph) (string, error) {
+			now := time.Now()
+			meta := snapshot.Snapshot{Name: "daemon-" + now.UTC().Format("20060102T150405Z"), Time: now,
+				Packages: len(g.Packages()), Build: g.Build()}
+			if err := store.Save(meta, strings.NewReader(lastOutput)); err != nil {
+				return "", err
+			}
+			return meta.Name, store.SaveIDs(meta.Name, g.StableIDs())
+		},
+		Notify: func(events []server.Event) error {
+			enc := json.NewEncoder(os.Stdout)
+			for _, e := range events {
+				if err := enc.Encode(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	if *webhook != "" {
+		d.Notify = server.NewWebhook(*webhook).NotifyEvents
+	}
+	g, err := d.Load()
+	if err != nil {
+		fatalln(err)
+	}
+	log.Printf("checking %v every %v", *load, d.Interval)
+	d.Run(g, nil)
+}
+
+// runIndexer keeps the graph of the working tree in sync with it, listing
+// again only the packages whose files changed, and serves JSON-RPC queries
+// on *indexerAddr, a unix socket path or host:port, until killed.
+func runIndexer() {
+	build := buildProfile()
+	x := &server.Indexer{
+		Dir:      ".",
+		Interval: *indexInterval,
+		List: func(dirs []string) (string, error) {
+			if dirs == nil {
+				return listPackages(".", build)
+			}
+			return listDirs(".", build, dirs)
+		},
+		// the graph is updated in place, so the query flags turning it
+		// into another one, like -prune, don't apply
+		Load: func(r io.Reader) (*depgraph.DepGraph, error) {
+			dg, err := newGraph(build)
+			if err != nil {
+				return nil, err
+			}
+			dg.SetConcurrency(*concurrency)
+			return dg, dg.Load(r)
+		},
+	}
+	start := time.Now()
+	if err := x.Start(); err != nil {
+		fail(exitParse, "go list failed %v", err)
+	}
+	log.Printf("indexed %d packages in %v", len(x.Graph().Packages()), time.Since(start).Round(time.Millisecond))
+	network := "unix"
+	if strings.Contains(*indexerAddr, ":") && !strings.ContainsAny(*indexerAddr, `/\`) {
+		network = "tcp"
+	}
+	// a socket left over by a previous run, nothing else is removed
+	if fi, err := os.Lstat(*indexerAddr); network == "unix" && err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(*indexerAddr)
+	}
+	l, err := net.Listen(network, *indexerAddr)
+	if err != nil {
+		fatalln(err)
+	}
+	go x.Run(nil)
+	log.Printf("serving JSON-RPC on %s %s", network, *indexerAddr)
+	fatalln(server.ServeListener(server.NewLiveService(x.Graph), l))
+}
+
+const watchInterval = 5 * time.Second
+
+// watch reports the changes of the main packages depending on the args
+// each time *watchFile is rewritten, to stdout or to *webhook.
+func watch(dg *depgraph.DepGraph) {
+	w := &server.Watcher{
+		Path:     *watchFile,
+		Interval: watchInterval,
+		Packages: flag.Args(),
+		Load:     loadGraph,
+		Notify: func(changes []server.Change) error {
+			for _, c := range changes {
+				fmt.Println(c)
+			}
+			return nil
+		},
+	}
+	if *webhook != "" {
+		w.Notify = server.NewWebhook(*webhook).Notify
+	}
+	log.Printf("watching %s", *watchFile)
+	w.Run(dg, nil)
+}
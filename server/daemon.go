@@ -0,0 +1,152 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Query is a standing query of a Daemon: its answer on each graph loaded
+// is compared with the previous one.
+type Query struct {
+	Kind   string // eg: "usage", "rules", "modules"
+	Name   string // eg: the package or the ruleset the query is about
+	Answer func(*depgraph.DepGraph) []string
+}
+
+// UsageQuery answers the main packages depending on pkg, like Watcher.
+func UsageQuery(pkg string) Query {
+	return Query{Kind: "usage", Name: pkg, Answer: func(g *depgraph.DepGraph) []string {
+		return g.SearchMain(pkg)
+	}}
+}
+
+// ModulesQuery answers the path@version of the modules of the graph, so
+// module additions, removals and upgrades are reported.
+func ModulesQuery() Query {
+	return Query{Kind: "modules", Name: "modules", Answer: func(g *depgraph.DepGraph) (modules []string) {
+		for _, m := range g.Modules() {
+			if !m.Main {
+				modules = append(modules, m.Path+"@"+m.Version)
+			}
+		}
+		return
+	}}
+}
+
+// Event is a change of the answer of a standing query.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Snapshot string    `json:"snapshot,omitempty"` // snapshot of the new graph, if saved
+	Kind     string    `json:"kind"`
+	Query    string    `json:"query"`
+	Added    []string  `json:"added,omitempty"`   // in the new answer only
+	Removed  []string  `json:"removed,omitempty"` // in the old answer only
+}
+
+func (e Event) String() string {
+	var parts []string
+	if len(e.Added) > 0 {
+		parts = append(parts, "+ "+strings.Join(e.Added, ", "))
+	}
+	if len(e.Removed) > 0 {
+		parts = append(parts, "- "+strings.Join(e.Removed, ", "))
+	}
+	return fmt.Sprintf("%s %s: %s", e.Kind, e.Query, strings.Join(parts, "; "))
+}
+
+// Daemon reloads the graph every Interval, evaluates Queries on it and
+// reports the answers that changed since the previous load.
+type Daemon struct {
+	Interval time.Duration
+	Queries  []Query
+	Load     func() (*depgraph.DepGraph, error)
+	// Save, if set, persists a graph the events are about and returns the
+	// name of its snapshot.
+	Save   func(*depgraph.DepGraph) (string, error)
+	Notify func([]Event) error
+}
+
+// answers evaluates the queries on g, each answer as a set.
+func (d *Daemon) answers(g *depgraph.DepGraph) []map[string]bool {
+	answers := make([]map[string]bool, len(d.Queries))
+	for i, q := range d.Queries {
+		answers[i] = set(q.Answer(g))
+	}
+	return answers
+}
+
+// events compares the answers old and new of the queries.
+func (d *Daemon) events(old, new []map[string]bool, now time.Time) (events []Event) {
+	for i, q := range d.Queries {
+		e := Event{Time: now, Kind: q.Kind, Query: q.Name, Added: missing(new[i], old[i]), Removed: missing(old[i], new[i])}
+		if len(e.Added) > 0 || len(e.Removed) > 0 {
+			events = append(events, e)
+		}
+	}
+	return
+}
+
+// Run saves g, the graph currently loaded, then reloads every Interval
+// until done is closed. Failures to load, save or notify are logged and
+// retried on the next run: the answers are only committed once notified,
+// and the snapshot saved for a failed notification is reused while the
+// answers stay the same.
+func (d *Daemon) Run(g *depgraph.DepGraph, done <-chan struct{}) {
+	d.save(g)
+	answers := d.answers(g)
+	// saved but not notified yet
+	var pending []map[string]bool
+	var snapshot string
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		newGraph, err := d.Load()
+		if err != nil {
+			log.Println("reload failed", err)
+			continue
+		}
+		now := time.Now()
+		newAnswers := d.answers(newGraph)
+		events := d.events(answers, newAnswers, now)
+		if len(events) == 0 {
+			answers, pending = newAnswers, nil
+			continue
+		}
+		if pending == nil || len(d.events(pending, newAnswers, now)) > 0 {
+			pending, snapshot = newAnswers, d.save(newGraph)
+		}
+		if snapshot != "" {
+			for i := range events {
+				events[i].Snapshot = snapshot
+			}
+		}
+		if err := d.Notify(events); err != nil {
+			log.Println("notify failed", err)
+			continue
+		}
+		answers, pending = newAnswers, nil
+	}
+}
+
+// save calls Save, if set, and returns the name of the snapshot, "" if
+// it failed.
+func (d *Daemon) save(g *depgraph.DepGraph) string {
+	if d.Save == nil {
+		return ""
+	}
+	name, err := d.Save(g)
+	if err != nil {
+		log.Println("save snapshot failed", err)
+		return ""
+	}
+	return name
+}
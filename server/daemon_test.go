@@ -0,0 +1,121 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func TestDaemon(t *testing.T) {
+	graphs := []*depgraph.DepGraph{mainsUsing("lib", "cmd/a"), mainsUsing("lib", "cmd/a", "cmd/b")}
+	var mu sync.Mutex
+	loads := 0
+	var saved []*depgraph.DepGraph
+	notified := make(chan []Event, 1)
+	d := &Daemon{
+		Interval: time.Millisecond,
+		Queries:  []Query{UsageQuery("lib"), ModulesQuery()},
+		Load: func() (*depgraph.DepGraph, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			// unchanged first, then cmd/b starts using lib
+			loads++
+			if loads == 1 {
+				return graphs[0], nil
+			}
+			return graphs[1], nil
+		},
+		Save: func(g *depgraph.DepGraph) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			saved = append(saved, g)
+			return "snap", nil
+		},
+		Notify: func(e []Event) error { notified <- e; return nil },
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go d.Run(graphs[0], done)
+	select {
+	case events := <-notified:
+		expect := []Event{{Time: events[0].Time, Snapshot: "snap", Kind: "usage", Query: "lib", Added: []string{"cmd/b"}}}
+		if !reflect.DeepEqual(events, expect) {
+			t.Error(events)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(saved) != 2 || saved[0] != graphs[0] || saved[1] != graphs[1] {
+		t.Error("expect the first graph and the changed one saved", saved)
+	}
+}
+
+func TestDaemonNotifyFailure(t *testing.T) {
+	var mu sync.Mutex
+	saves, calls := 0, 0
+	notified := make(chan []Event, 1)
+	d := &Daemon{
+		Interval: time.Millisecond,
+		Queries:  []Query{UsageQuery("lib")},
+		Load:     func() (*depgraph.DepGraph, error) { return mainsUsing("lib", "cmd/a", "cmd/b"), nil },
+		Save: func(g *depgraph.DepGraph) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			saves++
+			return fmt.Sprint("snap", saves), nil
+		},
+		Notify: func(e []Event) error {
+			if calls++; calls == 1 {
+				return errors.New("webhook down")
+			}
+			notified <- e
+			return nil
+		},
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go d.Run(mainsUsing("lib", "cmd/a"), done)
+	select {
+	case events := <-notified:
+		// the failed notification is sent again, with the snapshot saved
+		// for it
+		if len(events) != 1 || !reflect.DeepEqual(events[0].Added, []string{"cmd/b"}) || events[0].Snapshot != "snap2" {
+			t.Error(events)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if saves != 2 {
+		t.Error("expect the first graph and the changed one saved once", saves)
+	}
+}
+
+func TestWebhookEvents(t *testing.T) {
+	var payload struct {
+		Text   string
+		Events []Event
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer srv.Close()
+	err := NewWebhook(srv.URL).NotifyEvents([]Event{{Kind: "usage", Query: "lib", Added: []string{"cmd/a"}, Removed: []string{"cmd/b"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.Text != "usage lib: + cmd/a; - cmd/b" || len(payload.Events) != 1 || payload.Events[0].Query != "lib" {
+		t.Error(payload)
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Federation holds the graphs of many repositories, tagged by repository
+// name, to answer organization wide questions such as which repositories
+// and binaries depend on a package.
+type Federation struct {
+	Limits        depgraph.Limits // bounds the uploaded graphs
+	MaxUploadSize int64           // bytes of an upload, 0 for no limit
+
+	mu     sync.RWMutex
+	graphs map[string]*depgraph.DepGraph
+}
+
+// DefaultMaxUploadSize bounds the uploads of NewFederation, several times
+// the go list -json output of the largest monorepos.
+const DefaultMaxUploadSize = 4 << 30
+
+func NewFederation() *Federation {
+	return &Federation{Limits: depgraph.DefaultLimits, MaxUploadSize: DefaultMaxUploadSize,
+		graphs: make(map[string]*depgraph.DepGraph)}
+}
+
+// Upload adds the graph of repo, replacing the previous one.
+func (f *Federation) Upload(repo string, g *depgraph.DepGraph) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.graphs[repo] = g
+}
+
+// Repos returns the names of the uploaded repositories, sorted.
+func (f *Federation) Repos() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	repos := make([]string, 0, len(f.graphs))
+	for repo := range f.graphs {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// RepoUsers is the main packages of one repository depending on a package.
+type RepoUsers struct {
+	Repo  string
+	Mains []string
+}
+
+// Users returns, per repository, the main packages depending on
+// packageName, sorted by repository. Repositories not depending on it are
+// left out.
+func (f *Federation) Users(packageName string) []RepoUsers {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	users := []RepoUsers{}
+	for repo, g := range f.graphs {
+		mains := g.SearchMain(packageName)
+		if len(mains) == 0 {
+			continue
+		}
+		sort.Strings(mains)
+		users = append(users, RepoUsers{Repo: repo, Mains: mains})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Repo < users[j].Repo })
+	return users
+}
+
+// Handler serves the federation over HTTP:
+//
+//	PUT /repos/<repo>           upload the go list -json output of repo,
+//	                            413 if it goes over f.Limits or
+//	                            f.MaxUploadSize
+//	GET /repos                  list the uploaded repositories
+//	GET /users?package=<pkg>    repositories and main packages using pkg
+//
+// prepare is applied to every uploaded graph, eg: to freeze it.
+func (f *Federation) Handler(prepare func(*depgraph.DepGraph) *depgraph.DepGraph) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/", func(w http.ResponseWriter, r *http.Request) {
+		repo := strings.TrimPrefix(r.URL.Path, "/repos/")
+		if r.Method != http.MethodPut || repo == "" {
+			http.Error(w, "PUT /repos/<repo> expected", http.StatusMethodNotAllowed)
+			return
+		}
+		body := &countingReader{r: r.Body}
+		if max := f.MaxUploadSize; max > 0 {
+			if r.ContentLength > max {
+				http.Error(w, fmt.Sprintf("upload exceeds %d bytes", max), http.StatusRequestEntityTooLarge)
+				return
+			}
+			body.r = http.MaxBytesReader(w, r.Body, max)
+		}
+		g := &depgraph.DepGraph{}
+		g.SetLimits(f.Limits)
+		if err := g.Load(body); err != nil {
+			code := http.StatusBadRequest
+			if _, ok := err.(*depgraph.LimitError); ok || f.MaxUploadSize > 0 && body.n >= f.MaxUploadSize {
+				code = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), code)
+			return
+		}
+		if prepare != nil {
+			g = prepare(g)
+		}
+		f.Upload(repo, g)
+	})
+	mux.HandleFunc("/repos", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, f.Repos())
+	})
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		pkg := r.URL.Query().Get("package")
+		if pkg == "" {
+			http.Error(w, "package expected", http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, f.Users(pkg))
+	})
+	return mux
+}
+
+// countingReader counts the bytes read through it, telling a body cut by
+// http.MaxBytesReader from a malformed one.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFederation(t *testing.T) {
+	f := NewFederation()
+	srv := httptest.NewServer(f.Handler(nil))
+	defer srv.Close()
+	upload := func(repo, deps string) {
+		req, _ := http.NewRequest(http.MethodPut, srv.URL+"/repos/"+repo, strings.NewReader(deps))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatal(repo, resp.Status)
+		}
+	}
+	upload("billing", `{"ImportPath": "billing/cmd/api", "Name": "main", "Imports": ["corp/auth"], "Deps": ["corp/auth"]}
+{"ImportPath": "corp/auth", "Name": "auth"}`)
+	upload("search", `{"ImportPath": "search/cmd/indexer", "Name": "main", "Imports": ["fmt"], "Deps": ["fmt"]}`)
+	upload("gateway", `{"ImportPath": "gateway/cmd/a", "Name": "main", "Imports": ["corp/auth"], "Deps": ["corp/auth"]}
+{"ImportPath": "gateway/cmd/b", "Name": "main", "Imports": ["corp/auth"], "Deps": ["corp/auth"]}
+{"ImportPath": "corp/auth", "Name": "auth"}`)
+
+	if repos := f.Repos(); !reflect.DeepEqual(repos, []string{"billing", "gateway", "search"}) {
+		t.Error(repos)
+	}
+	resp, err := http.Get(srv.URL + "/users?package=corp/auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var users []RepoUsers
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		t.Fatal(err)
+	}
+	expect := []RepoUsers{
+		{Repo: "billing", Mains: []string{"billing/cmd/api"}},
+		{Repo: "gateway", Mains: []string{"gateway/cmd/a", "gateway/cmd/b"}},
+	}
+	if !reflect.DeepEqual(users, expect) {
+		t.Error(users)
+	}
+}
+
+func TestFederationLimits(t *testing.T) {
+	f := NewFederation()
+	f.Limits.MaxImports = 1
+	srv := httptest.NewServer(f.Handler(nil))
+	defer srv.Close()
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/repos/big",
+		strings.NewReader(`{"ImportPath": "big/cmd/a", "Name": "main", "Imports": ["fmt", "os"], "Deps": ["fmt", "os"]}`))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge || len(f.Repos()) != 0 {
+		t.Error(resp.Status, f.Repos())
+	}
+}
+
+func TestFederationUploadSize(t *testing.T) {
+	f := NewFederation()
+	f.MaxUploadSize = 64
+	srv := httptest.NewServer(f.Handler(nil))
+	defer srv.Close()
+	upload := `{"ImportPath": "big/cmd/a", "Name": "main", "Imports": ["fmt"], "Deps": ["fmt"]}`
+	// with and without Content-Length
+	for _, body := range []io.Reader{strings.NewReader(upload), io.MultiReader(strings.NewReader(upload))} {
+		req, _ := http.NewRequest(http.MethodPut, srv.URL+"/repos/big", body)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusRequestEntityTooLarge || len(f.Repos()) != 0 {
+			t.Error(resp.Status, f.Repos())
+		}
+	}
+}
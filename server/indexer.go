@@ -0,0 +1,259 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Indexer keeps the graph of a working tree in sync with it for long
+// running clients, eg: editor plugins and pre-commit hooks, so they never
+// wait for a full go list run. It polls the Go files below Dir every
+// Interval and lists again only the packages of the directories whose
+// files changed, updating its graph in place; go.mod, go.sum, go.work and
+// vendor/modules.txt changes reload everything. Queries are answered from
+// a frozen copy of the graph swapped in after each update.
+//
+// Each poll walks the whole tree and stats every file, a cost growing
+// with its size whether anything changed or not, so large trees want a
+// longer Interval.
+type Indexer struct {
+	Dir      string
+	Interval time.Duration
+	// List returns the go list -json -deps output of the packages of dirs,
+	// relative to Dir like ./pkg/auth, or of the whole tree for nil dirs.
+	List func(dirs []string) (string, error)
+	// Load reads the graph of the whole tree from the output of List, not
+	// frozen, see DepGraph.Freeze.
+	Load func(r io.Reader) (*depgraph.DepGraph, error)
+
+	live   *depgraph.DepGraph
+	stamps map[string]stamp // directory or module file -> stamp
+
+	mu     sync.RWMutex
+	frozen *depgraph.DepGraph
+}
+
+// stamp tells whether the Go files of a directory changed.
+type stamp struct {
+	files   int
+	size    int64
+	modTime time.Time // of the latest modified file
+}
+
+func (s *stamp) add(fi os.FileInfo) {
+	s.files++
+	s.size += fi.Size()
+	if fi.ModTime().After(s.modTime) {
+		s.modTime = fi.ModTime()
+	}
+}
+
+func isModuleFile(name string) bool {
+	switch name {
+	case "go.mod", "go.sum", "go.work", "go.work.sum", "modules.txt":
+		return true
+	}
+	return false
+}
+
+// scan returns the stamps of the directories of Dir with Go files, by path
+// relative to Dir, and of its module files. Directories go ignores,
+// testdata and those starting with . or _, are skipped, vendor only has
+// its modules.txt stamped.
+func (x *Indexer) scan() (map[string]stamp, error) {
+	stamps := make(map[string]stamp)
+	err := filepath.Walk(x.Dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := fi.Name()
+		if fi.IsDir() {
+			if path != x.Dir && (name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(x.Dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		inVendor := rel == "vendor" || strings.HasPrefix(rel, "vendor/") || strings.Contains(rel, "/vendor/")
+		switch {
+		case isModuleFile(name) && (name != "modules.txt" || inVendor):
+			s := stamps[rel]
+			s.add(fi)
+			stamps[rel] = s
+		case strings.HasSuffix(name, ".go") && !inVendor:
+			dir := "./" + filepath.ToSlash(filepath.Dir(rel))
+			if dir == "./." {
+				dir = "."
+			}
+			s := stamps[dir]
+			s.add(fi)
+			stamps[dir] = s
+		}
+		return nil
+	})
+	return stamps, err
+}
+
+// Start lists and loads the whole tree. Dir is made absolute.
+func (x *Indexer) Start() error {
+	dir, err := filepath.Abs(x.Dir)
+	if err != nil {
+		return err
+	}
+	x.Dir = dir
+	stamps, err := x.scan()
+	if err != nil {
+		return err
+	}
+	out, err := x.List(nil)
+	if err != nil {
+		return err
+	}
+	g, err := x.Load(strings.NewReader(out))
+	if err != nil {
+		return err
+	}
+	// the deps of the packages importing a changed one change too, so
+	// they are computed from the imports
+	g.IgnoreDeps(true)
+	x.live, x.stamps = g, stamps
+	x.publish()
+	return nil
+}
+
+func (x *Indexer) publish() {
+	frozen := x.live.Freeze()
+	x.mu.Lock()
+	x.frozen = frozen
+	x.mu.Unlock()
+}
+
+// Graph returns the graph as of the last update, safe for concurrent use.
+func (x *Indexer) Graph() *depgraph.DepGraph {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.frozen
+}
+
+// Update lists again the packages of the directories changed since the
+// last update, or everything if a module file changed, and returns those
+// directories, sorted. Packages whose directory has no Go file any more
+// are removed. On failure, eg: go list failing, the graph is left as it
+// was and the changes are retried on the next Update.
+func (x *Indexer) Update() ([]string, error) {
+	stamps, err := x.scan()
+	if err != nil {
+		return nil, err
+	}
+	var changed, gone []string
+	full := false // a module file changed, they are the paths not starting with .
+	for path, s := range stamps {
+		if old, ok := x.stamps[path]; !ok || old != s {
+			changed = append(changed, path)
+			full = full || !strings.HasPrefix(path, ".")
+		}
+	}
+	for path := range x.stamps {
+		if _, ok := stamps[path]; !ok {
+			gone = append(gone, path)
+			full = full || !strings.HasPrefix(path, ".")
+		}
+	}
+	if len(changed) == 0 && len(gone) == 0 {
+		return nil, nil
+	}
+	if full {
+		if err := x.Start(); err != nil {
+			return nil, err
+		}
+		return sorted(append(changed, gone...)), nil
+	}
+	var out string
+	if len(changed) > 0 {
+		if out, err = x.List(changed); err != nil {
+			return nil, err
+		}
+	}
+	listed := make(map[string]bool, len(changed))
+	for _, dir := range changed {
+		listed[filepath.Join(x.Dir, dir)] = true
+	}
+	dec := json.NewDecoder(strings.NewReader(out))
+	var records []depgraph.DepInfo
+	for dec.More() {
+		var d depgraph.DepInfo
+		if err := dec.Decode(&d); err != nil {
+			return nil, err
+		}
+		// deps already known, eg: the standard library, are kept
+		if listed[filepath.Clean(d.Dir)] || !x.live.Exists(d.ImportPath) {
+			records = append(records, d)
+		}
+	}
+	for _, dir := range gone {
+		for _, p := range x.live.PackagesAt(filepath.Join(x.Dir, dir)) {
+			x.live.Remove(p)
+		}
+	}
+	for _, d := range records {
+		// replaced, not listed twice, see DepGraph.Conflicts
+		x.live.Remove(d.ImportPath)
+		x.live.Add(d)
+	}
+	x.stamps = stamps
+	x.publish()
+	return sorted(append(changed, gone...)), nil
+}
+
+// Run updates the graph every Interval until done is closed. Failures are
+// logged and retried.
+func (x *Indexer) Run(done <-chan struct{}) {
+	ticker := time.NewTicker(x.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		start := time.Now()
+		changed, err := x.Update()
+		if err != nil {
+			log.Println("update failed", err)
+			continue
+		}
+		if len(changed) > 0 {
+			log.Printf("updated %d directories in %v", len(changed), time.Since(start).Round(time.Millisecond))
+		}
+	}
+}
+
+// ServeListener serves s like ServeJSONRPC on every connection accepted
+// on l, eg: a unix socket, until l is closed.
+func ServeListener(s *Service, l net.Listener) error {
+	srv, err := newRPCServer(s)
+	if err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
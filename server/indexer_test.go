@@ -0,0 +1,120 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func TestIndexer(t *testing.T) {
+	root := t.TempDir()
+	write := func(file, content string) {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/app\n")
+	write("cmd/api/main.go", "package main")
+	write("auth/auth.go", "package auth")
+	// go list output per directory, as the files say
+	records := map[string]depgraph.DepInfo{
+		"./cmd/api": {ImportPath: "example.com/app/cmd/api", Name: "main", Dir: filepath.Join(root, "cmd/api"), Imports: []string{"example.com/app/auth"}},
+		"./auth":    {ImportPath: "example.com/app/auth", Name: "auth", Dir: filepath.Join(root, "auth")},
+	}
+	var listed [][]string
+	x := &Indexer{
+		Dir: root,
+		List: func(dirs []string) (string, error) {
+			listed = append(listed, dirs)
+			if dirs == nil {
+				dirs = []string{"./auth", "./cmd/api", "./jwt"}
+			}
+			var b strings.Builder
+			enc := json.NewEncoder(&b)
+			for _, dir := range dirs {
+				if d, ok := records[dir]; ok {
+					enc.Encode(d)
+				}
+			}
+			return b.String(), nil
+		},
+		Load: func(r io.Reader) (*depgraph.DepGraph, error) { return depgraph.LoadDeps(r) },
+	}
+	if err := x.Start(); err != nil {
+		t.Fatal(err)
+	}
+	before := x.Graph()
+	if mains := before.SearchMain("example.com/app/auth"); len(mains) != 1 {
+		t.Fatal(mains)
+	}
+	if changed, err := x.Update(); err != nil || changed != nil {
+		t.Error("nothing changed", changed, err)
+	}
+
+	// auth starts importing a new package
+	write("jwt/jwt.go", "package jwt // new")
+	write("auth/auth.go", "package auth // imports jwt")
+	records["./jwt"] = depgraph.DepInfo{ImportPath: "example.com/app/jwt", Name: "jwt", Dir: filepath.Join(root, "jwt")}
+	records["./auth"] = depgraph.DepInfo{ImportPath: "example.com/app/auth", Name: "auth", Dir: filepath.Join(root, "auth"),
+		Imports: []string{"example.com/app/jwt"}}
+	changed, err := x.Update()
+	if err != nil || !reflect.DeepEqual(changed, []string{"./auth", "./jwt"}) {
+		t.Fatal(changed, err)
+	}
+	if last := listed[len(listed)-1]; !reflect.DeepEqual(last, []string{"./auth", "./jwt"}) {
+		t.Error("listed", last)
+	}
+	if mains := x.Graph().SearchMain("example.com/app/jwt"); len(mains) != 1 || len(x.Graph().Conflicts()) != 0 {
+		t.Error("updated", mains, x.Graph().Conflicts())
+	}
+	if before.Exists("example.com/app/jwt") {
+		t.Error("the previous graph changed")
+	}
+
+	// a directory removed
+	if err := os.RemoveAll(filepath.Join(root, "jwt")); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := x.Update(); err != nil || !reflect.DeepEqual(changed, []string{"./jwt"}) || x.Graph().Exists("example.com/app/jwt") {
+		t.Error("removed", changed, err)
+	}
+
+	// go.mod reloads everything
+	write("go.mod", "module example.com/app\n\ngo 1.16\n")
+	n := len(listed)
+	if _, err := x.Update(); err != nil || len(listed) != n+1 || listed[n] != nil {
+		t.Error("full reload", listed[n:], err)
+	}
+
+	// served over a socket
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go ServeListener(NewLiveService(x.Graph), l)
+	conn, err := net.DialTimeout("tcp", l.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := jsonrpc.NewClient(conn)
+	defer client.Close()
+	var mains []string
+	if err := client.Call("Graph.SearchMain", PackageArgs{Package: "example.com/app/auth"}, &mains); err != nil ||
+		!reflect.DeepEqual(mains, []string{"example.com/app/cmd/api"}) {
+		t.Error(mains, err)
+	}
+}
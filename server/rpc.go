@@ -0,0 +1,173 @@
+// Package server answers graph queries for long running clients, such as
+// editor extensions, so they don't spawn the CLI for every lookup.
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sort"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Service exposes the queries of a DepGraph as net/rpc methods, eg:
+// {"method": "Graph.Importers", "params": [{"Package": "net/url"}], "id": 1}
+type Service struct {
+	graph func() *depgraph.DepGraph
+	// Watchlist, if set, answers SearchMain and SearchChain for the
+	// packages it watches.
+	Watchlist *Watchlist
+}
+
+func NewService(g *depgraph.DepGraph) *Service {
+	return &Service{graph: func() *depgraph.DepGraph { return g }}
+}
+
+// NewLiveService returns a Service answering every request from the graph
+// graph returns then, eg: Indexer.Graph, which must be safe for
+// concurrent use.
+func NewLiveService(graph func() *depgraph.DepGraph) *Service {
+	return &Service{graph: graph}
+}
+
+type PackageArgs struct {
+	Package string
+}
+
+type GraphArgs struct {
+	From, To string
+}
+
+type NoArgs struct{}
+
+func sorted(packages []string) []string {
+	if packages == nil {
+		return []string{}
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// Exists reports whether the package was loaded.
+func (s *Service) Exists(args PackageArgs, reply *bool) error {
+	*reply = s.graph().Exists(args.Package)
+	return nil
+}
+
+// Imports returns the packages args.Package imports directly.
+func (s *Service) Imports(args PackageArgs, reply *[]string) error {
+	*reply = sorted(s.graph().Imports(args.Package))
+	return nil
+}
+
+// Importers returns the packages importing args.Package directly.
+func (s *Service) Importers(args PackageArgs, reply *[]string) error {
+	*reply = sorted(s.graph().Importers(args.Package))
+	return nil
+}
+
+// SearchAll returns every package depending on args.Package.
+func (s *Service) SearchAll(args PackageArgs, reply *[]string) error {
+	*reply = sorted(s.graph().SearchAll(args.Package))
+	return nil
+}
+
+// SearchMain returns the main packages depending on args.Package.
+func (s *Service) SearchMain(args PackageArgs, reply *[]string) error {
+	if chains, ok := s.Watchlist.Chains(args.Package); ok {
+		mains := make([]string, len(chains)) // sorted like the chains
+		for i, chain := range chains {
+			mains[i] = chain[1]
+		}
+		*reply = mains
+		return nil
+	}
+	*reply = sorted(s.graph().SearchMain(args.Package))
+	return nil
+}
+
+// SearchChain returns one chain main -> ... -> args.Package per main.
+func (s *Service) SearchChain(args PackageArgs, reply *[][]string) error {
+	chains, ok := s.Watchlist.Chains(args.Package)
+	if !ok {
+		chains = s.graph().SearchChain(args.Package)
+		sort.Slice(chains, func(i, j int) bool { return chains[i][1] < chains[j][1] })
+	}
+	if chains == nil {
+		chains = [][]string{}
+	}
+	*reply = chains
+	return nil
+}
+
+// Watched returns the packages of the Watchlist, sorted.
+func (s *Service) Watched(args NoArgs, reply *[]string) error {
+	*reply = sorted(append([]string(nil), s.Watchlist.Packages()...))
+	return nil
+}
+
+// SearchChains is SearchChain with structured chains, see depgraph.Chain.
+func (s *Service) SearchChains(args PackageArgs, reply *[]depgraph.Chain) error {
+	*reply = s.graph().SearchChains(args.Package)
+	return nil
+}
+
+// SearchGraph returns the edges on the paths from args.From to args.To.
+func (s *Service) SearchGraph(args GraphArgs, reply *map[string][]string) error {
+	result := s.graph().SearchGraph(args.From, args.To)
+	if result == nil {
+		result = map[string][]string{}
+	}
+	*reply = result
+	return nil
+}
+
+type stdio struct {
+	io.Reader
+	io.Writer
+}
+
+func (stdio) Close() error { return nil }
+
+func newRPCServer(s *Service) (*rpc.Server, error) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("Graph", s); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// ServeJSONRPC serves s as the "Graph" service, speaking JSON-RPC 1.0
+// (one JSON object per request) over r and w until r is exhausted.
+func ServeJSONRPC(s *Service, r io.Reader, w io.Writer) error {
+	srv, err := newRPCServer(s)
+	if err != nil {
+		return err
+	}
+	srv.ServeCodec(jsonrpc.NewServerCodec(stdio{r, w}))
+	return nil
+}
+
+// Handler answers the JSON-RPC requests of ServeJSONRPC one at a time, for
+// clients without a stream to serve, eg: the JavaScript of a web page
+// calling the WebAssembly build in wasm/.
+type Handler struct {
+	srv *rpc.Server
+}
+
+func NewHandler(s *Service) (*Handler, error) {
+	srv, err := newRPCServer(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{srv: srv}, nil
+}
+
+// Handle returns the JSON-RPC response to request.
+func (h *Handler) Handle(request []byte) []byte {
+	var out bytes.Buffer
+	h.srv.ServeRequest(jsonrpc.NewServerCodec(stdio{bytes.NewReader(request), &out}))
+	return out.Bytes()
+}
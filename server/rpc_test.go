@@ -0,0 +1,81 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func TestJSONRPC(t *testing.T) {
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"lib"}, Deps: []string{"lib", "fmt"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "lib", Name: "lib", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "fmt", Name: "fmt"})
+	requests := `{"method": "Graph.Importers", "params": [{"Package": "fmt"}], "id": 1}
+{"method": "Graph.SearchChain", "params": [{"Package": "fmt"}], "id": 2}
+{"method": "Graph.Exists", "params": [{"Package": "nope"}], "id": 3}
+{"method": "Graph.Nope", "params": [{}], "id": 4}
+{"method": "Graph.SearchChains", "params": [{"Package": "fmt"}], "id": 5}
+`
+	var out bytes.Buffer
+	if err := ServeJSONRPC(NewService(dg), strings.NewReader(requests), &out); err != nil {
+		t.Fatal(err)
+	}
+	dec := json.NewDecoder(&out)
+	type reply struct {
+		ID     int
+		Result interface{}
+		Error  interface{}
+	}
+	// requests are served concurrently, so replies may come out of order
+	var replies []reply
+	for dec.More() {
+		var r reply
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		replies = append(replies, r)
+	}
+	sort.Slice(replies, func(i, j int) bool { return replies[i].ID < replies[j].ID })
+	if len(replies) != 5 {
+		t.Fatal("expect 5 replies, real:", len(replies))
+	}
+	got, _ := json.Marshal(replies[0].Result)
+	if string(got) != `["lib"]` {
+		t.Error(string(got))
+	}
+	got, _ = json.Marshal(replies[1].Result)
+	if string(got) != `[["main","cmd/a","lib","fmt"]]` {
+		t.Error(string(got))
+	}
+	if replies[2].Result != false || replies[3].Error == nil {
+		t.Error("result error", replies[2], replies[3])
+	}
+	got, _ = json.Marshal(replies[4].Result)
+	if !strings.Contains(string(got), `"Main":"cmd/a"`) || !strings.Contains(string(got), `"Packages":["cmd/a","lib","fmt"]`) ||
+		!strings.Contains(string(got), `"Complete":true`) {
+		t.Error(string(got))
+	}
+}
+
+func TestHandler(t *testing.T) {
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"fmt"}, Deps: []string{"fmt"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "fmt", Name: "fmt"})
+	h, err := NewHandler(NewService(dg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for request, expect := range map[string]string{
+		`{"method": "Graph.SearchMain", "params": [{"Package": "fmt"}], "id": 1}`: `{"id":1,"result":["cmd/a"],"error":null}`,
+		`{"method": "Graph.Nope", "params": [{}], "id": 2}`:                       `{"id":2,"result":null,"error":"rpc: can't find method Graph.Nope"}`,
+	} {
+		if got := strings.TrimSpace(string(h.Handle([]byte(request)))); got != expect {
+			t.Error(got)
+		}
+	}
+}
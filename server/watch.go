@@ -0,0 +1,106 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Change is a difference in the answer of a standing query: the main
+// packages depending on Package.
+type Change struct {
+	Package string
+	Added   []string // main packages that started depending on Package
+	Removed []string // main packages that no longer depend on it
+}
+
+func (c Change) String() string {
+	var parts []string
+	if len(c.Added) > 0 {
+		parts = append(parts, "now used by "+strings.Join(c.Added, ", "))
+	}
+	if len(c.Removed) > 0 {
+		parts = append(parts, "no longer used by "+strings.Join(c.Removed, ", "))
+	}
+	return fmt.Sprintf("%s: %s", c.Package, strings.Join(parts, "; "))
+}
+
+// Diff compares the main packages depending on each of packages in old and
+// new, packages whose answer didn't change are left out.
+func Diff(old, new *depgraph.DepGraph, packages []string) (changes []Change) {
+	for _, pkg := range packages {
+		before, after := set(old.SearchMain(pkg)), set(new.SearchMain(pkg))
+		c := Change{Package: pkg, Added: missing(after, before), Removed: missing(before, after)}
+		if len(c.Added) > 0 || len(c.Removed) > 0 {
+			changes = append(changes, c)
+		}
+	}
+	return
+}
+
+func set(packages []string) map[string]bool {
+	s := make(map[string]bool, len(packages))
+	for _, p := range packages {
+		s[p] = true
+	}
+	return s
+}
+
+// missing returns the sorted elements of a not in b.
+func missing(a, b map[string]bool) (result []string) {
+	for p := range a {
+		if !b[p] {
+			result = append(result, p)
+		}
+	}
+	sort.Strings(result)
+	return
+}
+
+// Watcher reloads the go list output in Path whenever it changes and
+// reports the changes of the standing queries on Packages.
+type Watcher struct {
+	Path     string
+	Interval time.Duration
+	Packages []string
+	Load     func() (*depgraph.DepGraph, error)
+	Notify   func([]Change) error
+}
+
+// Run polls Path every Interval until done is closed, g is the graph
+// currently loaded from it. Failures to load or notify are logged and
+// retried on the next poll; the changes are compared against the graph of
+// the last successful notification, so none is lost.
+func (w *Watcher) Run(g *depgraph.DepGraph, done <-chan struct{}) {
+	last, _ := os.Stat(w.Path)
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		fi, err := os.Stat(w.Path)
+		if err != nil || (last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size()) {
+			continue
+		}
+		newGraph, err := w.Load()
+		if err != nil {
+			log.Println("reload failed", err)
+			continue
+		}
+		if changes := Diff(g, newGraph, w.Packages); len(changes) > 0 {
+			if err := w.Notify(changes); err != nil {
+				log.Println("notify failed", err)
+				continue
+			}
+		}
+		last, g = fi, newGraph
+	}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func mainsUsing(lib string, mains ...string) *depgraph.DepGraph {
+	dg := &depgraph.DepGraph{}
+	for _, m := range mains {
+		dg.Add(depgraph.DepInfo{ImportPath: m, Name: "main", Imports: []string{lib}, Deps: []string{lib}})
+	}
+	dg.Add(depgraph.DepInfo{ImportPath: lib, Name: "lib"})
+	return dg
+}
+
+func TestDiff(t *testing.T) {
+	changes := Diff(mainsUsing("lib", "cmd/a", "cmd/b"), mainsUsing("lib", "cmd/b", "cmd/c"), []string{"lib", "nope"})
+	expect := []Change{{Package: "lib", Added: []string{"cmd/c"}, Removed: []string{"cmd/a"}}}
+	if !reflect.DeepEqual(changes, expect) {
+		t.Fatal(changes)
+	}
+	if s := changes[0].String(); s != "lib: now used by cmd/c; no longer used by cmd/a" {
+		t.Error(s)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	var payload map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+	}))
+	defer srv.Close()
+	err := NewWebhook(srv.URL).Notify([]Change{{Package: "lib", Added: []string{"cmd/a"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload["text"] != "lib: now used by cmd/a" {
+		t.Error(payload)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deps.json")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notified := make(chan []Change, 1)
+	w := &Watcher{
+		Path:     path,
+		Interval: time.Millisecond,
+		Packages: []string{"lib"},
+		Load:     func() (*depgraph.DepGraph, error) { return mainsUsing("lib", "cmd/a", "cmd/b"), nil },
+		Notify:   func(c []Change) error { notified <- c; return nil },
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go w.Run(mainsUsing("lib", "cmd/a"), done)
+	// Run may not have looked at the file yet, keep growing it until the
+	// change is noticed
+	timeout := time.After(5 * time.Second)
+	for content := "new"; ; content += "!" {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case c := <-notified:
+			if len(c) != 1 || !reflect.DeepEqual(c[0].Added, []string{"cmd/b"}) {
+				t.Error(c)
+			}
+			return
+		case <-timeout:
+			t.Fatal("no notification")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestWatcherNotifyFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deps.json")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	notified := make(chan []Change, 1)
+	calls := 0
+	w := &Watcher{
+		Path:     path,
+		Interval: time.Millisecond,
+		Packages: []string{"lib"},
+		Load:     func() (*depgraph.DepGraph, error) { return mainsUsing("lib", "cmd/a", "cmd/b"), nil },
+		Notify: func(c []Change) error {
+			if calls++; calls == 1 {
+				return errors.New("webhook down")
+			}
+			notified <- c
+			return nil
+		},
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go w.Run(mainsUsing("lib", "cmd/a"), done)
+	timeout := time.After(5 * time.Second)
+	for content := "new"; ; content += "!" {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case c := <-notified:
+			// the failed notification is sent again
+			if len(c) != 1 || !reflect.DeepEqual(c[0].Added, []string{"cmd/b"}) {
+				t.Error(c)
+			}
+			return
+		case <-timeout:
+			t.Fatal("no notification")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
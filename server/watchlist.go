@@ -0,0 +1,56 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/rules"
+)
+
+// Watchlist holds the chains from the main packages to sensitive
+// packages, eg: crypto or the packages of modules with risky licenses,
+// computed once when the server starts, so the Service and the Web view
+// answer exposure questions about them without walking the graph.
+type Watchlist struct {
+	packages []string              // watched packages, sorted
+	chains   map[string][][]string // watched package -> chains, sorted by main
+}
+
+// NewWatchlist computes the chains to the packages of g matching patterns,
+// see rules.Match, spread over workers goroutines, see
+// depgraph.SearchChainBulk. g must not change afterwards.
+func NewWatchlist(g *depgraph.DepGraph, patterns []string, workers int) *Watchlist {
+	w := &Watchlist{}
+	for _, p := range g.Packages() {
+		for _, pattern := range patterns {
+			if rules.Match(pattern, p) {
+				w.packages = append(w.packages, p)
+				break
+			}
+		}
+	}
+	sort.Strings(w.packages)
+	w.chains = g.SearchChainBulk(w.packages, workers)
+	return w
+}
+
+// Packages returns the watched packages, sorted.
+func (w *Watchlist) Packages() []string {
+	if w == nil {
+		return nil
+	}
+	return w.packages
+}
+
+// Chains returns the chains main -> ... -> pkg, sorted by main package,
+// and whether pkg is watched. A nil Watchlist watches nothing.
+func (w *Watchlist) Chains(pkg string) ([][]string, bool) {
+	if w == nil {
+		return nil, false
+	}
+	i := sort.SearchStrings(w.packages, pkg)
+	if i == len(w.packages) || w.packages[i] != pkg {
+		return nil, false
+	}
+	return w.chains[pkg], true
+}
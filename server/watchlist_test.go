@@ -0,0 +1,48 @@
+package server
+
+import (
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWatchlist(t *testing.T) {
+	dg := webGraph()
+	w := NewWatchlist(dg, []string{"x/...", "io"}, 2)
+	if !reflect.DeepEqual(w.Packages(), []string{"io", "x/lib"}) {
+		t.Error(w.Packages())
+	}
+	chains, ok := w.Chains("x/lib")
+	if !ok || !reflect.DeepEqual(chains, [][]string{{"main", "cmd/a", "x/lib"}, {"main", "cmd/b", "x/lib"}}) {
+		t.Error(chains)
+	}
+	if _, ok := w.Chains("fmt"); ok {
+		t.Error("fmt is not watched")
+	}
+	var nilList *Watchlist
+	if _, ok := nilList.Chains("x/lib"); ok || nilList.Packages() != nil {
+		t.Error("nil Watchlist should watch nothing")
+	}
+
+	s := NewService(dg)
+	s.Watchlist = w
+	var mains, watched []string
+	if err := s.SearchMain(PackageArgs{"x/lib"}, &mains); err != nil || !reflect.DeepEqual(mains, []string{"cmd/a", "cmd/b"}) {
+		t.Error(mains, err)
+	}
+	if err := s.Watched(NoArgs{}, &watched); err != nil || len(watched) != 2 {
+		t.Error(watched, err)
+	}
+
+	web := NewWeb(dg, "x/lib")
+	web.Watchlist = w
+	rec := httptest.NewRecorder()
+	web.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	body, _ := io.ReadAll(rec.Result().Body)
+	if !strings.Contains(string(body), "2 main packages exposed to x/lib") ||
+		!strings.Contains(string(body), "<li>main -&gt; cmd/b -&gt; x/lib</li>") {
+		t.Error(string(body))
+	}
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"html/template"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+const (
+	maxRowNodes  = 8  // nodes per row before wrapping
+	maxSideNodes = 40 // importers or imports shown, heaviest first
+	cellWidth    = 180
+	rowHeight    = 110
+	minRadius    = 6
+	maxRadius    = 30
+)
+
+// Web serves a page modeled on the graph view of go tool pprof -http: the
+// focused package in the center, its importers above and its imports
+// below, each node sized by the source it transitively brings in, or by
+// its number of deps if the dump has no GoFiles. Clicking a node
+// refocuses the page on it. Packages of the Watchlist, if set, also list
+// the chains from the main packages exposed to them.
+type Web struct {
+	g         *depgraph.DepGraph
+	Focus     string // package shown when the request names none
+	Watchlist *Watchlist
+}
+
+func NewWeb(g *depgraph.DepGraph, focus string) *Web {
+	return &Web{g: g, Focus: focus}
+}
+
+type webNode struct {
+	Name   string
+	Label  string
+	Weight int
+	Unit   string
+	X, Y   float64
+	R      float64
+	Focus  bool
+}
+
+func (n webNode) URL() string {
+	return "?focus=" + url.QueryEscape(n.Name)
+}
+
+type webEdge struct {
+	X1, Y1, X2, Y2 float64
+}
+
+type webPage struct {
+	Focus         string
+	Found         bool
+	Width, Height float64
+	Nodes         []webNode
+	Edges         []webEdge
+	Hidden        int // importers and imports left out
+	Watched       bool
+	Exposure      []string // chains to Focus if Watched
+}
+
+func (w *Web) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	focus := r.URL.Query().Get("focus")
+	if focus == "" {
+		focus = w.Focus
+	}
+	page := layout(w.g, focus)
+	if chains, ok := w.Watchlist.Chains(focus); ok {
+		page.Watched = true
+		for _, chain := range chains {
+			page.Exposure = append(page.Exposure, strings.Join(chain, " -> "))
+		}
+	}
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := webTemplate.Execute(rw, page); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// layout places the importers of focus in rows above it and its imports
+// in rows below it.
+func layout(g *depgraph.DepGraph, focus string) *webPage {
+	page := &webPage{Focus: focus, Found: g.Exists(focus)}
+	if !page.Found {
+		return page
+	}
+	importers, hiddenImporters := heaviest(g, g.Importers(focus))
+	imports, hiddenImports := heaviest(g, g.Imports(focus))
+	page.Hidden = hiddenImporters + hiddenImports
+	center := []webNode{weighted(g, focus)}
+	center[0].Focus = true
+
+	above, below := rows(len(importers)), rows(len(imports))
+	widest := math.Max(math.Min(float64(len(importers)), maxRowNodes), math.Min(float64(len(imports)), maxRowNodes))
+	page.Width = math.Max(widest, 1) * cellWidth
+	page.Height = float64(above+1+below) * rowHeight
+
+	maxWeight := 1
+	for _, nodes := range [][]webNode{importers, center, imports} {
+		for _, n := range nodes {
+			if n.Weight > maxWeight {
+				maxWeight = n.Weight
+			}
+		}
+	}
+	place := func(nodes []webNode, firstRow int) {
+		for i := range nodes {
+			row, col := i/maxRowNodes, i%maxRowNodes
+			inRow := len(nodes) - row*maxRowNodes
+			if inRow > maxRowNodes {
+				inRow = maxRowNodes
+			}
+			n := &nodes[i]
+			n.X = page.Width/2 + (float64(col)-float64(inRow-1)/2)*cellWidth
+			n.Y = (float64(firstRow+row) + 0.5) * rowHeight
+			n.R = minRadius + (maxRadius-minRadius)*math.Sqrt(float64(n.Weight)/float64(maxWeight))
+			n.Label = shortName(n.Name)
+		}
+	}
+	place(importers, 0)
+	place(center, above)
+	place(imports, above+1)
+
+	c := center[0]
+	for _, n := range importers {
+		page.Edges = append(page.Edges, webEdge{n.X, n.Y + n.R, c.X, c.Y - c.R})
+	}
+	for _, n := range imports {
+		page.Edges = append(page.Edges, webEdge{c.X, c.Y + c.R, n.X, n.Y - n.R})
+	}
+	page.Nodes = append(append(append(page.Nodes, importers...), center...), imports...)
+	return page
+}
+
+// heaviest returns nodes for the maxSideNodes packages with the most
+// transitive deps, and how many packages were left out.
+func heaviest(g *depgraph.DepGraph, packages []string) ([]webNode, int) {
+	nodes := make([]webNode, len(packages))
+	for i, p := range packages {
+		nodes[i] = weighted(g, p)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Weight > nodes[j].Weight })
+	if len(nodes) <= maxSideNodes {
+		return nodes, 0
+	}
+	return nodes[:maxSideNodes], len(nodes) - maxSideNodes
+}
+
+// weighted returns the node of pkg weighing the source it brings in, or
+// the number of its deps if the dump has no source sizes.
+func weighted(g *depgraph.DepGraph, pkg string) webNode {
+	if s := g.TransitiveSize(pkg); s.Weight() > 0 {
+		return webNode{Name: pkg, Weight: s.Weight(), Unit: s.Unit()}
+	}
+	return webNode{Name: pkg, Weight: len(g.Deps(pkg)), Unit: "deps"}
+}
+
+func rows(n int) int {
+	return (n + maxRowNodes - 1) / maxRowNodes
+}
+
+// shortName keeps the last two elements of an import path so labels fit
+// in a cell, the full path is in the node's tooltip.
+func shortName(pkg string) string {
+	dir, base := path.Split(pkg)
+	if dir == "" {
+		return base
+	}
+	return path.Base(dir) + "/" + base
+}
+
+var webTemplate = template.Must(template.New("web").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Focus}} - go_dep_search</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+circle { fill: #f8e0d0; stroke: #b04020; }
+circle.focus { fill: #e06040; }
+line { stroke: #999; }
+text { font-size: 11px; text-anchor: middle; }
+a:hover circle { fill: #f0b090; }
+</style>
+</head>
+<body>
+<form>
+<input name="focus" size="60" value="{{.Focus}}">
+<input type="submit" value="Focus">
+</form>
+{{if not .Found}}
+<p>package {{.Focus}} not found</p>
+{{else}}
+{{if .Hidden}}<p>{{.Hidden}} lighter importers and imports not shown</p>{{end}}
+<svg width="{{.Width}}" height="{{.Height}}">
+{{range .Edges}}<line x1="{{.X1}}" y1="{{.Y1}}" x2="{{.X2}}" y2="{{.Y2}}"/>
+{{end}}
+{{range .Nodes}}<a href="{{.URL}}">
+<title>{{.Name}} ({{.Weight}} {{.Unit}})</title>
+<circle cx="{{.X}}" cy="{{.Y}}" r="{{.R}}"{{if .Focus}} class="focus"{{end}}/>
+<text x="{{.X}}" y="{{.Y}}" dy="{{.R}}" transform="translate(0 14)">{{.Label}}</text>
+</a>
+{{end}}
+</svg>
+{{if .Watched}}
+<h3>{{len .Exposure}} main packages exposed to {{.Focus}}</h3>
+<ul>
+{{range .Exposure}}<li>{{.}}</li>
+{{end}}
+</ul>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
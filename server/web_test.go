@@ -0,0 +1,56 @@
+package server
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func webGraph() *depgraph.DepGraph {
+	dg := &depgraph.DepGraph{}
+	dg.Add(depgraph.DepInfo{ImportPath: "cmd/a", Name: "main", Imports: []string{"x/lib"}, Deps: []string{"x/lib", "fmt", "io"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "cmd/b", Name: "main", Imports: []string{"x/lib"}, Deps: []string{"x/lib", "fmt", "io"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "x/lib", Name: "lib", Imports: []string{"fmt"}, Deps: []string{"fmt", "io"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "fmt", Name: "fmt", Imports: []string{"io"}, Deps: []string{"io"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "io", Name: "io"})
+	return dg
+}
+
+func TestLayout(t *testing.T) {
+	page := layout(webGraph(), "x/lib")
+	if !page.Found || len(page.Nodes) != 4 || len(page.Edges) != 3 {
+		t.Fatal(page)
+	}
+	byName := make(map[string]webNode)
+	for _, n := range page.Nodes {
+		byName[n.Name] = n
+	}
+	a, lib, f := byName["cmd/a"], byName["x/lib"], byName["fmt"]
+	if !(a.Y < lib.Y && lib.Y < f.Y) || !lib.Focus {
+		t.Error("importers above, imports below", page.Nodes)
+	}
+	if !(a.R > lib.R && lib.R > f.R) {
+		t.Error("radius follows transitive weight", page.Nodes)
+	}
+	if a.URL() != "?focus=cmd%2Fa" {
+		t.Error(a.URL())
+	}
+	if layout(webGraph(), "nope").Found {
+		t.Error("nope found")
+	}
+}
+
+func TestWeb(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewWeb(webGraph(), "fmt").ServeHTTP(rec, httptest.NewRequest("GET", "/?focus=x%2Flib", nil))
+	body, _ := io.ReadAll(rec.Result().Body)
+	if !strings.Contains(string(body), `href="?focus=cmd%2Fa"`) {
+		t.Error(string(body))
+	}
+	if !strings.Contains(string(body), "<title>x/lib - go_dep_search</title>") {
+		t.Error("focus from query not used")
+	}
+}
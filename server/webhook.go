@@ -0,0 +1,65 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Webhook posts Changes to an incoming webhook accepting Slack's
+// {"text": "..."} payload.
+type Webhook struct {
+	HTTP *http.Client
+	URL  string
+}
+
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		HTTP: &http.Client{Timeout: 30 * time.Second},
+		URL:  url,
+	}
+}
+
+func (h *Webhook) Notify(changes []Change) error {
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = c.String()
+	}
+	body, err := json.Marshal(map[string]string{"text": strings.Join(lines, "\n")})
+	if err != nil {
+		return err
+	}
+	return h.post(body)
+}
+
+// NotifyEvents posts events as lines of text, for Slack, along with the
+// events themselves for other consumers.
+func (h *Webhook) NotifyEvents(events []Event) error {
+	lines := make([]string, len(events))
+	for i, e := range events {
+		lines[i] = e.String()
+	}
+	body, err := json.Marshal(struct {
+		Text   string  `json:"text"`
+		Events []Event `json:"events"`
+	}{strings.Join(lines, "\n"), events})
+	if err != nil {
+		return err
+	}
+	return h.post(body)
+}
+
+func (h *Webhook) post(body []byte) error {
+	resp, err := h.HTTP.Post(h.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook %s: %s", h.URL, resp.Status)
+	}
+	return nil
+}
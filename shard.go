@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// searchSharded answers the plain and -main searches of the args from a
+// ShardedGraph loaded with the -shard partition, for dumps too large to
+// load whole.
+func searchSharded() {
+	input := openInput()
+	defer input.Close()
+	shard := depgraph.ShardByModule
+	if *shardBy != "module" {
+		shard = depgraph.ShardByPrefix(strings.Split(*shardBy, ","))
+	}
+	s, err := depgraph.LoadSharded(input, shard)
+	if err != nil {
+		fail(exitParse, "LoadSharded failed %v", err)
+	}
+	log.Printf("successfully load %d packages in %d shards", s.CountAll(), len(s.Shards()))
+	for _, dep := range flag.Args() {
+		if !s.Has(dep) {
+			warn(exitNotFound, "%v not found", dep)
+			continue
+		}
+		if *onlyMain {
+			for _, p := range s.SearchMain(dep) {
+				deps := []string{"main", p}
+				if p != dep {
+					deps = append(deps, dep)
+				}
+				fmt.Println(strings.Join(deps, " -> "))
+			}
+			continue
+		}
+		for _, p := range s.SearchAll(dep) {
+			fmt.Println(p + " -> " + dep)
+		}
+	}
+}
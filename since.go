@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// git runs git with args in dir and returns its trimmed output.
+func git(dir string, args ...string) (string, error) {
+	return run(dir, nil, "git", args...)
+}
+
+// loadRevision loads the graph of the working directory at revision rev:
+// it checks rev out in a temporary worktree and runs listPackages in the
+// same directory of it, with the build profile of the flags.
+func loadRevision(rev string) (*depgraph.DepGraph, error) {
+	prefix, err := git(".", "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := ioutil.TempDir("", "go_dep_search")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	worktree := filepath.Join(tmp, "worktree")
+	if _, err := git(".", "worktree", "add", "--detach", worktree, rev); err != nil {
+		return nil, err
+	}
+	defer git(".", "worktree", "remove", "--force", worktree)
+	out, err := listPackages(filepath.Join(worktree, prefix), buildProfile())
+	if err != nil {
+		return nil, fmt.Errorf("go list at %v: %v", rev, err)
+	}
+	dg, err := newGraph(buildProfile())
+	if err != nil {
+		return nil, err
+	}
+	if err := dg.Load(strings.NewReader(out)); err != nil {
+		return nil, err
+	}
+	return prepareGraph(dg), nil
+}
+
+// reportSince prints the packages the input adds to and removes from the
+// graph of the working directory at *since.
+func reportSince(dg *depgraph.DepGraph) {
+	old, err := loadRevision(*since)
+	if err != nil {
+		fail(exitParse, "load %v failed %v", *since, err)
+	}
+	printDiff(old, dg)
+}
+
+// printDiff prints the packages dg adds to and removes from old, each
+// added package with a chain bringing it in.
+func printDiff(old, dg *depgraph.DepGraph) {
+	added, removed := depgraph.DiffPackages(old, dg)
+	for _, p := range added {
+		if !showPackage(dg, p) {
+			continue
+		}
+		line, chains := "+ "+p, dg.SearchChain(p)
+		var chain []string
+		if len(chains) > 0 {
+			chain = chains[0]
+			line += ": " + formatChain(dg, chain)
+		}
+		printPackage(dg, p, chain, line)
+	}
+	for _, p := range removed {
+		if showPackage(old, p) {
+			printPackage(old, p, nil, "- "+p)
+		}
+	}
+}
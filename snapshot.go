@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/snapshot"
+)
+
+// snapshotInput holds a copy of the input read so far, for -save.
+var snapshotInput *os.File
+
+func openStore() *snapshot.Store {
+	s, err := snapshot.Open(*storeDir)
+	if err != nil {
+		fatalln("open snapshot store failed", err)
+	}
+	return s
+}
+
+// openSnapshot returns the input stored as name, and its description.
+func openSnapshot(name string) (io.ReadCloser, snapshot.Snapshot, error) {
+	s := openStore()
+	meta, err := s.Get(name)
+	if err != nil {
+		return nil, meta, fmt.Errorf("snapshot %v: %v", name, err)
+	}
+	r, err := s.Open(name)
+	if err != nil {
+		return nil, meta, fmt.Errorf("snapshot %v: %v", name, err)
+	}
+	return r, meta, nil
+}
+
+// teeSnapshot returns input, copying what is read of it to a temporary
+// file for saveSnapshot.
+func teeSnapshot(input io.Reader) (io.Reader, error) {
+	f, err := ioutil.TempFile("", "go_dep_search")
+	if err != nil {
+		return nil, err
+	}
+	os.Remove(f.Name())
+	snapshotInput = f
+	return io.TeeReader(input, f), nil
+}
+
+// saveSnapshot stores the input of dg in the -store as *save.
+func saveSnapshot(dg *depgraph.DepGraph) {
+	defer snapshotInput.Close()
+	if _, err := snapshotInput.Seek(0, io.SeekStart); err != nil {
+		fatalln("save snapshot failed", err)
+	}
+	meta := snapshot.Snapshot{Name: *save, Time: time.Now(), Packages: len(dg.Packages()), Build: dg.Build()}
+	if *inputFormat != "golist" {
+		meta.Format = *inputFormat
+	}
+	store := openStore()
+	if err := store.Save(meta, snapshotInput); err != nil {
+		fatalln("save snapshot failed", err)
+	}
+	if err := store.SaveIDs(meta.Name, dg.StableIDs()); err != nil {
+		fatalln("save snapshot failed", err)
+	}
+	log.Printf("saved snapshot %v of %d packages", meta.Name, meta.Packages)
+}
+
+// printSnapshots lists the snapshots of the -store, oldest first.
+func printSnapshots() {
+	snapshots, err := openStore().List()
+	if err != nil {
+		fatalln("list snapshots failed", err)
+	}
+	for _, s := range snapshots {
+		line := fmt.Sprintf("%s\t%s\t%d packages", s.Name, s.Time.Format(time.RFC3339), s.Packages)
+		if b := s.Build.String(); b != "" {
+			line += "\t" + b
+		}
+		fmt.Println(line)
+	}
+}
+
+// reportSnapshotDiff prints the packages the input adds to and removes
+// from the snapshot *diffSnapshot.
+func reportSnapshotDiff(dg *depgraph.DepGraph) {
+	r, meta, err := openSnapshot(*diffSnapshot)
+	if err != nil {
+		fatalln(err)
+	}
+	defer r.Close()
+	old, err := readGraph(r, meta.Format, meta.Build)
+	if err != nil {
+		fail(exitParse, "load snapshot %v failed %v", meta.Name, err)
+	}
+	printDiff(old, dg)
+}
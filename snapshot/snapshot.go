@@ -0,0 +1,191 @@
+// Package snapshot keeps named, timestamped copies of go list -json
+// outputs in a local directory, so today's graph can be compared with the
+// graph of any release saved before.
+package snapshot
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Snapshot describes a stored graph.
+type Snapshot struct {
+	Name     string
+	Time     time.Time
+	Packages int                   // loaded packages
+	Format   string                `json:",omitempty"` // input format, see package loader, "" for golist
+	Build    depgraph.BuildProfile `json:",omitempty"`
+}
+
+// Store is a directory of snapshots: for each, NAME.json.gz holds the go
+// list -json output, or the input in Format, NAME.meta.json its Snapshot
+// and NAME.ids.json, if saved, the stable identifiers of its packages.
+type Store struct {
+	dir string
+}
+
+// ErrNotFound is returned for snapshots missing from the store.
+var ErrNotFound = errors.New("snapshot: not found")
+
+// Open returns the store in dir, creating dir if needed.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+func (s *Store) path(name, ext string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("snapshot: invalid name %q", name)
+	}
+	return filepath.Join(s.dir, name+ext), nil
+}
+
+// Save stores the go list -json output read from r as snapshot meta.Name.
+// Names are never reused: saving under a taken name fails.
+func (s *Store) Save(meta Snapshot, r io.Reader) (err error) {
+	data, err := s.path(meta.Name, ".json.gz")
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(data, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("snapshot: %v already exists", meta.Name)
+		}
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(data)
+		}
+	}()
+	zw := gzip.NewWriter(f)
+	_, err = io.Copy(zw, r)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(meta, "", "\t")
+	if err != nil {
+		return err
+	}
+	metaPath, _ := s.path(meta.Name, ".meta.json")
+	return ioutil.WriteFile(metaPath, b, 0644)
+}
+
+// List returns the stored snapshots, oldest first.
+func (s *Store) List() (snapshots []Snapshot, err error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.meta.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		var meta Snapshot
+		if err := json.Unmarshal(b, &meta); err != nil {
+			return nil, fmt.Errorf("%v: %v", p, err)
+		}
+		snapshots = append(snapshots, meta)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return
+}
+
+// Get returns the Snapshot of name.
+func (s *Store) Get(name string) (meta Snapshot, err error) {
+	p, err := s.path(name, ".meta.json")
+	if err != nil {
+		return
+	}
+	b, err := ioutil.ReadFile(p)
+	if os.IsNotExist(err) {
+		return meta, ErrNotFound
+	} else if err != nil {
+		return
+	}
+	err = json.Unmarshal(b, &meta)
+	return
+}
+
+// Open returns the go list -json output stored as name.
+func (s *Store) Open(name string) (io.ReadCloser, error) {
+	p, err := s.path(name, ".json.gz")
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return readCloser{zr, f}, nil
+}
+
+type readCloser struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (r readCloser) Close() error {
+	r.Reader.Close()
+	return r.f.Close()
+}
+
+// SaveIDs stores the depgraph.StableID of every package of snapshot name,
+// by import path, for external systems correlating packages across
+// snapshots without loading them.
+func (s *Store) SaveIDs(name string, ids map[string]string) error {
+	p, err := s.path(name, ".ids.json")
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(ids, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, b, 0644)
+}
+
+// IDs returns the identifiers saved by SaveIDs for snapshot name.
+func (s *Store) IDs(name string) (map[string]string, error) {
+	p, err := s.path(name, ".ids.json")
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(p)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	var ids map[string]string
+	err = json.Unmarshal(b, &ids)
+	return ids, err
+}
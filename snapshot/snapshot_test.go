@@ -0,0 +1,73 @@
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	input := `{"ImportPath": "cmd/a", "Name": "main", "Imports": ["fmt"], "Deps": ["fmt"]}`
+	if err := s.Save(Snapshot{Name: "v1.42", Time: day.Add(time.Hour), Packages: 1}, strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(Snapshot{Name: "v1.41", Time: day, Build: depgraph.BuildProfile{GOOS: "linux"}}, strings.NewReader("")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(Snapshot{Name: "v1.42"}, strings.NewReader("")); err == nil {
+		t.Error("expect error saving a taken name")
+	}
+	if err := s.Save(Snapshot{Name: "../x"}, strings.NewReader("")); err == nil {
+		t.Error("expect error for a name with a path")
+	}
+
+	list, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Name != "v1.41" || list[0].Build.GOOS != "linux" || list[1].Packages != 1 {
+		t.Error(list)
+	}
+	if meta, err := s.Get("v1.42"); err != nil || !meta.Time.Equal(day.Add(time.Hour)) {
+		t.Error(meta, err)
+	}
+	r, err := s.Open("v1.42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	dg, err := depgraph.LoadDeps(r)
+	if err != nil || !dg.IsMainPackage("cmd/a") {
+		t.Error("load snapshot failed", err)
+	}
+	if _, err := s.Open("v2"); err != ErrNotFound {
+		t.Error(err)
+	}
+	if _, err := s.Get("v2"); err != ErrNotFound {
+		t.Error(err)
+	}
+
+	if err := s.SaveIDs("v1.42", dg.StableIDs()); err != nil {
+		t.Fatal(err)
+	}
+	if ids, err := s.IDs("v1.42"); err != nil || ids["cmd/a"] != depgraph.StableID("cmd/a", "") {
+		t.Error(ids, err)
+	}
+	if _, err := s.IDs("v1.41"); err != ErrNotFound {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// listedPackage is what the -f template of a listed package sees.
+type listedPackage struct {
+	ImportPath string
+	Name       string // package name
+	Main       bool
+	Test       bool
+	Standard   bool
+	FirstParty bool
+	Module     *depgraph.Module
+	Imports    []string
+	Importers  []string
+	Deps       []string
+	Chain      []string          // chain the package was found through, if any
+	Owners     []string          // owners from -owners
+	ID         string            // see depgraph.StableID
+	Labels     map[string]string // labels from -labels
+}
+
+var listTemplate *template.Template
+
+// parseFormat parses the -f template, if any.
+func parseFormat() {
+	if *format == "" {
+		return
+	}
+	t, err := template.New("f").Parse(*format)
+	if err != nil {
+		fail(exitUsage, "parse -f template failed %v", err)
+	}
+	listTemplate = t
+}
+
+// printPackage prints the result p, found through chain if not nil, with
+// the -f template, or as line without one, unless -label doesn't select
+// its labels. With -groupby or -sort the result is held until
+// flushResults, with -count it is only counted.
+func printPackage(dg *depgraph.DepGraph, p string, chain []string, line string) {
+	if selector != nil && !selector.Matches(mainLabels.Of(p)) {
+		return
+	}
+	matches++
+	if *countOnly && *resultsBy == "" {
+		return
+	}
+	if listTemplate != nil && !*countOnly {
+		line = formatPackage(dg, p, chain)
+	}
+	if *resultsBy == "" && *sortBy == "" {
+		fmt.Println(line)
+		return
+	}
+	r := heldResult{Result: depgraph.Result{Package: p, Chain: chain}, dg: dg,
+		target: searchTarget, search: searchIndex, line: line}
+	if *resultsBy != "" {
+		r.group = resultGroup(dg, p)
+	}
+	held = append(held, r)
+}
+
+// formatPackage executes the -f template on p.
+func formatPackage(dg *depgraph.DepGraph, p string, chain []string) string {
+	var buf bytes.Buffer
+	err := listTemplate.Execute(&buf, listedPackage{
+		ImportPath: p,
+		Name:       dg.PackageName(p),
+		Main:       dg.IsMainPackage(p),
+		Test:       dg.IsTestPackage(p),
+		Standard:   dg.IsStandard(p),
+		FirstParty: dg.IsFirstParty(p),
+		Module:     dg.Module(p),
+		Imports:    dg.Imports(p),
+		Importers:  dg.Importers(p),
+		Deps:       dg.Deps(p),
+		Chain:      chain,
+		Owners:     codeOwners.Of(p),
+		ID:         dg.StableID(p),
+		Labels:     mainLabels.Of(p),
+	})
+	if err != nil {
+		fatalln("execute -f template failed", err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportUpgrade prints the blast radius of bumping the module named by
+// *upgrade, given as path or path@new_version.
+func reportUpgrade(dg *depgraph.DepGraph) {
+	path, newVersion := *upgrade, ""
+	if i := strings.LastIndex(path, "@"); i >= 0 {
+		path, newVersion = path[:i], path[i+1:]
+	}
+	pkgs := dg.PackagesOf(path)
+	if len(pkgs) == 0 {
+		fail(exitNotFound, "module %v not found", path)
+	}
+	current := dg.Module(pkgs[0]).Version
+	if newVersion != "" {
+		fmt.Printf("%s: %s -> %s\n", path, current, newVersion)
+	} else {
+		fmt.Printf("%s@%s\n", path, current)
+	}
+	direct, transitive := dg.ModuleUsers(path)
+	printUsers := func(title string, packages []string) {
+		fmt.Printf("%s (%d):\n", title, len(packages))
+		for _, p := range packages {
+			name := "\t"
+			if dg.IsMainPackage(p) {
+				name += "[main] "
+			}
+			fmt.Println(name + p)
+		}
+	}
+	printUsers("direct", direct)
+	printUsers("transitive", transitive)
+}
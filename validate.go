@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// reportConflicts prints the packages the input listed more than once
+// with different imports or deps, and what the last record changed.
+func reportConflicts(dg *depgraph.DepGraph) {
+	for _, c := range dg.Conflicts() {
+		var changes []string
+		for _, d := range []struct {
+			title          string
+			added, removed []string
+		}{
+			{"imports", c.ImportsAdded, c.ImportsRemoved},
+			{"deps", c.DepsAdded, c.DepsRemoved},
+		} {
+			var diff []string
+			for _, p := range d.added {
+				diff = append(diff, "+"+p)
+			}
+			for _, p := range d.removed {
+				diff = append(diff, "-"+p)
+			}
+			if len(diff) > 0 {
+				changes = append(changes, d.title+" "+strings.Join(diff, " "))
+			}
+		}
+		fmt.Printf("%s: %s\n", c.Package, strings.Join(changes, ", "))
+	}
+}
+
+// reportDangling prints the packages referenced but missing from the
+// input, with the packages referencing them.
+func reportDangling(dg *depgraph.DepGraph) {
+	missing := dg.Dangling()
+	names := make([]string, 0, len(missing))
+	for p := range missing {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	for _, p := range names {
+		fmt.Printf("%s: referenced by %s\n", p, strings.Join(missing[p], " "))
+	}
+}
+
+// validateReport is the -validate json document.
+type validateReport struct {
+	Errors   int
+	Warnings int
+	Issues   []depgraph.Issue
+}
+
+// reportValidate runs every consistency check of the graph and prints the
+// issues as text or JSON per *validateFormat, exiting with status 1 if
+// one is an error.
+func reportValidate(dg *depgraph.DepGraph) {
+	r := validateReport{Issues: dg.Validate()}
+	for _, i := range r.Issues {
+		if i.Severity == depgraph.SeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	switch *validateFormat {
+	case "text":
+		for _, i := range r.Issues {
+			fmt.Println(i)
+		}
+		log.Printf("%d errors, %d warnings", r.Errors, r.Warnings)
+	case "json":
+		if r.Issues == nil {
+			r.Issues = []depgraph.Issue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			fatalln(err)
+		}
+	default:
+		fail(exitUsage, "unknown -validate format %q, supported: text,json", *validateFormat)
+	}
+	if r.Errors > 0 {
+		fail(exitViolation, "%d errors in the input", r.Errors)
+	}
+}
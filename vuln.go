@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/issues"
+	"github.com/ma6174/go_dep_search/vuln"
+)
+
+func loadVulns(dg *depgraph.DepGraph) (entries []vuln.Entry) {
+	if *vulnFile != "" {
+		f, err := os.Open(*vulnFile)
+		if err != nil {
+			fail(exitParse, "open vuln file failed %v", err)
+		}
+		defer f.Close()
+		entries, err = vuln.Load(f)
+		if err != nil {
+			fail(exitParse, "load vuln file failed %v", err)
+		}
+	}
+	if *osv {
+		client := &http.Client{Timeout: 30 * time.Second}
+		for _, m := range dg.Modules() {
+			if m.Main {
+				continue
+			}
+			vulns, err := vuln.Query(client, m.Path, m.Version)
+			if err != nil {
+				fatalln("query osv.dev failed", err)
+			}
+			entries = append(entries, vulns...)
+		}
+	}
+	return
+}
+
+func reportVulns(dg *depgraph.DepGraph) {
+	findings := vuln.Match(dg, loadVulns(dg))
+	if len(findings) == 0 {
+		log.Println("no known vulnerabilities found")
+		return
+	}
+	if *issueFormat != "" {
+		var items []issues.Item
+		for i := range findings {
+			items = append(items, findingItems(&findings[i])...)
+		}
+		writeIssues("vulnerable dependency", items)
+		return
+	}
+	for _, f := range findings {
+		fmt.Println(f.String())
+		if len(f.Chains) == 0 {
+			fmt.Println("\tnot used by any main package")
+		}
+		for _, chain := range f.Chains {
+			fmt.Println("\t" + formatChain(dg, chain))
+		}
+	}
+}
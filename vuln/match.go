@@ -0,0 +1,100 @@
+package vuln
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// Finding is a vulnerable package present in the graph and the main
+// packages exposed to it.
+type Finding struct {
+	Entry   *Entry
+	Package string
+	Module  *depgraph.Module // nil for the standard library or GOPATH mode
+	Chains  [][]string       // main -> ... -> Package, one per exposed main
+}
+
+// Match returns a finding for every package of g an entry affects, sorted
+// by entry ID and package. Entries listing affected import paths match
+// those packages; others match every package of the affected module.
+// Packages whose module version is unknown are assumed to be affected.
+// The chains of all findings are searched in one SearchChainBulk.
+func Match(g *depgraph.DepGraph, entries []Entry) (findings []Finding) {
+	for i := range entries {
+		e := &entries[i]
+		seen := make(map[string]bool)
+		for j := range e.Affected {
+			a := &e.Affected[j]
+			if a.Package.Ecosystem != "" && a.Package.Ecosystem != "Go" {
+				continue
+			}
+			for _, p := range affectedPackages(g, a) {
+				if seen[p] {
+					continue
+				}
+				m := g.Module(p)
+				version := ""
+				if m != nil {
+					version = m.Version
+					if m.Replace != nil && m.Replace.Version != "" {
+						version = m.Replace.Version
+					}
+				}
+				if !a.Affects(version) {
+					continue
+				}
+				seen[p] = true
+				findings = append(findings, Finding{Entry: e, Package: p, Module: m})
+			}
+		}
+	}
+	packages := make([]string, len(findings))
+	for i, f := range findings {
+		packages[i] = f.Package
+	}
+	chains := g.SearchChainBulk(packages, 0)
+	for i := range findings {
+		findings[i].Chains = chains[findings[i].Package]
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Entry.ID != findings[j].Entry.ID {
+			return findings[i].Entry.ID < findings[j].Entry.ID
+		}
+		return findings[i].Package < findings[j].Package
+	})
+	return
+}
+
+func affectedPackages(g *depgraph.DepGraph, a *Affected) (packages []string) {
+	if imports := a.EcosystemSpecific.Imports; len(imports) > 0 {
+		for _, imp := range imports {
+			if g.Exists(imp.Path) {
+				packages = append(packages, imp.Path)
+			}
+		}
+		return
+	}
+	return g.PackagesOf(a.Package.Name)
+}
+
+// String formats the finding header, eg:
+// GO-2021-0113 golang.org/x/text/language (golang.org/x/text@v0.3.5): summary
+func (f *Finding) String() string {
+	var b strings.Builder
+	b.WriteString(f.Entry.ID)
+	b.WriteString(" ")
+	b.WriteString(f.Package)
+	if f.Module != nil {
+		b.WriteString(" (" + f.Module.Path)
+		if f.Module.Version != "" {
+			b.WriteString("@" + f.Module.Version)
+		}
+		b.WriteString(")")
+	}
+	if f.Entry.Summary != "" {
+		b.WriteString(": " + f.Entry.Summary)
+	}
+	return b.String()
+}
@@ -0,0 +1,149 @@
+// Package vuln maps OSV vulnerability entries, read from files, from
+// govulncheck -json output or from the osv.dev API, onto a dependency graph.
+package vuln
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ma6174/go_dep_search/semver"
+)
+
+// Entry is the subset of an OSV entry used here, see
+// https://ossf.github.io/osv-schema/.
+type Entry struct {
+	ID       string     `json:"id"`
+	Summary  string     `json:"summary"`
+	Aliases  []string   `json:"aliases"`
+	Affected []Affected `json:"affected"`
+}
+
+type Affected struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string `json:"type"`
+		Events []struct {
+			Introduced   string `json:"introduced"`
+			Fixed        string `json:"fixed"`
+			LastAffected string `json:"last_affected"`
+		} `json:"events"`
+	} `json:"ranges"`
+	EcosystemSpecific struct {
+		Imports []struct {
+			Path    string   `json:"path"`
+			Symbols []string `json:"symbols"`
+		} `json:"imports"`
+	} `json:"ecosystem_specific"`
+}
+
+// Affects reports whether version is inside one of the SEMVER ranges. An
+// empty version is unknown and always counts as affected.
+func (a *Affected) Affects(version string) bool {
+	if version == "" || len(a.Ranges) == 0 {
+		return true
+	}
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" {
+			continue
+		}
+		affected := false
+		for _, e := range r.Events {
+			switch {
+			case e.Introduced != "":
+				if e.Introduced == "0" || semver.Compare(version, e.Introduced) >= 0 {
+					affected = true
+				}
+			case e.Fixed != "":
+				if semver.Compare(version, e.Fixed) >= 0 {
+					affected = false
+				}
+			case e.LastAffected != "":
+				if semver.Compare(version, e.LastAffected) > 0 {
+					affected = false
+				}
+			}
+		}
+		if affected {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads OSV entries from r, which may hold a single entry, a JSON
+// array of entries, a stream of entries, or the message stream written by
+// govulncheck -json (only its "osv" messages are used).
+func Load(r io.Reader) (entries []Entry, err error) {
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err = dec.Decode(&raw); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+		raw = bytes.TrimSpace(raw)
+		if len(raw) > 0 && raw[0] == '[' {
+			var list []Entry
+			if err = json.Unmarshal(raw, &list); err != nil {
+				return
+			}
+			entries = append(entries, list...)
+			continue
+		}
+		var msg struct {
+			Entry
+			OSV *Entry `json:"osv"`
+		}
+		if err = json.Unmarshal(raw, &msg); err != nil {
+			return
+		}
+		if msg.OSV != nil {
+			entries = append(entries, *msg.OSV)
+		} else if msg.ID != "" {
+			entries = append(entries, msg.Entry)
+		}
+	}
+}
+
+// QueryURL is the osv.dev endpoint used by Query.
+var QueryURL = "https://api.osv.dev/v1/query"
+
+// Query asks osv.dev for the entries affecting version of the Go module
+// path.
+func Query(client *http.Client, path, version string) ([]Entry, error) {
+	var req struct {
+		Package struct {
+			Name      string `json:"name"`
+			Ecosystem string `json:"ecosystem"`
+		} `json:"package"`
+		Version string `json:"version,omitempty"`
+	}
+	req.Package.Name = path
+	req.Package.Ecosystem = "Go"
+	req.Version = version
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Post(QueryURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("osv query %s@%s: %s", path, version, resp.Status)
+	}
+	var result struct {
+		Vulns []Entry `json:"vulns"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result.Vulns, err
+}
@@ -0,0 +1,52 @@
+package vuln
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+const entries = `
+{"config": {"protocol_version": "v1.0.0"}}
+{"osv": {"id": "GO-2021-0113", "summary": "Out-of-bounds read in golang.org/x/text/language",
+  "affected": [{"package": {"name": "golang.org/x/text", "ecosystem": "Go"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.7"}]}],
+    "ecosystem_specific": {"imports": [{"path": "golang.org/x/text/language"}]}}]}}
+[{"id": "GO-2099-0001", "affected": [{"package": {"name": "example.com/lib", "ecosystem": "Go"},
+  "ranges": [{"type": "SEMVER", "events": [{"introduced": "1.2.0"}]}]}]}]
+`
+
+func TestMatch(t *testing.T) {
+	list, err := Load(strings.NewReader(entries))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatal("expect 2 entries, real:", len(list))
+	}
+	dg := &depgraph.DepGraph{}
+	text := &depgraph.Module{Path: "golang.org/x/text", Version: "v0.3.6"}
+	lib := &depgraph.Module{Path: "example.com/lib", Version: "v1.1.0"}
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/cmd/a", Name: "main",
+		Imports: []string{"golang.org/x/text/language", "example.com/lib"},
+		Deps:    []string{"golang.org/x/text/language", "example.com/lib"}})
+	dg.Add(depgraph.DepInfo{ImportPath: "golang.org/x/text/language", Name: "language", Module: text})
+	dg.Add(depgraph.DepInfo{ImportPath: "example.com/lib", Name: "lib", Module: lib})
+	findings := Match(dg, list)
+	if len(findings) != 1 || findings[0].Package != "golang.org/x/text/language" {
+		t.Fatal("result error", findings)
+	}
+	if len(findings[0].Chains) != 1 || findings[0].Chains[0][1] != "example.com/cmd/a" {
+		t.Error("result error", findings[0].Chains)
+	}
+	if !strings.HasPrefix(findings[0].String(), "GO-2021-0113 golang.org/x/text/language (golang.org/x/text@v0.3.6)") {
+		t.Error(findings[0].String())
+	}
+	text.Version = "v0.3.7"
+	lib.Version = "v1.2.0"
+	findings = Match(dg, list)
+	if len(findings) != 1 || findings[0].Entry.ID != "GO-2099-0001" {
+		t.Error("result error", findings)
+	}
+}
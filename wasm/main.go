@@ -0,0 +1,58 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Command wasm is the query engine compiled to WebAssembly, for web pages
+// to search a graph in the browser, without a backend, eg:
+//
+//	GOOS=js GOARCH=wasm go build -o go_dep_search.wasm ./wasm
+//
+// It sets the global goDepSearch object, whose load(text) loads go list
+// -json output and returns null, or the error message, and whose
+// call(request) answers a JSON-RPC request of -rpc, eg:
+// {"method": "Graph.SearchMain", "params": [{"Package": "net/http"}], "id": 1},
+// with the JSON-RPC response. See index.html.
+package main
+
+import (
+	"strings"
+	"syscall/js"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+	"github.com/ma6174/go_dep_search/server"
+)
+
+var handler *server.Handler
+
+func load(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 {
+		return "usage: goDepSearch.load(goListJSON)"
+	}
+	dg, err := depgraph.LoadDeps(strings.NewReader(args[0].String()))
+	if err != nil {
+		return err.Error()
+	}
+	h, err := server.NewHandler(server.NewService(dg))
+	if err != nil {
+		return err.Error()
+	}
+	handler = h
+	return nil
+}
+
+func call(this js.Value, args []js.Value) interface{} {
+	if len(args) != 1 {
+		return `{"id":null,"result":null,"error":"usage: goDepSearch.call(request)"}`
+	}
+	if handler == nil {
+		return `{"id":null,"result":null,"error":"no graph loaded, see goDepSearch.load"}`
+	}
+	return string(handler.Handle([]byte(args[0].String())))
+}
+
+func main() {
+	js.Global().Set("goDepSearch", js.ValueOf(map[string]interface{}{
+		"load": js.FuncOf(load),
+		"call": js.FuncOf(call),
+	}))
+	select {}
+}
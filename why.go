@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/ma6174/go_dep_search/depgraph"
+)
+
+// explainModule prints, per main package, the import chain that makes
+// the build require the module named by *whyModule.
+func explainModule(dg *depgraph.DepGraph) {
+	chains := dg.WhyModule(*whyModule)
+	if len(chains) == 0 {
+		log.Printf("module %v not needed by any main package", *whyModule)
+		return
+	}
+	for _, chain := range chains {
+		fmt.Println(formatChain(dg, chain))
+	}
+}
+
+// reportMajorConflicts prints the main packages depending on several
+// major versions of a module, with the chain pulling in each.
+func reportMajorConflicts(dg *depgraph.DepGraph) {
+	conflicts := dg.MajorConflicts()
+	if len(conflicts) == 0 {
+		log.Println("no module is used with several major versions")
+		return
+	}
+	for _, c := range conflicts {
+		fmt.Printf("%s uses %d major versions of %s:\n", c.Main, len(c.Chains), c.Module)
+		paths := make([]string, 0, len(c.Chains))
+		for p := range c.Chains {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+		for _, p := range paths {
+			fmt.Println("\t" + formatChain(dg, c.Chains[p]))
+		}
+	}
+}